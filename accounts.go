@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// currentAccountFile records the account chosen with `auth switch`
+const currentAccountFile = "current-account"
+
+// defaultAccount is the unnamed account whose session lives directly in the
+// auth cache and whose credentials are CSES_USERNAME and CSES_PASSWORD
+const defaultAccount = "default"
+
+var accountNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// GetAccount returns the account CSES is used as: -account, else the one
+// chosen with `auth switch`. It is empty for the default account.
+func (c *Config) GetAccount() string {
+	account := c.Account
+	if account == "" {
+		data, err := os.ReadFile(filepath.Join(c.GetAuthCacheDir(), currentAccountFile))
+		if err != nil {
+			return ""
+		}
+		account = strings.TrimSpace(string(data))
+	}
+	if account == defaultAccount || !accountNamePattern.MatchString(account) {
+		return ""
+	}
+	return account
+}
+
+// accountSessionDir is where the sessions of an account are stored
+func (c *Config) accountSessionDir(account string) string {
+	if account == "" {
+		return c.GetAuthCacheDir()
+	}
+	return filepath.Join(c.GetAuthCacheDir(), "accounts", account)
+}
+
+// credentialVars returns the environment variables holding the username and
+// password of an account: CSES_USERNAME_CONTEST for the account "contest"
+func credentialVars(account string) (string, string) {
+	if account == "" {
+		return "CSES_USERNAME", "CSES_PASSWORD"
+	}
+	suffix := strings.ToUpper(strings.ReplaceAll(account, "-", "_"))
+	return "CSES_USERNAME_" + suffix, "CSES_PASSWORD_" + suffix
+}
+
+// handleAuthSwitch implements `auth switch <name>`: later commands use the
+// account's own session and credentials until another switch. `default`
+// returns to CSES_USERNAME/CSES_PASSWORD.
+func handleAuthSwitch(config *Config, name string) error {
+	if !accountNamePattern.MatchString(name) {
+		return withExitCode(ExitUsageError, fmt.Errorf("invalid account name %q (letters, digits, - and _)", name))
+	}
+
+	if err := os.MkdirAll(config.GetAuthCacheDir(), 0700); err != nil {
+		return fmt.Errorf("failed to create auth directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(config.GetAuthCacheDir(), currentAccountFile), []byte(name+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to switch account: %w", err)
+	}
+	config.Account = name
+
+	green.Printf("✅ Switched to account %s\n", name)
+	userVar, passVar := credentialVars(config.GetAccount())
+	if _, err := os.Stat(config.GetSessionFile()); err != nil {
+		fmt.Printf("   No session yet: set %s and %s, then run `%s auth`\n", userVar, passVar, AppName)
+	}
+	return nil
+}
+
+// handleAuthWhoami implements `auth whoami`: the current account, who its
+// session is logged in as, and the other accounts with a stored session
+func handleAuthWhoami(config *Config) error {
+	account := config.GetAccount()
+	name := account
+	if name == "" {
+		name = defaultAccount
+	}
+
+	auth := NewCSESAuth(config)
+	userVar, passVar := credentialVars(account)
+	if err := auth.LoadSession(); err != nil {
+		fmt.Printf("Account: %s (not logged in; credentials from %s and %s)\n", name, userVar, passVar)
+	} else {
+		session := auth.sessionData
+		status := "valid"
+		if !auth.HasValidSession() {
+			status = "expired"
+		}
+		fmt.Printf("Account: %s, logged in as %s\n", name, session.Username)
+		fmt.Printf("Session: %s, created %s ago\n", status, time.Since(session.CreatedAt).Round(time.Minute))
+	}
+
+	var others []string
+	if account != "" {
+		others = append(others, defaultAccount)
+	}
+	entries, _ := os.ReadDir(filepath.Join(config.GetAuthCacheDir(), "accounts"))
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != account {
+			others = append(others, entry.Name())
+		}
+	}
+	if len(others) > 0 {
+		sort.Strings(others)
+		fmt.Printf("Other accounts: %s (use `%s auth switch <name>`)\n", strings.Join(others, ", "), AppName)
+	}
+	return nil
+}
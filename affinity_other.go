@@ -0,0 +1,15 @@
+//go:build !linux
+
+package main
+
+import (
+	"os/exec"
+)
+
+// cpuPinningSupported reports whether -isolate-timing can pin the solution to a core
+const cpuPinningSupported = false
+
+// startPinned starts cmd normally; CPU pinning is only implemented on Linux
+func startPinned(cmd *exec.Cmd) error {
+	return cmd.Start()
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadAliases reads the friendly problem names set with `alias add`
+func LoadAliases(config *Config) (map[string]string, error) {
+	data, err := os.ReadFile(config.GetAliasesPath())
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aliases: %w", err)
+	}
+
+	aliases := make(map[string]string)
+	if err := yaml.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", config.GetAliasesPath(), err)
+	}
+	return aliases, nil
+}
+
+func saveAliases(config *Config, aliases map[string]string) error {
+	data, err := yaml.Marshal(aliases)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(config.GetAliasesPath()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(config.GetAliasesPath(), data, 0644)
+}
+
+// resolveAliases replaces aliases in a comma separated list of problems with
+// their IDs, leaving IDs and unknown names as they are
+func resolveAliases(config *Config, problems string) string {
+	if problems == "" {
+		return problems
+	}
+
+	aliases, err := LoadAliases(config)
+	if err != nil {
+		yellow.Printf("⚠️  Ignoring problem aliases: %v\n", err)
+		return problems
+	}
+
+	parts := strings.Split(problems, ",")
+	for i, part := range parts {
+		if id, ok := aliases[strings.TrimSpace(part)]; ok {
+			parts[i] = id
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// handleAlias implements `alias add <name> <id>`, `alias list` and `alias remove <name>`
+func handleAlias(config *Config, args []string) error {
+	usage := withExitCode(ExitUsageError, fmt.Errorf("usage: alias add <name> <problem id> | alias list | alias remove <name>"))
+	if len(args) == 0 {
+		return usage
+	}
+
+	aliases, err := LoadAliases(config)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		if len(aliases) == 0 {
+			fmt.Printf("No aliases yet; add one with `%s alias add two-sets 1092`\n", AppName)
+			return nil
+		}
+		names := make([]string, 0, len(aliases))
+		for name := range aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%-24s %s\n", name, aliases[name])
+		}
+		return nil
+
+	case args[0] == "add" && len(args) == 3:
+		name, id := args[1], args[2]
+		if _, err := strconv.Atoi(name); err == nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("alias %q would hide problem %s; pick a name that is not a number", name, name))
+		}
+		if strings.ContainsAny(name, ", ") {
+			return withExitCode(ExitUsageError, fmt.Errorf("alias %q cannot contain commas or spaces", name))
+		}
+		if _, err := strconv.Atoi(id); err != nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("invalid problem ID %s", id))
+		}
+
+		if previous, ok := aliases[name]; ok && previous != id {
+			yellow.Printf("⚠️  %s pointed to %s before\n", name, previous)
+		}
+		aliases[name] = id
+		if err := saveAliases(config, aliases); err != nil {
+			return fmt.Errorf("failed to save aliases: %w", err)
+		}
+		green.Printf("✅ %s → %s\n", name, id)
+		return nil
+
+	case args[0] == "remove" && len(args) == 2:
+		if _, ok := aliases[args[1]]; !ok {
+			return withExitCode(ExitUsageError, fmt.Errorf("no alias called %q", args[1]))
+		}
+		delete(aliases, args[1])
+		if err := saveAliases(config, aliases); err != nil {
+			return fmt.Errorf("failed to save aliases: %w", err)
+		}
+		green.Printf("✅ Removed %s\n", args[1])
+		return nil
+	}
+
+	return usage
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// handleArchive copies the accepted solution of a problem into
+// <dir>/<category>/<id>-<name>/ with a README of its verdict and timings
+func handleArchive(config *Config, args []string, dir string, gitAdd bool) error {
+	problemID := config.ProblemID
+	if len(args) == 1 {
+		problemID = resolveAliases(config, args[0])
+	}
+	if problemID == "" || len(args) > 1 {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: archive <problem> [-file=solution.go] [-archive-dir=solutions] [-git-add]"))
+	}
+
+	record, err := acceptedRun(config, problemID)
+	if err != nil {
+		return err
+	}
+
+	source, err := os.ReadFile(record.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read solution: %w", err)
+	}
+	if hash, err := hashFile(record.FilePath); err != nil || hash != record.SourceHash {
+		return withExitCode(ExitUsageError, fmt.Errorf("%s changed since its accepted run on %s; run the tests again first",
+			record.FilePath, record.StartedAt.Local().Format("2006-01-02 15:04")))
+	}
+
+	problem := lookupProblem(config, problemID)
+	target := filepath.Join(dir, slugify(problem.Category), problemID)
+	if slug := slugify(problem.Name); slug != "" {
+		target += "-" + slug
+	}
+
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	if err := os.WriteFile(filepath.Join(target, "main.go"), source, 0644); err != nil {
+		return fmt.Errorf("failed to write solution: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(target, "README.md"), []byte(archiveReadme(config, problem, record)), 0644); err != nil {
+		return fmt.Errorf("failed to write README: %w", err)
+	}
+	green.Printf("📦 Archived %s to %s\n", record.FilePath, target)
+
+	if gitAdd {
+		if output, err := exec.Command("git", "add", target).CombinedOutput(); err != nil {
+			return fmt.Errorf("git add failed: %v: %s", err, strings.TrimSpace(string(output)))
+		}
+		cyan.Printf("➕ Staged %s\n", target)
+	}
+	return nil
+}
+
+// acceptedRun returns the most recent run of problemID in which every test
+// passed, of -file if it is given
+func acceptedRun(config *Config, problemID string) (*RunRecord, error) {
+	file := ""
+	if config.FilePath != "" {
+		var err error
+		if file, err = filepath.Abs(config.FilePath); err != nil {
+			return nil, err
+		}
+	}
+
+	record, err := NewHistoryStore(config).Latest(func(record *RunRecord) bool {
+		return record.ProblemID == problemID && record.AllPassed() && (file == "" || record.FilePath == file)
+	})
+	if err != nil || record != nil {
+		return record, err
+	}
+	return nil, withExitCode(ExitUsageError, fmt.Errorf("no accepted run of problem %s in the history; run its tests first", problemID))
+}
+
+// lookupProblem returns the name and category of a problem from the problem
+// list, or just its ID when the list cannot be loaded
+func lookupProblem(config *Config, problemID string) ProblemInfo {
+	if index, err := LoadProblemsetIndex(config, NewCSESAuth(config)); err == nil {
+		if problem, ok := index.Find(problemID); ok {
+			return problem
+		}
+	} else {
+		yellow.Printf("⚠️  Problem list unavailable (%v)\n", err)
+	}
+	return ProblemInfo{ID: problemID, Category: "Uncategorized"}
+}
+
+func archiveReadme(config *Config, problem ProblemInfo, record *RunRecord) string {
+	var b strings.Builder
+	title := problem.ID
+	if problem.Name != "" {
+		title += " - " + problem.Name
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "%s/problemset/task/%s\n\n", config.GetBaseURL(), problem.ID)
+
+	var slowest TestRecord
+	var peak int64
+	for _, test := range record.Tests {
+		if test.Duration > slowest.Duration {
+			slowest = test
+		}
+		if test.MemoryUsage > peak {
+			peak = test.MemoryUsage
+		}
+	}
+
+	fmt.Fprintf(&b, "| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Verdict | Accepted (%d/%d tests) |\n", record.Passed, record.Total)
+	fmt.Fprintf(&b, "| Tested | %s |\n", record.StartedAt.Local().Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "| Slowest test | %.2fms (test %d) |\n", slowest.Duration, slowest.Number)
+	fmt.Fprintf(&b, "| Peak memory | %s |\n", formatBytes(peak))
+	fmt.Fprintf(&b, "| Compiler | %s |\n", compilerLabel(record.Compiler))
+	fmt.Fprintf(&b, "| Source hash | `%s` |\n", shortHash(record.SourceHash))
+	return b.String()
+}
+
+// slugify turns "Dynamic Programming" into "dynamic-programming"
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SaveFailedArtifacts writes input, expected and actual output of every failed
+// test to <dir>/<problem>/<test>/ so a failure can be re-run by hand. Artifacts
+// from earlier runs of the problem are removed first.
+func SaveFailedArtifacts(config *Config, results []TestResult) (string, int, error) {
+	problemDir := filepath.Join(config.FailedDir, config.ProblemID)
+	if err := os.RemoveAll(problemDir); err != nil {
+		return "", 0, fmt.Errorf("failed to clear old artifacts: %w", err)
+	}
+
+	saved := 0
+	for _, result := range results {
+		if result.Passed || result.Verdict == VerdictSkipped {
+			continue
+		}
+
+		testDir := filepath.Join(problemDir, strconv.Itoa(result.TestNumber))
+		if err := os.MkdirAll(testDir, 0755); err != nil {
+			return "", saved, fmt.Errorf("failed to create %s: %w", testDir, err)
+		}
+
+		input, err := os.ReadFile(result.InputFile)
+		if err != nil {
+			return "", saved, fmt.Errorf("failed to read input of test %d: %w", result.TestNumber, err)
+		}
+
+		files := map[string]string{
+			"input.txt":    string(input),
+			"expected.txt": result.ExpectedOutput,
+			"actual.txt":   result.ActualOutput,
+		}
+		if result.Error != "" {
+			files["error.txt"] = result.Error + "\n"
+		}
+
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(testDir, name), []byte(content), 0644); err != nil {
+				return "", saved, fmt.Errorf("failed to write %s: %w", name, err)
+			}
+		}
+
+		saved++
+	}
+
+	return problemDir, saved, nil
+}
+
+// dumpedOutputPath is where -dump-output-dir keeps the full output of a failed test
+func dumpedOutputPath(config *Config, testNumber int) string {
+	return filepath.Join(config.DumpOutputDir, config.ProblemID, strconv.Itoa(testNumber)+".actual.txt")
+}
+
+// DumpFailedOutputs writes the complete stdout (and stderr, if any) of every
+// failed test under -dump-output-dir, however much the console truncates it.
+// Dumps from earlier runs of the problem are removed first.
+func DumpFailedOutputs(config *Config, results []TestResult) (int, error) {
+	problemDir := filepath.Join(config.DumpOutputDir, config.ProblemID)
+	if err := os.RemoveAll(problemDir); err != nil {
+		return 0, fmt.Errorf("failed to clear old output dumps: %w", err)
+	}
+
+	dumped := 0
+	for _, result := range results {
+		if result.Passed || result.Verdict == VerdictSkipped {
+			continue
+		}
+
+		if err := os.MkdirAll(problemDir, 0755); err != nil {
+			return dumped, fmt.Errorf("failed to create %s: %w", problemDir, err)
+		}
+
+		path := dumpedOutputPath(config, result.TestNumber)
+		if err := os.WriteFile(path, []byte(result.ActualOutput), 0644); err != nil {
+			return dumped, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+
+		if result.Stderr != "" {
+			stderrPath := strings.TrimSuffix(path, ".actual.txt") + ".stderr.txt"
+			if err := os.WriteFile(stderrPath, []byte(result.Stderr), 0644); err != nil {
+				return dumped, fmt.Errorf("failed to write %s: %w", stderrPath, err)
+			}
+		}
+
+		dumped++
+	}
+
+	return dumped, nil
+}
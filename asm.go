@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// symbolPattern matches the header of a function in -S output, e.g. "main.main STEXT size=319 ..."
+	symbolPattern = regexp.MustCompile(`^(\S+) STEXT\b`)
+
+	// instructionPattern matches an instruction line, e.g. "\t0x0000 00000 (/tmp/sol.go:5)\tTEXT\tmain.main(SB)..."
+	instructionPattern = regexp.MustCompile(`^\s+0x[0-9a-f]+ \d+ \((.+):(\d+)\)\s+(.*)$`)
+
+	// boundsCheckPattern matches the panics the compiler emits for index and slice bounds checks
+	boundsCheckPattern = regexp.MustCompile(`CALL\s+runtime\.(panicIndex|panicSlice|panicBounds|goPanicIndex|goPanicSlice)`)
+)
+
+// asmFunction is the assembly of one function defined in the solution
+type asmFunction struct {
+	Name         string
+	Lines        []asmLine
+	BoundsChecks int
+}
+
+type asmLine struct {
+	File        string
+	Line        string
+	Instruction string
+}
+
+// handleAsm prints the gc assembly of the solution's functions, optionally only
+// those whose name matches the pattern in args, with bounds-check panics marked
+func handleAsm(config *Config, args []string) error {
+	if err := validateSolutionFile(config); err != nil {
+		return withExitCode(ExitUsageError, err)
+	}
+	if config.GetCompiler() != CompilerGC {
+		return withExitCode(ExitUsageError, fmt.Errorf("asm needs the %s compiler", CompilerGC))
+	}
+	if len(args) > 1 {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: asm -file=solution.go [function pattern]"))
+	}
+
+	var filter *regexp.Regexp
+	if len(args) == 1 {
+		var err error
+		if filter, err = regexp.Compile(args[0]); err != nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("invalid function pattern: %w", err))
+		}
+	}
+
+	output, err := NewGoCompiler(config).Assembly()
+	if err != nil {
+		return err
+	}
+
+	functions, err := solutionAssembly(config, output)
+	if err != nil {
+		return err
+	}
+
+	shown := 0
+	for _, function := range functions {
+		if filter != nil && !filter.MatchString(function.Name) {
+			continue
+		}
+		shown++
+		displayAsmFunction(function)
+	}
+
+	if shown == 0 {
+		yellow.Println("⚠️  No matching functions (small functions may have been inlined into their callers)")
+	}
+
+	return nil
+}
+
+// Assembly compiles the solution with -gcflags=-S and returns the compiler's
+// listing. The binary itself is discarded.
+func (c *GoCompiler) Assembly() (string, error) {
+	gcflags := "-S"
+	if !c.config.Optimize {
+		gcflags += " -N -l"
+	}
+
+	dir, target := c.buildTarget()
+	args := []string{"build", "-o", os.DevNull, "-gcflags=" + gcflags}
+	args = append(args, c.config.GetTagFlags()...)
+	cmd := exec.Command("go", append(args, target)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), c.config.GetBuildEnv()...)
+
+	if c.config.Verbose {
+		yellow.Printf("🔨 Compiling: %s\n", cmd.String())
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		compileErr := newCompileError(err, string(output))
+		if len(compileErr.Diagnostics) > 0 {
+			displayCompileError(c.config, compileErr)
+		}
+		return "", withExitCode(ExitCompileError, fmt.Errorf("compilation failed: %w", compileErr))
+	}
+
+	return string(output), nil
+}
+
+// solutionAssembly extracts the functions whose code comes from the solution's
+// directory, dropping data symbols and PCDATA/FUNCDATA annotations
+func solutionAssembly(config *Config, output string) ([]asmFunction, error) {
+	dir, err := solutionDir(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var functions []asmFunction
+	var current *asmFunction
+	inSolution := false
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if !strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, " ") {
+			current = nil
+			if matches := symbolPattern.FindStringSubmatch(line); matches != nil {
+				functions = append(functions, asmFunction{Name: matches[1]})
+				current = &functions[len(functions)-1]
+				inSolution = false
+			}
+			continue
+		}
+
+		matches := instructionPattern.FindStringSubmatch(line)
+		if current == nil || matches == nil {
+			continue
+		}
+
+		file, number, instruction := matches[1], matches[2], strings.Join(strings.Fields(matches[3]), " ")
+
+		// The TEXT line comes first and tells where the function is defined
+		if strings.HasPrefix(instruction, "TEXT ") {
+			inSolution = filepath.Dir(file) == dir
+		}
+		if !inSolution || strings.HasPrefix(instruction, "PCDATA") || strings.HasPrefix(instruction, "FUNCDATA") {
+			continue
+		}
+
+		if boundsCheckPattern.MatchString(instruction) {
+			current.BoundsChecks++
+		}
+		current.Lines = append(current.Lines, asmLine{File: filepath.Base(file), Line: number, Instruction: instruction})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read assembly: %w", err)
+	}
+
+	var result []asmFunction
+	for _, function := range functions {
+		if len(function.Lines) > 0 {
+			result = append(result, function)
+		}
+	}
+	return result, nil
+}
+
+func displayAsmFunction(function asmFunction) {
+	cyan.Printf("\n⚙️  %s", function.Name)
+	if function.BoundsChecks > 0 {
+		yellow.Printf(" (%d bounds check(s))", function.BoundsChecks)
+	} else {
+		green.Printf(" (no bounds checks)")
+	}
+	fmt.Println()
+
+	width := 0
+	for _, line := range function.Lines {
+		if n := len(line.File) + len(line.Line) + 1; n > width {
+			width = n
+		}
+	}
+
+	for _, line := range function.Lines {
+		position := fmt.Sprintf("%-*s", width, line.File+":"+line.Line)
+		if boundsCheckPattern.MatchString(line.Instruction) {
+			red.Printf("   %s  %s\n", position, line.Instruction)
+		} else {
+			fmt.Printf("   %s  %s\n", position, line.Instruction)
+		}
+	}
+}
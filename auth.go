@@ -1,9 +1,14 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
@@ -11,9 +16,30 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
+// ErrSessionExpired is returned when CSES no longer accepts the stored session
+var ErrSessionExpired = errors.New("session expired, requires re-authentication")
+
+// ErrSessionMismatch is returned when the stored session is logged in as
+// another user than the one it was saved for, or than the credentials name
+var ErrSessionMismatch = errors.New("session belongs to another user")
+
+// ErrNotModified is returned by a conditional test download when the cached archive is current
+var ErrNotModified = errors.New("test archive not modified")
+
+// Login failure classes, so callers can tell the user what to fix
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrLoginBlocked       = errors.New("login blocked by CSES (captcha or rate limit), log in through a browser and retry later")
+	ErrLoginNetwork       = errors.New("network error while logging in")
+)
+
+// accountPattern matches the logged-in user link in the CSES page header
+var accountPattern = regexp.MustCompile(`<a[^>]*class="account"[^>]*>([^<]+)</a>`)
+
 // SessionData represents the stored authentication session
 type SessionData struct {
 	PHPSessionID string    `json:"php_session_id"`
@@ -23,26 +49,58 @@ type SessionData struct {
 	LastUsed     time.Time `json:"last_used"`
 }
 
-// CSESAuth handles authentication with CSES
+// CSESAuth handles authentication with CSES. A single instance is shared by
+// everything in the process that talks to CSES, so the session is loaded and
+// verified once; the session lock file serializes logins across processes.
 type CSESAuth struct {
 	client      *http.Client
-	sessionData *SessionData
+	baseURL     string
 	sessionFile string
+	account     string
+
+	// pageCacheDir holds scraped pages (see fetchCachedPage); -refresh-pages
+	// fetches them again
+	pageCacheDir string
+	refreshPages bool
+
+	mu          sync.Mutex
+	sessionData *SessionData
+	verified    bool
+
+	// tokenMu serializes taking a CSRF token and posting it, since taking a
+	// new one invalidates the last; archives still arrive in parallel
+	tokenMu sync.Mutex
 }
 
 // NewCSESAuth creates a new CSES authentication handler
 func NewCSESAuth(config *Config) *CSESAuth {
-	// Create HTTP client with cookie jar
+	// Create HTTP client with cookie jar. Downloads run in parallel when
+	// several problems are fetched, so keep enough idle connections to reuse.
+	transport := newFixtureTransport(config)
+	if transport == nil {
+		pooled := http.DefaultTransport.(*http.Transport).Clone()
+		pooled.MaxIdleConnsPerHost = prefetchWorkers
+		transport = pooled
+	}
 	jar, _ := cookiejar.New(nil)
 	client := &http.Client{
-		Jar:     jar,
-		Timeout: 30 * time.Second,
+		Jar:       jar,
+		Timeout:   30 * time.Second,
+		Transport: &unavailableTransport{next: transport},
 	}
 
-	return &CSESAuth{
-		client:      client,
-		sessionFile: config.GetSessionFile(),
+	auth := &CSESAuth{
+		client:       client,
+		baseURL:      config.GetBaseURL(),
+		sessionFile:  config.GetSessionFile(),
+		account:      config.GetAccount(),
+		refreshPages: config.RefreshPages,
+	}
+	// Fixture runs must issue the same requests every time
+	if auth.sessionFile != "" {
+		auth.pageCacheDir = config.GetPageCacheDir()
 	}
+	return auth
 }
 
 // LoadSession loads session data from file
@@ -61,6 +119,8 @@ func (a *CSESAuth) LoadSession() error {
 		return fmt.Errorf("failed to parse session data: %w", err)
 	}
 
+	registerSecret(sessionData.PHPSessionID)
+	registerSecret(sessionData.CSRFToken)
 	a.sessionData = &sessionData
 	return nil
 }
@@ -71,6 +131,11 @@ func (a *CSESAuth) SaveSession() error {
 		return fmt.Errorf("no session data to save")
 	}
 
+	// In-memory session (fixture record/replay)
+	if a.sessionFile == "" {
+		return nil
+	}
+
 	// Create directory if it doesn't exist
 	if err := os.MkdirAll(filepath.Dir(a.sessionFile), 0700); err != nil {
 		return fmt.Errorf("failed to create session directory: %w", err)
@@ -84,7 +149,13 @@ func (a *CSESAuth) SaveSession() error {
 		return fmt.Errorf("failed to marshal session data: %w", err)
 	}
 
-	if err := os.WriteFile(a.sessionFile, data, 0600); err != nil {
+	// Write to a temp file and rename so other processes never read a partial session
+	tmpFile := a.sessionFile + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if err := os.Rename(tmpFile, a.sessionFile); err != nil {
+		os.Remove(tmpFile)
 		return fmt.Errorf("failed to write session file: %w", err)
 	}
 
@@ -93,7 +164,17 @@ func (a *CSESAuth) SaveSession() error {
 
 // ClearSession removes the session file
 func (a *CSESAuth) ClearSession() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	unlock, err := lockSession(a.sessionFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	a.sessionData = nil
+	a.verified = false
 	if err := os.Remove(a.sessionFile); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove session file: %w", err)
 	}
@@ -115,17 +196,19 @@ func (a *CSESAuth) HasValidSession() bool {
 	return a.sessionData.PHPSessionID != "" && a.sessionData.CSRFToken != ""
 }
 
-// GetCredentials retrieves CSES credentials from environment variables
+// GetCredentials retrieves CSES credentials from environment variables:
+// CSES_USERNAME and CSES_PASSWORD, or those of the named account
 func (a *CSESAuth) GetCredentials() (string, string, error) {
-	username := os.Getenv("CSES_USERNAME")
-	password := os.Getenv("CSES_PASSWORD")
+	userVar, passVar := credentialVars(a.account)
+	username := os.Getenv(userVar)
+	password := os.Getenv(passVar)
 
 	if username == "" {
-		return "", "", fmt.Errorf("CSES_USERNAME environment variable is not set")
+		return "", "", fmt.Errorf("%s environment variable is not set", userVar)
 	}
 
 	if password == "" {
-		return "", "", fmt.Errorf("CSES_PASSWORD environment variable is not set")
+		return "", "", fmt.Errorf("%s environment variable is not set", passVar)
 	}
 
 	return username, password, nil
@@ -135,9 +218,13 @@ func (a *CSESAuth) GetCredentials() (string, string, error) {
 func (a *CSESAuth) FetchLoginPage() (string, string, error) {
 	yellow.Println("� Fetching login page...")
 
-	resp, err := a.client.Get("https://cses.fi/login")
+	resp, err := a.client.Get(a.baseURL + "/login")
+	var unavailable *UnavailableError
+	if errors.As(err, &unavailable) {
+		return "", "", unavailable
+	}
 	if err != nil {
-		return "", "", fmt.Errorf("failed to fetch login page: %w", err)
+		return "", "", fmt.Errorf("%w: %v", ErrLoginNetwork, err)
 	}
 	defer resp.Body.Close()
 
@@ -167,10 +254,8 @@ func (a *CSESAuth) FetchLoginPage() (string, string, error) {
 		return "", "", fmt.Errorf("failed to extract required authentication data")
 	}
 
-	if len(csrfToken) >= 8 && len(phpSessionID) >= 8 {
-		cyan.Printf("★ Extracted CSRF token: %s...\n", csrfToken[:8])
-		cyan.Printf("!★ Extracted PHP session ID: %s...\n", phpSessionID[:8])
-	}
+	cyan.Printf("★ Extracted CSRF token: %s\n", maskSecret(csrfToken))
+	cyan.Printf("!★ Extracted PHP session ID: %s\n", maskSecret(phpSessionID))
 
 	return csrfToken, phpSessionID, nil
 }
@@ -191,26 +276,45 @@ func (a *CSESAuth) extractCSRFToken(html string) (string, error) {
 		if len(matches) > 1 {
 			token := matches[1]
 			if len(token) > 0 {
+				registerSecret(token)
 				return token, nil
 			}
 		}
 	}
 
-	return "", fmt.Errorf("CSRF token not found in login page")
+	return "", fmt.Errorf("CSRF token not found in page")
 }
 
 // extractPHPSessionID extracts PHP session ID from cookies
 func (a *CSESAuth) extractPHPSessionID(cookies []*http.Cookie) string {
 	for _, cookie := range cookies {
 		if cookie.Name == "PHPSESSID" {
+			registerSecret(cookie.Value)
 			return cookie.Value
 		}
 	}
 	return ""
 }
 
-// Login performs the actual login process including all validation
+// Login performs a fresh login, replacing the shared session
 func (a *CSESAuth) Login() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	unlock, err := lockSession(a.sessionFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return a.login()
+}
+
+// login performs the actual login process including all validation.
+// Callers must hold both a.mu and the session lock.
+func (a *CSESAuth) login() error {
+	a.verified = false
+
 	username, password, err := a.GetCredentials()
 	if err != nil {
 		return fmt.Errorf("credential error: %w", err)
@@ -232,7 +336,7 @@ func (a *CSESAuth) Login() error {
 	}
 
 	// Create login request
-	req, err := http.NewRequest("POST", "https://cses.fi/login", strings.NewReader(loginData.Encode()))
+	req, err := http.NewRequest("POST", a.baseURL+"/login", strings.NewReader(loginData.Encode()))
 	if err != nil {
 		return fmt.Errorf("failed to create login request: %w", err)
 	}
@@ -240,15 +344,19 @@ func (a *CSESAuth) Login() error {
 	// Set headers to match browser request
 	a.setLoginHeaders(req, phpSessionID)
 
-	// Perform login request
+	// Perform login request; the client follows the post-login redirect
 	resp, err := a.client.Do(req)
+	var unavailable *UnavailableError
+	if errors.As(err, &unavailable) {
+		return unavailable
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create login request: %w", err)
+		return fmt.Errorf("%w: %v", ErrLoginNetwork, err)
 	}
 	defer resp.Body.Close()
 
 	// Check if login was successful
-	if err := a.validateLoginResponse(resp); err != nil {
+	if err := a.validateLoginResponse(resp, username); err != nil {
 		return fmt.Errorf("login validation failed: %w", err)
 	}
 
@@ -265,6 +373,7 @@ func (a *CSESAuth) Login() error {
 	if err := a.SaveSession(); err != nil {
 		return fmt.Errorf("failed to save session: %w", err)
 	}
+	a.verified = true
 
 	green.Println("✅ Login successful")
 	return nil
@@ -279,8 +388,8 @@ func (a *CSESAuth) setLoginHeaders(req *http.Request, phpSessionID string) {
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Cookie", fmt.Sprintf("PHPSESSID=%s", phpSessionID))
 	req.Header.Set("DNT", "1")
-	req.Header.Set("Origin", "https://cses.fi")
-	req.Header.Set("Referer", "https://cses.fi/login")
+	req.Header.Set("Origin", a.baseURL)
+	req.Header.Set("Referer", a.baseURL+"/login")
 	req.Header.Set("Sec-Fetch-Dest", "document")
 	req.Header.Set("Sec-Fetch-Mode", "navigate")
 	req.Header.Set("Sec-Fetch-Site", "same-origin")
@@ -292,58 +401,101 @@ func (a *CSESAuth) setLoginHeaders(req *http.Request, phpSessionID string) {
 	req.Header.Set("sec-ch-ua-platform", `"Linux"`)
 }
 
-// validateLoginResponse validates the login response
-func (a *CSESAuth) validateLoginResponse(resp *http.Response) error {
-	// Successful login should redirect (status 302) or return 200
-	if resp.StatusCode != http.StatusFound && resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("expected status 302 or 200, got %d", resp.StatusCode)
+// validateLoginResponse checks the page we landed on after the login redirect:
+// it must show the expected username in the account header. Otherwise the
+// page is inspected to tell wrong credentials apart from captcha/rate limiting.
+func (a *CSESAuth) validateLoginResponse(resp *http.Response, username string) error {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%w (HTTP %d)", ErrLoginBlocked, resp.StatusCode)
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("CSES returned server error HTTP %d", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected login response HTTP %d", resp.StatusCode)
 	}
 
-	// Check if there's a redirect location
-	location := resp.Header.Get("Location")
-	if location != "" {
-		// If redirecting to login page, it means login failed
-		if strings.Contains(location, "/login") {
-			return fmt.Errorf("failed to login: invalid credentials")
-		}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read login response: %v", ErrLoginNetwork, err)
 	}
+	html := string(body)
 
-	// Read response body to check for error messages
-	body, err := io.ReadAll(resp.Body)
-	if err == nil {
-		html := string(body)
-		// Check for error messages in HTML
-		if strings.Contains(html, "Invalid username or password") ||
-			strings.Contains(html, "Login failed") ||
-			strings.Contains(html, "error") {
-			return fmt.Errorf("failed to login: invalid credentials")
+	if user := extractLoggedInUser(html); user != "" {
+		if !strings.EqualFold(user, username) {
+			return fmt.Errorf("logged in as %q instead of %q", user, username)
 		}
+		return nil
 	}
 
-	return nil
+	lower := strings.ToLower(html)
+	switch {
+	case strings.Contains(lower, "captcha"):
+		return fmt.Errorf("%w: a captcha is required", ErrLoginBlocked)
+	case strings.Contains(lower, "too many") || strings.Contains(lower, "blocked"):
+		return ErrLoginBlocked
+	case strings.Contains(lower, "invalid username or password") || strings.Contains(lower, "login failed"):
+		return ErrInvalidCredentials
+	}
+
+	// Still on the login form without a header account link: credentials were rejected
+	if resp.Request != nil && strings.HasPrefix(resp.Request.URL.Path, "/login") {
+		return ErrInvalidCredentials
+	}
+
+	return fmt.Errorf("could not confirm login: %q not found in the page header", username)
+}
+
+// extractLoggedInUser returns the username shown in the page header, or "" if logged out
+func extractLoggedInUser(html string) string {
+	matches := accountPattern.FindStringSubmatch(html)
+	if len(matches) < 2 {
+		return ""
+	}
+	return strings.TrimSpace(matches[1])
 }
 
 // EnsureAuthenticated ensures we have a valid authentication session
 func (a *CSESAuth) EnsureAuthenticated() error {
-	// Try to load existing session
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Already loaded and checked by another caller sharing this handler
+	if a.verified && a.HasValidSession() {
+		return nil
+	}
+
+	unlock, err := lockSession(a.sessionFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Try to load existing session; another process may have just logged in
 	if err := a.LoadSession(); err == nil && a.HasValidSession() {
-		if a.TestSession() == nil {
+		err := a.TestSession()
+		if err == nil {
+			a.verified = true
 			return nil
 		}
+		if errors.Is(err, ErrSessionMismatch) {
+			yellow.Printf("⚠️  %v, logging in again\n", err)
+		}
 	}
 
 	// Session invalid or expired, login again
-	return a.Login()
+	return a.login()
 }
 
-// TestSession tests if the session is still valid by attempting a request
+// TestSession checks the stored session against the page header, which names
+// the logged-in user: a logged-out header means the session expired, and
+// another user than the session's (or the credentials') means sessions got
+// mixed up, e.g. a session file copied between profiles
 func (a *CSESAuth) TestSession() error {
 	if a.sessionData == nil {
 		return fmt.Errorf("no session data")
 	}
 
-	// Test session by trying to access a protected page
-	req, err := http.NewRequest("GET", "https://cses.fi/problemset/stats", nil)
+	req, err := http.NewRequest("GET", a.baseURL+"/", nil)
 	if err != nil {
 		return fmt.Errorf("failed to create test request: %w", err)
 	}
@@ -361,43 +513,253 @@ func (a *CSESAuth) TestSession() error {
 	}
 
 	body, err := io.ReadAll(resp.Body)
-
-	if strings.Contains(string(body), "Please login") {
-		return fmt.Errorf("login expired, need to relogin")
+	if err != nil {
+		return fmt.Errorf("failed to read test session response: %w", err)
 	}
 
+	user := extractLoggedInUser(string(body))
+	if user == "" {
+		return ErrSessionExpired
+	}
+	if !strings.EqualFold(user, a.sessionData.Username) {
+		return fmt.Errorf("%w: logged in as %q, saved for %q", ErrSessionMismatch, user, a.sessionData.Username)
+	}
+	if username, _, err := a.GetCredentials(); err == nil && !strings.EqualFold(user, username) {
+		return fmt.Errorf("%w: logged in as %q, but the credentials are for %q", ErrSessionMismatch, user, username)
+	}
 	return nil
 }
 
-// DownloadTestCases downloads test cases for a given problem ID
-func (a *CSESAuth) DownloadTestCases(problemID string) ([]byte, error) {
-	if a.sessionData == nil {
-		return nil, fmt.Errorf("no session data")
+// fetchCSRFToken loads the page hosting a form with the stored session cookie
+// and returns its current CSRF token. The login-time token is never reused for
+// POSTs because CSES rotates it while keeping the same PHPSESSID.
+func (a *CSESAuth) fetchCSRFToken(pageURL string, session *SessionData) (string, error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create page request: %w", err)
+	}
+	req.Header.Set("Cookie", fmt.Sprintf("PHPSESSID=%s", session.PHPSessionID))
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/137.0.0.0 Safari/537.36")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Request != nil && strings.HasPrefix(resp.Request.URL.Path, "/login") {
+		return "", ErrSessionExpired
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch %s: HTTP %d", pageURL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", pageURL, err)
+	}
+
+	token, err := a.extractCSRFToken(string(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh CSRF token: %w", err)
+	}
+
+	return token, nil
+}
+
+// FetchProblemset returns the HTML of the problem list. With a session the
+// page carries the user's solve status for every task.
+func (a *CSESAuth) FetchProblemset() (string, error) {
+	return a.fetchCachedPage("/problemset/", "problemset", problemsetTTL)
+}
+
+// FetchProblemStats returns the HTML of a problem's statistics page (fastest
+// and shortest solutions), which CSES only shows to logged in users. A cached
+// copy is returned without logging in.
+func (a *CSESAuth) FetchProblemStats(problemID string) (string, error) {
+	path := fmt.Sprintf("/problemset/stats/%s/", problemID)
+	if page, ok := a.loadCachedPage(path, problemStatsTTL); ok {
+		return page, nil
+	}
+	if err := a.EnsureAuthenticated(); err != nil {
+		return "", fmt.Errorf("the statistics page needs a login: %w", err)
+	}
+	return a.fetchCachedPage(path, "problem statistics", problemStatsTTL)
+}
+
+// FetchTask returns the HTML of a problem's task page, with its limits
+func (a *CSESAuth) FetchTask(problemID string) (string, error) {
+	return a.fetchCachedPage(fmt.Sprintf("/problemset/task/%s/", problemID), "task page", taskPageTTL)
+}
+
+// FetchSubmitForm returns the HTML of a problem's submit form, with the
+// languages it offers
+func (a *CSESAuth) FetchSubmitForm(problemID string) (string, error) {
+	return a.fetchPage(fmt.Sprintf("/problemset/submit/%s/", problemID), "submit form")
+}
+
+// SubmitSolution sends a solution through the problem's submit form and
+// returns the URL of the submission's result page. option is the language
+// variant (C++17, PyPy3) and may be empty.
+func (a *CSESAuth) SubmitSolution(problemID, fileName string, source []byte, language, option string) (string, error) {
+	a.mu.Lock()
+	session := a.sessionData
+	a.mu.Unlock()
+
+	if session == nil {
+		return "", fmt.Errorf("no session data")
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+
+	a.tokenMu.Lock()
+	defer a.tokenMu.Unlock()
+	csrfToken, err := a.fetchCSRFToken(fmt.Sprintf("%s/problemset/submit/%s/", a.baseURL, problemID), session)
+	if err != nil {
+		return "", err
+	}
+
+	form.WriteField("csrf_token", csrfToken)
+	form.WriteField("task", problemID)
+	form.WriteField("lang", language)
+	if option != "" {
+		form.WriteField("option", option)
+	}
+	file, err := form.CreateFormFile("file", fileName)
+	if err != nil {
+		return "", fmt.Errorf("failed to build the submission: %w", err)
+	}
+	file.Write(source)
+	if err := form.Close(); err != nil {
+		return "", fmt.Errorf("failed to build the submission: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", a.baseURL+"/course/send.php", &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create submit request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Cookie", fmt.Sprintf("PHPSESSID=%s", session.PHPSessionID))
+	req.Header.Set("Referer", fmt.Sprintf("%s/problemset/submit/%s/", a.baseURL, problemID))
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/137.0.0.0 Safari/537.36")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to submit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Request != nil && strings.HasPrefix(resp.Request.URL.Path, "/login") {
+		return "", ErrSessionExpired
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to submit: HTTP %d", resp.StatusCode)
+	}
+	if resp.Request == nil || !strings.Contains(resp.Request.URL.Path, "/result/") {
+		return "", fmt.Errorf("CSES did not accept the submission")
+	}
+	return resp.Request.URL.String(), nil
+}
+
+// FetchSubmissionResult returns the HTML of a submission's result page
+func (a *CSESAuth) FetchSubmissionResult(resultURL string) (string, error) {
+	parsed, err := url.Parse(resultURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid result URL %q: %w", resultURL, err)
+	}
+	return a.fetchPage(parsed.Path, "submission result")
+}
+
+// fetchPage GETs a page of the site, with the session cookie if there is one
+func (a *CSESAuth) fetchPage(path, what string) (string, error) {
+	a.mu.Lock()
+	session := a.sessionData
+	a.mu.Unlock()
+
+	req, err := http.NewRequest("GET", a.baseURL+path, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s request: %w", what, err)
+	}
+	if session != nil {
+		req.Header.Set("Cookie", fmt.Sprintf("PHPSESSID=%s", session.PHPSessionID))
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/137.0.0.0 Safari/537.36")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch %s: HTTP %d", what, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", what, err)
+	}
+
+	return string(body), nil
+}
+
+// DownloadTestCases downloads test cases for a given problem ID. With the
+// metadata of a cached archive the request is conditional, and ErrNotModified
+// means the cached tests are current. progress, if not nil, is called as the
+// archive arrives with the bytes read so far and the archive size (-1 if CSES
+// does not send it).
+func (a *CSESAuth) DownloadTestCases(problemID string, cached *ArchiveMeta, progress func(read, total int64)) ([]byte, *ArchiveMeta, error) {
+	a.mu.Lock()
+	session := a.sessionData
+	a.mu.Unlock()
+
+	if session == nil {
+		return nil, nil, fmt.Errorf("no session data")
+	}
+
+	// CSES rotates CSRF tokens, so take a fresh one from the tests page
+	a.tokenMu.Lock()
+	testsURL := fmt.Sprintf("%s/problemset/tests/%s/", a.baseURL, problemID)
+	csrfToken, err := a.fetchCSRFToken(testsURL, session)
+	if err != nil {
+		a.tokenMu.Unlock()
+		return nil, nil, err
 	}
 
 	// Prepare POST data for test case download
 	formData := url.Values{
-		"csrf_token": {a.sessionData.CSRFToken},
+		"csrf_token": {csrfToken},
 		"download":   {"true"},
 	}
 
 	// Create POST request to download test cases
-	url := fmt.Sprintf("https://cses.fi/problemset/tests/%s/", problemID)
-	req, err := http.NewRequest("POST", url, strings.NewReader(formData.Encode()))
+	req, err := http.NewRequest("POST", testsURL, strings.NewReader(formData.Encode()))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create test case download request: %w", err)
+		a.tokenMu.Unlock()
+		return nil, nil, fmt.Errorf("failed to create test case download request: %w", err)
 	}
 
 	// Set required headers
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Cookie", fmt.Sprintf("PHPSESSID=%s", a.sessionData.PHPSessionID))
-	req.Header.Set("Referer", fmt.Sprintf("https://cses.fi/problemset/task/%s", problemID))
+	req.Header.Set("Cookie", fmt.Sprintf("PHPSESSID=%s", session.PHPSessionID))
+	req.Header.Set("Referer", fmt.Sprintf("%s/problemset/task/%s", a.baseURL, problemID))
 	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/137.0.0.0 Safari/537.36")
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
 
-	// Execute the request
+	// Execute the request; the token is used up once the response headers arrive
 	resp, err := a.client.Do(req)
+	a.tokenMu.Unlock()
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute test case download request: %w", err)
+		return nil, nil, fmt.Errorf("failed to execute test case download request: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -405,29 +767,64 @@ func (a *CSESAuth) DownloadTestCases(problemID string) ([]byte, error) {
 	if resp.StatusCode == http.StatusFound {
 		location := resp.Header.Get("Location")
 		if strings.Contains(location, "/login") {
-			return nil, fmt.Errorf("session expired, requires re-authentication")
+			return nil, nil, ErrSessionExpired
 		}
 	}
 
+	// The client follows redirects, so an expired session usually ends up on the login page
+	if resp.Request != nil && strings.HasPrefix(resp.Request.URL.Path, "/login") {
+		return nil, nil, ErrSessionExpired
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil, ErrNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to download test cases: HTTP %d", resp.StatusCode)
+		return nil, nil, fmt.Errorf("failed to download test cases: HTTP %d", resp.StatusCode)
 	}
 
 	// Check if the response is a ZIP file
 	contentType := resp.Header.Get("Content-Type")
 	if !strings.Contains(contentType, "application/zip") && !strings.Contains(contentType, "application/octet-stream") {
-		return nil, fmt.Errorf("expected ZIP file, got content type: %s", contentType)
+		return nil, nil, fmt.Errorf("expected ZIP file, got content type: %s", contentType)
 	}
 
 	// Read the ZIP file data
-	zipData, err := io.ReadAll(resp.Body)
+	var body io.Reader = resp.Body
+	if progress != nil {
+		body = &progressReader{reader: resp.Body, total: resp.ContentLength, progress: progress}
+	}
+	zipData, err := io.ReadAll(body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read ZIP file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read ZIP file: %w", err)
 	}
 
 	if len(zipData) == 0 {
-		return nil, fmt.Errorf("received empty ZIP file")
+		return nil, nil, fmt.Errorf("received empty ZIP file")
 	}
 
-	return zipData, nil
+	sum := sha256.Sum256(zipData)
+	meta := &ArchiveMeta{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		SHA256:       hex.EncodeToString(sum[:]),
+		FetchedAt:    time.Now(),
+	}
+	return zipData, meta, nil
+}
+
+// progressReader reports how much of a response body has been read
+type progressReader struct {
+	reader   io.Reader
+	read     int64
+	total    int64
+	progress func(read, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	r.progress(r.read, r.total)
+	return n, err
 }
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// goVersionPattern matches a -go-versions entry: 1.22, 1.22.5 or go1.22.5
+var goVersionPattern = regexp.MustCompile(`^(?:go)?1\.([0-9]+)(\.[0-9]+)?$`)
+
+// minToolchainMinor is the oldest Go release GOTOOLCHAIN can switch to
+const minToolchainMinor = 21
+
+// benchColumn is the build and the results of the solution with one toolchain
+type benchColumn struct {
+	Toolchain string
+	BuildTime time.Duration
+	Err       error
+	Results   map[int]TestResult
+}
+
+// parseGoVersions turns -go-versions into GOTOOLCHAIN values. A minor version
+// means its first release (1.22 is go1.22.0), and local is the installed go.
+func parseGoVersions(list string) ([]string, error) {
+	var toolchains []string
+	for _, version := range strings.Split(list, ",") {
+		version = strings.TrimSpace(version)
+		toolchain := "local"
+		if version != "local" {
+			match := goVersionPattern.FindStringSubmatch(version)
+			if match == nil {
+				return nil, fmt.Errorf("invalid Go version %q (use e.g. 1.22, 1.22.5 or local)", version)
+			}
+			minor, _ := strconv.Atoi(match[1])
+			if minor < minToolchainMinor {
+				return nil, fmt.Errorf("Go %s cannot be selected, toolchains older than 1.%d are not downloadable", version, minToolchainMinor)
+			}
+			patch := match[2]
+			if patch == "" {
+				patch = ".0"
+			}
+			toolchain = fmt.Sprintf("go1.%d%s", minor, patch)
+		}
+		if slices.Contains(toolchains, toolchain) {
+			return nil, fmt.Errorf("Go version %s is listed twice", version)
+		}
+		toolchains = append(toolchains, toolchain)
+	}
+	return toolchains, nil
+}
+
+// handleBench implements `bench -go-versions=1.21,1.22,1.23`: the solution is
+// built with every toolchain, the go command downloading those that are not
+// installed, and the tests are timed one at a time with each build.
+func handleBench(config *Config, goVersions string) error {
+	if err := validateRunConfig(config); err != nil {
+		return withExitCode(ExitUsageError, err)
+	}
+	if goVersions == "" {
+		return withExitCode(ExitUsageError, fmt.Errorf("bench needs -go-versions, e.g. -go-versions=1.22,1.23,local"))
+	}
+	toolchains, err := parseGoVersions(goVersions)
+	if err != nil {
+		return withExitCode(ExitUsageError, err)
+	}
+	if config.Docker != "" || config.GetCompiler() != CompilerGC {
+		return withExitCode(ExitUsageError, fmt.Errorf("-go-versions builds with the gc toolchains on the host, without -docker or -compiler"))
+	}
+
+	runner := NewTestRunner(config, NewCSESAuth(config))
+	if err := runner.compiler.ValidateGo(); err != nil {
+		return withExitCode(ExitCompileError, fmt.Errorf("Go validation failed: %w", err))
+	}
+	if err := runner.authenticate(); err != nil {
+		return err
+	}
+	testCases, err := runner.fetcher.FetchTestCases(config.ProblemID)
+	if err != nil {
+		return withExitCode(ExitFetchError, fmt.Errorf("failed to fetch test cases: %w", err))
+	}
+	if config.Test > 0 {
+		if testCases, err = selectTest(testCases, config.Test); err != nil {
+			return err
+		}
+	}
+	if len(testCases) == 0 {
+		return withExitCode(ExitFetchError, fmt.Errorf("no test cases found for problem %s", config.ProblemID))
+	}
+
+	yellow.Printf("⚖️  Benchmarking %s on %d test(s) with %s...\n", config.FilePath, len(testCases), strings.Join(toolchains, ", "))
+	var columns []benchColumn
+	for _, toolchain := range toolchains {
+		columns = append(columns, benchToolchain(context.Background(), config, toolchain, testCases))
+	}
+
+	failed := displayBench(config, columns, testCases)
+	if !slices.ContainsFunc(columns, func(column benchColumn) bool { return column.Err == nil }) {
+		return withExitCode(ExitCompileError, errors.New("no Go version could build the solution"))
+	}
+	if failed {
+		return ErrTestsFailed
+	}
+	return nil
+}
+
+// benchToolchain builds the solution with one toolchain and runs every test
+// with the build, sequentially so the timings do not disturb each other
+func benchToolchain(ctx context.Context, config *Config, toolchain string, testCases []TestCase) benchColumn {
+	toolchainConfig := *config
+	toolchainConfig.GoToolchain = toolchain
+	runner := NewTestRunner(&toolchainConfig, nil)
+	column := benchColumn{Toolchain: toolchain, Results: make(map[int]TestResult)}
+
+	if toolchain == "local" {
+		cmd := exec.Command("go", "env", "GOVERSION")
+		cmd.Env = append(os.Environ(), "GOTOOLCHAIN=local")
+		if output, err := cmd.Output(); err == nil {
+			column.Toolchain = strings.TrimSpace(string(output))
+		}
+	}
+
+	yellow.Printf("🔨 Building with %s...\n", column.Toolchain)
+	startTime := time.Now()
+	executablePath, err := runner.compiler.Compile()
+	column.BuildTime = time.Since(startTime)
+	if err != nil {
+		column.Err = err
+		red.Printf("❌ %s cannot build the solution: %v\n", column.Toolchain, err)
+		return column
+	}
+	defer os.Remove(executablePath)
+
+	for _, testCase := range testCases {
+		column.Results[testCase.Number] = runner.executeRepeated(ctx, executablePath, testCase)
+	}
+	return column
+}
+
+// displayBench prints the timings of every test with every toolchain, the
+// fastest of each row starred, and reports whether a test failed
+func displayBench(config *Config, columns []benchColumn, testCases []TestCase) (failed bool) {
+	runs := "one run"
+	if config.Repeat > 1 {
+		runs = fmt.Sprintf("mean of %d runs", config.Repeat)
+	}
+	width := 20 + 14*len(columns)
+	fmt.Println("\n" + strings.Repeat("-", width))
+	white.Printf("⚖️  GO VERSIONS (%s per test)\n", runs)
+	fmt.Println(strings.Repeat("-", width))
+
+	header := fmt.Sprintf("%-8s", "TEST")
+	for _, column := range columns {
+		header += fmt.Sprintf(" %13s", column.Toolchain+" ")
+	}
+	fmt.Println(header)
+
+	// Totals only count tests every build passed, so they compare like with like
+	totals := make([]time.Duration, len(columns))
+	counted := 0
+	for _, testCase := range testCases {
+		durations := make([]time.Duration, len(columns))
+		cells := make([]string, len(columns))
+		allPassed := true
+		for i, column := range columns {
+			result, ran := column.Results[testCase.Number]
+			switch {
+			case !ran:
+				cells[i] = "-"
+			case !result.Passed:
+				cells[i] = string(result.Verdict)
+				allPassed, failed = false, true
+			default:
+				cells[i] = fmt.Sprintf("%.2fms", result.Duration.Seconds()*1000)
+				durations[i] = result.Duration
+			}
+		}
+		if allPassed {
+			counted++
+			for i := range columns {
+				totals[i] += durations[i]
+			}
+		}
+		fmt.Println(benchRow(strconv.Itoa(testCase.Number), cells, durations))
+	}
+
+	fmt.Println(strings.Repeat("-", width))
+	cells := make([]string, len(columns))
+	builds := make([]string, len(columns))
+	for i, column := range columns {
+		cells[i], builds[i] = "-", "failed"
+		if column.Err == nil {
+			cells[i] = fmt.Sprintf("%.2fms", totals[i].Seconds()*1000)
+			builds[i] = fmt.Sprintf("%.2fs", column.BuildTime.Seconds())
+		}
+	}
+	fmt.Println(benchRow("TOTAL", cells, totals))
+	fmt.Println(benchRow("BUILD", builds, nil))
+	fmt.Println(strings.Repeat("-", width))
+
+	if counted < len(testCases) {
+		yellow.Printf("⚠️  TOTAL counts the %d of %d tests that every version passed\n", counted, len(testCases))
+	}
+	fastest, slowest := -1, -1
+	for i, total := range totals {
+		if total == 0 {
+			continue
+		}
+		if fastest == -1 || total < totals[fastest] {
+			fastest = i
+		}
+		if slowest == -1 || total > totals[slowest] {
+			slowest = i
+		}
+	}
+	if fastest != -1 && fastest != slowest {
+		saved := 1 - totals[fastest].Seconds()/totals[slowest].Seconds()
+		cyan.Printf("💡 Fastest: %s, %.1f%% less time than %s\n", columns[fastest].Toolchain, saved*100, columns[slowest].Toolchain)
+	}
+	return failed
+}
+
+// benchRow formats a row of the matrix, starring the smallest of durations
+// when there are at least two to compare
+func benchRow(label string, cells []string, durations []time.Duration) string {
+	best, compared := -1, 0
+	for i, duration := range durations {
+		if duration == 0 {
+			continue
+		}
+		compared++
+		if best == -1 || duration < durations[best] {
+			best = i
+		}
+	}
+
+	row := fmt.Sprintf("%-8s", label)
+	for i, cell := range cells {
+		if i == best && compared > 1 {
+			cell += "*"
+		} else {
+			cell += " "
+		}
+		row += fmt.Sprintf(" %13s", cell)
+	}
+	return row
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lastUsedFile is touched in a problem's test directory whenever its tests are used
+const lastUsedFile = ".last-used"
+
+// archiveMetaFile records, in a problem's test directory, which archive the tests came from
+const archiveMetaFile = ".archive.json"
+
+// maxEvictionLog is how many evictions `cache info` remembers
+const maxEvictionLog = 20
+
+// cachedTestSet is the cached tests of one problem
+type cachedTestSet struct {
+	ProblemID string
+	Dir       string
+	Size      int64
+	LastUsed  time.Time
+}
+
+// CacheEviction records a test set removed to keep the cache under its quota
+type CacheEviction struct {
+	ProblemID string    `json:"problem_id"`
+	Size      int64     `json:"size_bytes"`
+	LastUsed  time.Time `json:"last_used"`
+	EvictedAt time.Time `json:"evicted_at"`
+}
+
+// ArchiveMeta identifies the downloaded test archive of a problem, so a refresh
+// can ask CSES for it only if it changed
+type ArchiveMeta struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	SHA256       string    `json:"sha256"`
+	FetchedAt    time.Time `json:"fetched_at"`
+
+	// RetryRefresh is set when a refresh found CSES unavailable; the next
+	// run checks for changed tests again
+	RetryRefresh bool `json:"retry_refresh,omitempty"`
+}
+
+// loadArchiveMeta returns the archive metadata saved in dir, or nil if there is none
+func loadArchiveMeta(dir string) *ArchiveMeta {
+	data, err := os.ReadFile(filepath.Join(dir, archiveMetaFile))
+	if err != nil {
+		return nil
+	}
+	var meta ArchiveMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil
+	}
+	return &meta
+}
+
+// saveArchiveMeta records the archive the tests in dir came from
+func saveArchiveMeta(dir string, meta *ArchiveMeta) {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return
+	}
+	os.WriteFile(filepath.Join(dir, archiveMetaFile), data, 0644)
+}
+
+// parseByteSize parses sizes such as 500MB, 2GB, 750K or a plain byte count
+func parseByteSize(value string) (int64, error) {
+	text := strings.ToUpper(strings.TrimSpace(value))
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	}
+
+	multiplier := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(text, unit.suffix) {
+			text, multiplier = strings.TrimSpace(strings.TrimSuffix(text, unit.suffix)), unit.multiplier
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size %q (e.g. 500MB or 2GB)", value)
+	}
+	return int64(number * float64(multiplier)), nil
+}
+
+// touchTestSet marks the tests in dir as just used
+func touchTestSet(dir string) {
+	path := filepath.Join(dir, lastUsedFile)
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		os.WriteFile(path, nil, 0644)
+	}
+}
+
+// listTestSets returns the cached test sets, least recently used first
+func listTestSets(config *Config) ([]cachedTestSet, error) {
+	entries, err := os.ReadDir(config.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var sets []cachedTestSet
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue // not a problem's tests (history, .auth, ...)
+		}
+
+		set := cachedTestSet{ProblemID: entry.Name(), Dir: filepath.Join(config.CacheDir, entry.Name())}
+		// Tests with the same content are hard links to one file (see
+		// dedupWriter), which takes up its size once
+		counted := make(map[int64][]fs.FileInfo)
+		filepath.WalkDir(set.Dir, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				if info, err := d.Info(); err == nil {
+					if !slices.ContainsFunc(counted[info.Size()], func(other fs.FileInfo) bool { return os.SameFile(info, other) }) {
+						counted[info.Size()] = append(counted[info.Size()], info)
+						set.Size += info.Size()
+					}
+					if set.LastUsed.Before(info.ModTime()) {
+						set.LastUsed = info.ModTime()
+					}
+				}
+			}
+			return nil
+		})
+		if info, err := os.Stat(filepath.Join(set.Dir, lastUsedFile)); err == nil {
+			set.LastUsed = info.ModTime()
+		}
+		sets = append(sets, set)
+	}
+
+	sort.Slice(sets, func(i, j int) bool { return sets[i].LastUsed.Before(sets[j].LastUsed) })
+	return sets, nil
+}
+
+// enforceCacheQuota evicts the least recently used test sets until the cached
+// tests fit in -cache-max-size. The tests of keep (the problem being run) are
+// never evicted, and sessions, history and config are not counted or touched.
+func enforceCacheQuota(config *Config, keep string) {
+	if config.CacheMaxSize == "" {
+		return
+	}
+	limit, err := parseByteSize(config.CacheMaxSize)
+	if err != nil {
+		yellow.Printf("⚠️  Ignoring -cache-max-size: %v\n", err)
+		return
+	}
+
+	sets, err := listTestSets(config)
+	if err != nil {
+		return
+	}
+	var total int64
+	for _, set := range sets {
+		total += set.Size
+	}
+
+	var evicted []CacheEviction
+	for _, set := range sets {
+		if total <= limit {
+			break
+		}
+		if set.ProblemID == keep {
+			continue
+		}
+		if err := os.RemoveAll(set.Dir); err != nil {
+			yellow.Printf("⚠️  Failed to evict the tests of problem %s: %v\n", set.ProblemID, err)
+			continue
+		}
+		total -= set.Size
+		evicted = append(evicted, CacheEviction{ProblemID: set.ProblemID, Size: set.Size, LastUsed: set.LastUsed, EvictedAt: time.Now()})
+		if config.Verbose {
+			cyan.Printf("🧹 Evicted the tests of problem %s (%s, last used %s)\n", set.ProblemID, formatBytes(set.Size), set.LastUsed.Format("2006-01-02"))
+		}
+	}
+
+	if len(evicted) > 0 {
+		if err := recordEvictions(config, evicted); err != nil {
+			yellow.Printf("⚠️  Failed to record cache evictions: %v\n", err)
+		}
+	}
+}
+
+func loadEvictions(config *Config) ([]CacheEviction, error) {
+	data, err := os.ReadFile(config.GetEvictionLogPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var evictions []CacheEviction
+	if err := json.Unmarshal(data, &evictions); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", config.GetEvictionLogPath(), err)
+	}
+	return evictions, nil
+}
+
+func recordEvictions(config *Config, evicted []CacheEviction) error {
+	evictions, _ := loadEvictions(config)
+	evictions = append(evictions, evicted...)
+	if len(evictions) > maxEvictionLog {
+		evictions = evictions[len(evictions)-maxEvictionLog:]
+	}
+
+	data, err := json.MarshalIndent(evictions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(config.GetEvictionLogPath(), data, 0644)
+}
+
+// handleCache implements `cache info`: where the cache is, what it holds and
+// what the quota recently evicted
+func handleCache(config *Config, args []string) error {
+	if len(args) != 1 || args[0] != "info" {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: cache info"))
+	}
+
+	sets, err := listTestSets(config)
+	if err != nil {
+		return fmt.Errorf("failed to read the cache: %w", err)
+	}
+	var total int64
+	for _, set := range sets {
+		total += set.Size
+	}
+
+	fmt.Printf("Cache:  %s\n", config.CacheDir)
+	fmt.Printf("Config: %s\n", config.ConfigDir)
+	fmt.Printf("Tests:  %d problem(s), %s", len(sets), formatBytes(total))
+	if config.CacheMaxSize != "" {
+		fmt.Printf(" of %s (-cache-max-size)", config.CacheMaxSize)
+	}
+	fmt.Println()
+
+	if len(sets) > 0 {
+		fmt.Printf("\n%-8s %10s  %s\n", "PROBLEM", "SIZE", "LAST USED")
+		for i := len(sets) - 1; i >= 0; i-- {
+			fmt.Printf("%-8s %10s  %s\n", sets[i].ProblemID, formatBytes(sets[i].Size), sets[i].LastUsed.Format("2006-01-02 15:04"))
+		}
+	}
+
+	evictions, err := loadEvictions(config)
+	if err != nil {
+		return err
+	}
+	if len(evictions) > 0 {
+		fmt.Println()
+		yellow.Println("🧹 Recent evictions:")
+		for i := len(evictions) - 1; i >= 0; i-- {
+			eviction := evictions[i]
+			fmt.Printf("  %s  problem %-6s %10s  (last used %s)\n", eviction.EvictedAt.Format("2006-01-02 15:04"),
+				eviction.ProblemID, formatBytes(eviction.Size), eviction.LastUsed.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
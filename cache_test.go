@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListTestSetsCountsLinksOnce(t *testing.T) {
+	config := &Config{CacheDir: t.TempDir()}
+	dir := filepath.Join(config.CacheDir, "1068")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	input := strings.Repeat("1 2 3\n", 100)
+	writer := newDedupWriter()
+	for _, name := range []string{"1.in", "2.in", "3.in"} {
+		if err := writer.write(filepath.Join(dir, name), input); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.write(filepath.Join(dir, "1.out"), "6\n"); err != nil {
+		t.Fatal(err)
+	}
+	if writer.linked != 2 {
+		t.Skip("hard links are not supported here")
+	}
+
+	sets, err := listTestSets(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 1 {
+		t.Fatalf("listTestSets() found %d sets, want 1", len(sets))
+	}
+	if want := int64(len(input) + 2); sets[0].Size != want {
+		t.Errorf("Size = %d, want %d", sets[0].Size, want)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// hashTestSet returns the hex SHA-256 of the numbers and data of the tests,
+// which changes whenever a test is added, removed or edited
+func hashTestSet(testCases []TestCase) (string, error) {
+	h := sha256.New()
+	for _, testCase := range testCases {
+		inputHash, expectedHash, _, _, err := hashTestCase(testCase)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%d\n", testCase.Number)
+		h.Write(inputHash[:])
+		h.Write(expectedHash[:])
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashVerdictConfig returns the hex SHA-256 of the options that can change a
+// verdict: how the solution is built, how it runs and how its output is
+// judged. Runs with a different hash cannot stand in for each other.
+func hashVerdictConfig(config *Config) string {
+	var fileEnv map[string]string
+	if config.EnvFile != "" {
+		fileEnv, _ = parseEnvFile(config.EnvFile)
+	}
+	data, _ := json.Marshal(struct {
+		Tags, GoToolchain, MaxStack, Checks, Docker string
+		CGO, Optimize, Race                         bool
+		Env, EnvAllow                               []string
+		FileEnv                                     map[string]string
+		Args, SolutionGOGC                          string
+		SolutionProcs                               int
+		IOInput, IOOutput                           string
+		Numeric, AllowNonZeroExit                   bool
+		Epsilon                                     float64
+	}{
+		config.Tags, config.GoToolchain, config.MaxStack, config.Checks, config.Docker,
+		config.CGO, config.Optimize, config.Race,
+		config.Env, config.GetEnvAllowlist(),
+		fileEnv,
+		config.Args, config.SolutionGOGC,
+		config.SolutionProcs,
+		config.IOInput, config.IOOutput,
+		config.Numeric, config.AllowNonZeroExit,
+		config.Epsilon,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// findCachedRun returns the latest recorded run of the same source on the same
+// tests, built with the same compiler and options and judged with the same
+// time limit, or nil if the verdicts of no run still hold
+func findCachedRun(config *Config, testsHash string) *RunRecord {
+	sourceHash, err := hashFile(config.FilePath)
+	if err != nil {
+		return nil
+	}
+	timeLimit := config.GetTimeout().Seconds() * 1000
+	configHash := hashVerdictConfig(config)
+
+	record, err := NewHistoryStore(config).Latest(func(record *RunRecord) bool {
+		return record.ProblemID == config.ProblemID && record.SourceHash == sourceHash && record.TestsHash == testsHash &&
+			record.ConfigHash == configHash && record.Compiler == config.GetCompiler() && record.TimeLimit == timeLimit
+	})
+	if err != nil {
+		return nil
+	}
+	return record
+}
+
+// replayRun turns a recorded run back into the results it was made from.
+// Outputs of passing tests were never stored, and those of failing tests may
+// be truncated.
+func (r *TestRunner) replayRun(record *RunRecord, testCases []TestCase) []TestResult {
+	files := make(map[int]TestCase)
+	for _, testCase := range testCases {
+		files[testCase.Number] = testCase
+	}
+
+	results := make([]TestResult, 0, len(record.Tests))
+	for _, test := range record.Tests {
+		result := TestResult{
+			TestNumber:     test.Number,
+			Passed:         test.Passed,
+			Verdict:        test.Verdict,
+			Error:          test.Error,
+			Duration:       time.Duration(test.Duration * float64(time.Millisecond)),
+			ExpectedOutput: test.ExpectedOutput,
+			ActualOutput:   test.ActualOutput,
+			MemoryUsage:    test.MemoryUsage,
+			ExitCode:       test.ExitCode,
+		}
+		result.InputFile, result.ExpectedFile = r.executor.testFiles(files[test.Number])
+		results = append(results, result)
+	}
+	return results
+}
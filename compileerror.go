@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// diagnosticPattern matches a compiler diagnostic, e.g. "./1068.go:6:7: undefined: y"
+var diagnosticPattern = regexp.MustCompile(`^(\S+\.go):(\d+)(?::(\d+))?: (.*)$`)
+
+// Diagnostic is a single error reported by the Go compiler
+type Diagnostic struct {
+	File    string
+	Line    int
+	Column  int // 0 if the compiler gave none
+	Message string
+}
+
+// CompileError is a failed build with its diagnostics parsed out of the
+// compiler output. Output keeps the raw text for anything that did not parse.
+type CompileError struct {
+	Diagnostics []Diagnostic
+	Output      string
+	Err         error
+}
+
+// newCompileError parses the combined output of a failed `go build`
+func newCompileError(err error, output string) *CompileError {
+	compileErr := &CompileError{Output: strings.TrimSpace(output), Err: err}
+
+	for _, line := range strings.Split(output, "\n") {
+		matches := diagnosticPattern.FindStringSubmatch(line)
+		if matches == nil {
+			// Indented lines continue the previous diagnostic (e.g. have/want)
+			if n := len(compileErr.Diagnostics); n > 0 && strings.HasPrefix(line, "\t") {
+				compileErr.Diagnostics[n-1].Message += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+
+		lineNumber, _ := strconv.Atoi(matches[2])
+		column, _ := strconv.Atoi(matches[3])
+		compileErr.Diagnostics = append(compileErr.Diagnostics, Diagnostic{
+			File:    matches[1],
+			Line:    lineNumber,
+			Column:  column,
+			Message: matches[4],
+		})
+	}
+
+	return compileErr
+}
+
+func (e *CompileError) Error() string {
+	if len(e.Diagnostics) == 0 {
+		return fmt.Sprintf("%v\nOutput: %s", e.Err, e.Output)
+	}
+
+	first := e.Diagnostics[0]
+	message := fmt.Sprintf("%s:%d: %s", filepath.Base(first.File), first.Line, strings.SplitN(first.Message, "\n", 2)[0])
+	if len(e.Diagnostics) > 1 {
+		message += fmt.Sprintf(" (and %d more)", len(e.Diagnostics)-1)
+	}
+	return message
+}
+
+func (e *CompileError) Unwrap() error {
+	return e.Err
+}
+
+// displayCompileError prints the diagnostics grouped by file:line, each under
+// the offending source line with a caret at the reported column
+func displayCompileError(config *Config, e *CompileError) {
+	if len(e.Diagnostics) == 0 {
+		return
+	}
+
+	red.Printf("\n🔨 Compilation failed with %d error(s):\n", len(e.Diagnostics))
+
+	sources := make(map[string][]string)
+	for i := 0; i < len(e.Diagnostics); {
+		diag := e.Diagnostics[i]
+
+		// Diagnostics are sorted by position, so one line's errors are adjacent
+		j := i
+		for j < len(e.Diagnostics) && e.Diagnostics[j].File == diag.File && e.Diagnostics[j].Line == diag.Line {
+			j++
+		}
+
+		fmt.Println()
+		cyan.Printf("   %s:%d\n", filepath.Base(diag.File), diag.Line)
+
+		lines, loaded := sources[diag.File]
+		if !loaded {
+			lines = readSourceLines(config, diag.File)
+			sources[diag.File] = lines
+		}
+
+		code := ""
+		if diag.Line >= 1 && diag.Line <= len(lines) {
+			code = lines[diag.Line-1]
+			fmt.Printf("   %4d | %s\n", diag.Line, expandTabs(code))
+		}
+
+		for _, d := range e.Diagnostics[i:j] {
+			caret := ""
+			if d.Column > 0 && code != "" {
+				caret = strings.Repeat(" ", visualColumn(code, d.Column)) + "^ "
+			}
+			message := strings.ReplaceAll(d.Message, "\n", "\n          ")
+			fmt.Printf("        | ")
+			red.Printf("%s%s\n", caret, message)
+		}
+
+		i = j
+	}
+	fmt.Println()
+}
+
+// readSourceLines loads the file a diagnostic refers to. Paths are relative to
+// wherever the build ran (the host or the -docker container), so the solution
+// is matched by name.
+func readSourceLines(config *Config, file string) []string {
+	path := file
+	if filepath.Base(file) == filepath.Base(config.FilePath) {
+		path = config.FilePath
+	} else if !filepath.IsAbs(file) {
+		// Package builds run in the solution directory
+		path = filepath.Join(filepath.Dir(config.FilePath), file)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(string(data), "\n")
+}
+
+func expandTabs(line string) string {
+	return strings.ReplaceAll(line, "\t", "    ")
+}
+
+// visualColumn converts a 1-based byte column into a display offset with
+// tabs expanded as in expandTabs
+func visualColumn(line string, column int) int {
+	offset := 0
+	for i := 0; i < column-1 && i < len(line); i++ {
+		if line[i] == '\t' {
+			offset += 4
+		} else {
+			offset++
+		}
+	}
+	return offset
+}
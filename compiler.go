@@ -2,12 +2,23 @@ package main
 
 import (
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 )
 
+// Compiler backends selectable with -compiler
+const (
+	CompilerGC     = "gc"
+	CompilerGccgo  = "gccgo"
+	CompilerTinyGo = "tinygo"
+)
+
 type GoCompiler struct {
 	config *Config
 }
@@ -16,7 +27,47 @@ func NewGoCompiler(config *Config) *GoCompiler {
 	return &GoCompiler{config: config}
 }
 
+// validateCompiler checks a -compiler value against the other build options
+func validateCompiler(config *Config) error {
+	backend := config.GetCompiler()
+	if err := validateChecks(config); err != nil {
+		return err
+	}
+	if config.MaxStack != "" {
+		if _, err := parseByteSize(config.MaxStack); err != nil {
+			return fmt.Errorf("invalid -max-stack: %w", err)
+		}
+		if backend != CompilerGC || config.Docker != "" {
+			return fmt.Errorf("-max-stack is only supported by the %s compiler on the host, without -docker", CompilerGC)
+		}
+	}
+
+	switch backend {
+	case CompilerGC:
+		return nil
+	case CompilerGccgo, CompilerTinyGo:
+	default:
+		return fmt.Errorf("invalid compiler %q (use %s, %s or %s)", backend, CompilerGC, CompilerGccgo, CompilerTinyGo)
+	}
+
+	if config.Race {
+		return fmt.Errorf("-race is only supported by the %s compiler", CompilerGC)
+	}
+	if config.Docker != "" {
+		return fmt.Errorf("-compiler=%s is not available with -docker (the image only has %s)", backend, CompilerGC)
+	}
+	return nil
+}
+
 func (c *GoCompiler) ValidateGo() error {
+	if c.config.Docker != "" {
+		// The toolchain comes from the image; only docker itself is needed on the host
+		if output, err := exec.Command("docker", "version", "--format", "{{.Server.Version}}").CombinedOutput(); err != nil {
+			return fmt.Errorf("docker is not available: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
 	cmd := exec.Command("go", "version")
 	output, err := cmd.Output()
 	if err != nil {
@@ -27,32 +78,96 @@ func (c *GoCompiler) ValidateGo() error {
 		cyan.Printf("🔍 %s\n", strings.TrimSpace(string(output)))
 	}
 
+	// The go command still type-checks the solution, whichever backend builds it
+	var version *exec.Cmd
+	switch c.config.GetCompiler() {
+	case CompilerGccgo:
+		version = exec.Command("gccgo", "--version")
+	case CompilerTinyGo:
+		version = exec.Command("tinygo", "version")
+	default:
+		return nil
+	}
+
+	output, err = version.Output()
+	if err != nil {
+		return fmt.Errorf("%s is not installed or not in PATH: %w", c.config.GetCompiler(), err)
+	}
+
+	if c.config.Verbose {
+		cyan.Printf("🔍 %s\n", strings.SplitN(strings.TrimSpace(string(output)), "\n", 2)[0])
+	}
+
 	return nil
 }
 
+// ValidateSyntax parses the solution, warns about imports CSES cannot resolve,
+// then type-checks it with a build whose output is discarded. Problems are
+// returned as a *CompileError.
 func (c *GoCompiler) ValidateSyntax() error {
-	// Check if the file compiles without building
-	cmd := exec.Command("go", "run", "-n", c.config.FilePath)
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, c.config.FilePath, nil, 0)
+	if err != nil {
+		return parseErrorDiagnostics(err)
+	}
+	c.warnExternalImports(fset, file)
+
+	// The toolchain lives in the container; the -docker build reports type errors
+	if c.config.Docker != "" {
+		return nil
+	}
+
+	dir, target := c.buildTarget()
+	args := []string{"build", "-o", os.DevNull}
+	args = append(args, c.config.GetTagFlags()...)
+	cmd := exec.Command("go", append(args, target)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), c.config.GetBuildEnv()...)
 
 	if c.config.Verbose {
-		yellow.Printf("🔍 Validating syntax: %s\n", cmd.String())
+		yellow.Printf("🔍 Type-checking: %s\n", cmd.String())
 	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("syntax validation failed: %w", err)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return newCompileError(err, string(output))
 	}
 
 	return nil
 }
 
+// parseErrorDiagnostics converts go/parser errors into a *CompileError
+func parseErrorDiagnostics(err error) error {
+	list, ok := err.(scanner.ErrorList)
+	if !ok {
+		return fmt.Errorf("failed to parse solution: %w", err)
+	}
+
+	compileErr := &CompileError{Output: list.Error(), Err: err}
+	for _, e := range list {
+		compileErr.Diagnostics = append(compileErr.Diagnostics, Diagnostic{
+			File:    e.Pos.Filename,
+			Line:    e.Pos.Line,
+			Column:  e.Pos.Column,
+			Message: e.Msg,
+		})
+	}
+	return compileErr
+}
+
 func (c *GoCompiler) Compile() (string, error) {
 	outputPath := c.getOutputPath()
+	if c.config.Docker != "" {
+		return c.compileInDocker(outputPath)
+	}
 
-	args := []string{"build", "-o", outputPath}
-	args = append(args, c.config.GetBuildFlags()...)
-	args = append(args, c.config.FilePath)
-
-	cmd := exec.Command("go", args...)
+	cmd := c.buildCommand(outputPath)
+	if c.config.MaxStack != "" {
+		cleanup, err := c.addStackLimit(cmd)
+		if err != nil {
+			return "", err
+		}
+		defer cleanup()
+	}
 
 	if c.config.Verbose {
 		yellow.Printf("🔨 Compiling: %s\n", cmd.String())
@@ -61,17 +176,127 @@ func (c *GoCompiler) Compile() (string, error) {
 	// Capture compilation output
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("compilation failed: %w\nOutput: %s", err, string(output))
+		return "", newCompileError(err, string(output))
 	}
 
 	// Verify executable was created
-	if _, err := os.Stat(outputPath); err != nil {
+	info, err := os.Stat(outputPath)
+	if err != nil {
 		return "", fmt.Errorf("executable not created: %w", err)
 	}
+	c.reportBinarySize(info)
 
 	return outputPath, nil
 }
 
+func (c *GoCompiler) reportBinarySize(info os.FileInfo) {
+	if c.config.Verbose {
+		cyan.Printf("📦 Binary size: %s (%s)\n", formatBytes(info.Size()), strings.Join(c.config.GetBuildEnv(), " "))
+	}
+}
+
+// buildCommand returns the build of the solution with the selected backend
+func (c *GoCompiler) buildCommand(outputPath string) *exec.Cmd {
+	dir, target := c.buildTarget()
+
+	var cmd *exec.Cmd
+	switch c.config.GetCompiler() {
+	case CompilerTinyGo:
+		// TinyGo has its own flags; -ldflags and -race do not apply
+		args := []string{"build", "-o", outputPath}
+		args = append(args, c.config.GetTagFlags()...)
+		if c.config.Optimize {
+			args = append(args, "-no-debug")
+		}
+		cmd = exec.Command("tinygo", append(args, target)...)
+	case CompilerGccgo:
+		args := []string{"build", "-compiler=gccgo", "-o", outputPath}
+		args = append(args, c.config.GetBuildFlags()...)
+		cmd = exec.Command("go", append(args, target)...)
+	default:
+		args := []string{"build", "-o", outputPath}
+		args = append(args, c.config.GetBuildFlags()...)
+		cmd = exec.Command("go", append(args, target)...)
+	}
+
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), c.config.GetBuildEnv()...)
+	return cmd
+}
+
+// buildTarget returns the directory to run `go build` in and what to build.
+// A solution that is the only main function in its module directory is built
+// as the whole package, so build-tagged siblings and //go:embed work. In a
+// folder of solutions (or outside a module) only the file itself is built.
+func (c *GoCompiler) buildTarget() (dir, target string) {
+	solution, err := filepath.Abs(c.config.FilePath)
+	if err != nil {
+		return "", c.config.FilePath
+	}
+	dir = filepath.Dir(solution)
+
+	// The container only sees the solution directory, so go.mod must be in it
+	inModule := fileExists(filepath.Join(dir, "go.mod"))
+	if !inModule && c.config.Docker == "" {
+		inModule = findGoMod(dir) != ""
+	}
+
+	if !inModule || hasOtherMain(dir, solution) {
+		return "", c.config.FilePath
+	}
+
+	return dir, "."
+}
+
+// hasOtherMain reports whether another Go file in dir declares func main
+func hasOtherMain(dir, solution string) bool {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return true
+	}
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if file == solution || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		parsed, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return true
+		}
+		if parsed.Name.Name != "main" {
+			return true
+		}
+		for _, decl := range parsed.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// findGoMod returns the go.mod governing dir, or "" outside a module
+func findGoMod(dir string) string {
+	for {
+		if path := filepath.Join(dir, "go.mod"); fileExists(path) {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
 func (c *GoCompiler) getOutputPath() string {
 	dir, _ := filepath.Abs(filepath.Dir(c.config.FilePath))
 	base := strings.TrimSuffix(filepath.Base(c.config.FilePath), ".go")
@@ -89,3 +314,31 @@ func (c *GoCompiler) GetModuleInfo() (string, error) {
 
 	return strings.TrimSpace(string(output)), nil
 }
+
+// A binary this much larger than in the previous run of the problem, and by at
+// least binaryGrowthMin, suggests a heavy new import
+const (
+	binaryGrowthRatio = 1.25
+	binaryGrowthMin   = 512 << 10
+)
+
+// checkBinaryGrowth warns when the binary grew a lot since the last recorded
+// run of the problem with the same compiler. Importing fmt, reflect-heavy
+// packages such as encoding/json, or anything outside the standard library
+// shows up in the size long before it shows up in the timings.
+func checkBinaryGrowth(config *Config, size int64) {
+	if size == 0 {
+		return
+	}
+	previous, err := NewHistoryStore(config).Latest(func(record *RunRecord) bool {
+		return record.ProblemID == config.ProblemID && record.BinarySize > 0 && record.Compiler == config.GetCompiler()
+	})
+	if err != nil || previous == nil {
+		return
+	}
+
+	if float64(size) >= binaryGrowthRatio*float64(previous.BinarySize) && size-previous.BinarySize >= binaryGrowthMin {
+		yellow.Printf("⚠️  The binary grew from %s to %s since the run of %s; check for a new heavy import\n",
+			formatBytes(previous.BinarySize), formatBytes(size), previous.StartedAt.Local().Format("2006-01-02 15:04"))
+	}
+}
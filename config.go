@@ -1,21 +1,121 @@
 package main
 
 import (
+	"net/url"
+	"strings"
 	"time"
 )
 
+// DefaultBaseURL is the CSES instance used unless -base-url overrides it
+const DefaultBaseURL = "https://cses.fi"
+
+// Supported values for -summary
+const (
+	SummaryTable        = "table"
+	SummaryCompact      = "compact"
+	SummaryFailuresOnly = "failures-only"
+)
+
 type Config struct {
 	FilePath  string
 	ProblemID string
 	Timeout   string
 	Verbose   bool
 	CacheDir  string
+	ConfigDir string
 	Parallel  int
 	ShowDiff  bool
 	MaxOutput int
+	Summary   string
 	Optimize  bool
 	Race      bool
+	RaceTests int // tests of the separate race detector pass, 0 for all
+	Checks    string
 	ForceAuth bool
+	Repeat    int
+	Test      int
+	Attach    bool
+	Stream    bool
+
+	// RecordedPath is the solution path kept in history when FilePath is a
+	// copy of it, such as the staged version tested by the git hook
+	RecordedPath string
+
+	Order       string
+	ShuffleSeed int64
+
+	CachedResults bool // replay the recorded run when the source and tests are unchanged
+
+	EnvAllow string
+	Env      []string // KEY=VAL set for the solution (-env)
+	EnvFile  string
+
+	Args string // command line arguments of the solution (-args)
+
+	SolutionGOGC  string
+	SolutionProcs int
+	GOGCSweep     bool
+	GCStats       bool // trace the solution's garbage collections
+	MaxStack      string
+	Monitor       string
+
+	Notify        bool
+	NotifyWebhook string
+	NotifyMin     string
+
+	ReportHTML     string
+	ReportMarkdown string
+
+	SaveFailed bool
+	FailedDir  string
+
+	BaseURL      string
+	RefreshPages bool   // fetch scraped pages again instead of using the page cache
+	Account      string // named CSES account (-account), see GetAccount
+
+	RecordFixtures string
+	ReplayFixtures string
+
+	IsolateTiming bool
+	Warmup        bool
+
+	Docker string
+
+	AllowNonZeroExit bool
+
+	Tags string
+	CGO  bool
+
+	Compiler    string
+	GoToolchain string // GOTOOLCHAIN of the build, set per version by bench -go-versions
+
+	DumpOutputDir  string
+	ShowWhitespace bool
+
+	Numeric bool
+	Epsilon float64
+
+	GitCommitOnPass bool
+
+	HooksFile string
+
+	CacheMaxSize string
+	RefreshTests bool
+
+	// Tests is where the tests come from when not from CSES (set by a manifest)
+	Tests *TestSourceSpec
+
+	ShowNotes bool
+
+	// File-based I/O: the solution reads IOInput and writes IOOutput in its
+	// working directory instead of using stdin and stdout
+	IOInput  string
+	IOOutput string
+}
+
+// FileIO reports whether the solution reads or writes named files
+func (c *Config) FileIO() bool {
+	return c.IOInput != "" || c.IOOutput != ""
 }
 
 func (c *Config) GetTimeout() time.Duration {
@@ -26,10 +126,75 @@ func (c *Config) GetTimeout() time.Duration {
 	return duration
 }
 
+// GetMonitorInterval returns how often -monitor samples the solution, 0 when
+// it does not
+func (c *Config) GetMonitorInterval() time.Duration {
+	interval, err := time.ParseDuration(c.Monitor)
+	if err != nil {
+		return 0
+	}
+	return interval
+}
+
+// GetEnvAllowlist returns the extra host variables passed through to the solution
+func (c *Config) GetEnvAllowlist() []string {
+	return parseList(c.EnvAllow)
+}
+
+// GetSolutionArgs splits -args into the solution's arguments. Arguments are
+// separated by spaces; single or double quotes keep spaces in one argument.
+func (c *Config) GetSolutionArgs() []string {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range c.Args {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// GetNotifyMinDuration returns how long a run must take before notifications are sent
+func (c *Config) GetNotifyMinDuration() time.Duration {
+	duration, err := time.ParseDuration(c.NotifyMin)
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
+// GetTagFlags returns the -tags argument for go build, if any tags are set
+func (c *Config) GetTagFlags() []string {
+	if tags := parseList(c.Tags); len(tags) > 0 {
+		return []string{"-tags", strings.Join(tags, ",")}
+	}
+	return nil
+}
+
 func (c *Config) GetBuildFlags() []string {
-	var flags []string
+	flags := c.GetTagFlags()
 
-	if c.Optimize {
+	// Sanitizer reports need the symbol table to name the faulting line
+	if c.Optimize && c.Checks == "" {
 		flags = append(flags, "-ldflags", "-s -w")
 	}
 
@@ -37,13 +202,114 @@ func (c *Config) GetBuildFlags() []string {
 		flags = append(flags, "-race")
 	}
 
+	if c.Checks != "" {
+		flags = append(flags, "-"+c.Checks)
+	}
+
 	return flags
 }
 
+// GetBuildEnv returns the environment overrides for compiling the solution.
+// CGO is off by default so the binary is static and runs in -docker or on a
+// machine without a C toolchain; -race and -checks need CGO and turn it back on.
+func (c *Config) GetBuildEnv() []string {
+	env := []string{"CGO_ENABLED=0"}
+	if c.CGO || c.Race || c.Checks != "" {
+		env = []string{"CGO_ENABLED=1"}
+	}
+	if c.GoToolchain != "" {
+		env = append(env, "GOTOOLCHAIN="+c.GoToolchain)
+	}
+	return env
+}
+
+// GetCompiler returns the compiler backend, gc unless -compiler says otherwise
+func (c *Config) GetCompiler() string {
+	if c.Compiler == "" {
+		return CompilerGC
+	}
+	return c.Compiler
+}
+
 func (c *Config) GetAuthCacheDir() string {
 	return c.CacheDir + "/.auth"
 }
 
+// GetPageCacheDir returns where scraped CSES pages are cached
+func (c *Config) GetPageCacheDir() string {
+	return c.CacheDir + "/pages"
+}
+
+func (c *Config) GetHistoryDir() string {
+	return c.CacheDir + "/history"
+}
+
+// GetPracticeSessionPath returns where the active `session` is recorded
+func (c *Config) GetPracticeSessionPath() string {
+	return c.CacheDir + "/practice-session.json"
+}
+
+// GetProblemsetPath returns where the problem list fetched by `suggest` is cached
+func (c *Config) GetProblemsetPath() string {
+	return c.CacheDir + "/problemset.json"
+}
+
+// GetEvictionLogPath returns where test sets evicted by -cache-max-size are logged
+func (c *Config) GetEvictionLogPath() string {
+	return c.CacheDir + "/evictions.json"
+}
+
+// GetAliasesPath returns where problem aliases set with `alias add` are stored
+func (c *Config) GetAliasesPath() string {
+	return c.ConfigDir + "/aliases.yaml"
+}
+
+// GetNotesPath returns where the study notes of a problem are kept. Notes are
+// the user's own writing, so they live in the config directory and survive
+// cache cleaning and eviction of the problem's tests.
+func (c *Config) GetNotesPath(problemID string) string {
+	return c.ConfigDir + "/notes/" + problemID + ".md"
+}
+
+// GetTemplatesDir returns where user templates for `new` are stored
+func (c *Config) GetTemplatesDir() string {
+	return c.ConfigDir + "/templates"
+}
+
+// GetHooksPath returns the lifecycle hooks file, -hooks or hooks.yaml in the config directory
+func (c *Config) GetHooksPath() string {
+	if c.HooksFile != "" {
+		return c.HooksFile
+	}
+	return c.ConfigDir + "/hooks.yaml"
+}
+
+// GetVirtualContestPath returns where the active `practice` contest is recorded
+func (c *Config) GetVirtualContestPath() string {
+	return c.CacheDir + "/virtual-contest.json"
+}
+
+// GetBaseURL returns the CSES base URL without a trailing slash
+func (c *Config) GetBaseURL() string {
+	if c.BaseURL == "" {
+		return DefaultBaseURL
+	}
+	return strings.TrimRight(c.BaseURL, "/")
+}
+
+// GetSessionFile returns the session path. Sessions for a non-default base URL
+// (e.g. a local fake CSES) are kept apart so they never replace the real one.
+// Fixture runs keep the session in memory only, so every run logs in from
+// scratch and issues the same requests. Named accounts have a directory each.
 func (c *Config) GetSessionFile() string {
-	return c.GetAuthCacheDir() + "/session.json"
+	if c.RecordFixtures != "" || c.ReplayFixtures != "" {
+		return ""
+	}
+	dir := c.accountSessionDir(c.GetAccount())
+	if base := c.GetBaseURL(); base != DefaultBaseURL {
+		if parsed, err := url.Parse(base); err == nil && parsed.Host != "" {
+			return dir + "/session-" + strings.ReplaceAll(parsed.Host, ":", "_") + ".json"
+		}
+	}
+	return dir + "/session.json"
 }
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffLine is one line of a line-by-line comparison between expected and actual output
+type DiffLine struct {
+	Number   int
+	Expected string
+	Actual   string
+	Kind     string // "same", "changed", "missing" or "extra"
+}
+
+// lineDiff compares outputs line by line using the same normalization as the
+// executor, so only lines that actually caused a mismatch are marked.
+func lineDiff(expected, actual string) []DiffLine {
+	expectedLines := splitOutputLines(expected)
+	actualLines := splitOutputLines(actual)
+
+	n := len(expectedLines)
+	if len(actualLines) > n {
+		n = len(actualLines)
+	}
+
+	lines := make([]DiffLine, 0, n)
+	for i := 0; i < n; i++ {
+		line := DiffLine{Number: i + 1}
+
+		switch {
+		case i >= len(actualLines):
+			line.Expected = expectedLines[i]
+			line.Kind = "missing"
+		case i >= len(expectedLines):
+			line.Actual = actualLines[i]
+			line.Kind = "extra"
+		default:
+			line.Expected = expectedLines[i]
+			line.Actual = actualLines[i]
+			line.Kind = "same"
+			if strings.TrimRight(line.Expected, " \t\r") != strings.TrimRight(line.Actual, " \t\r") {
+				line.Kind = "changed"
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// shapeMismatch describes how the structure of actual differs from expected:
+// the line count and the tokens per line. An off-by-one loop shows up here
+// long before it is obvious in the diff. It returns nil when both outputs have
+// the same shape and only values differ.
+func shapeMismatch(expected, actual string) []string {
+	expectedCounts := tokenCounts(splitOutputLines(expected))
+	actualCounts := tokenCounts(splitOutputLines(actual))
+
+	var mismatches []string
+	if len(expectedCounts) != len(actualCounts) {
+		mismatches = append(mismatches, fmt.Sprintf("expected %d lines / got %d", len(expectedCounts), len(actualCounts)))
+	}
+
+	expectedPerLine, expectedUniform := uniformCount(expectedCounts)
+	actualPerLine, actualUniform := uniformCount(actualCounts)
+	if expectedUniform && actualUniform && expectedPerLine != actualPerLine {
+		return append(mismatches, fmt.Sprintf("expected %d tokens per line / got %d", expectedPerLine, actualPerLine))
+	}
+
+	for i := 0; i < len(expectedCounts) && i < len(actualCounts); i++ {
+		if expectedCounts[i] != actualCounts[i] {
+			mismatches = append(mismatches, fmt.Sprintf("line %d: expected %d tokens / got %d", i+1, expectedCounts[i], actualCounts[i]))
+			break
+		}
+	}
+
+	expectedTotal, actualTotal := sum(expectedCounts), sum(actualCounts)
+	if len(mismatches) > 0 && expectedTotal != actualTotal {
+		mismatches = append(mismatches, fmt.Sprintf("expected %d tokens in total / got %d", expectedTotal, actualTotal))
+	}
+
+	return mismatches
+}
+
+func tokenCounts(lines []string) []int {
+	counts := make([]int, len(lines))
+	for i, line := range lines {
+		counts[i] = len(strings.Fields(line))
+	}
+	return counts
+}
+
+// uniformCount reports whether every line has the same number of tokens
+func uniformCount(counts []int) (int, bool) {
+	if len(counts) == 0 {
+		return 0, false
+	}
+	for _, count := range counts[1:] {
+		if count != counts[0] {
+			return 0, false
+		}
+	}
+	return counts[0], true
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+func splitOutputLines(output string) []string {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
+}
+
+// visualizeWhitespace makes invisible characters visible: tabs as →, carriage
+// returns as ␍ and trailing spaces as ·, one line at a time
+func visualizeWhitespace(output string) string {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimRight(line, " \r")
+		trailing := line[len(trimmed):]
+		trailing = strings.NewReplacer(" ", "·", "\r", "␍").Replace(trailing)
+		lines[i] = strings.NewReplacer("\t", "→", "\r", "␍").Replace(trimmed) + trailing
+	}
+	return strings.Join(lines, "\n")
+}
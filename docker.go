@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultDockerImage is used by a bare -docker, pinned to the Go release in go.mod
+const defaultDockerImage = "golang:1.23.4-bookworm"
+
+// Limits of the container tests run in, close to the CSES judge
+const (
+	dockerCPUs   = "1"
+	dockerMemory = "512m"
+	dockerPids   = "64"
+)
+
+// dockerImageFlag implements both -docker (default image) and -docker=image
+type dockerImageFlag string
+
+func (f *dockerImageFlag) String() string { return string(*f) }
+
+func (f *dockerImageFlag) Set(value string) error {
+	switch value {
+	case "true":
+		*f = defaultDockerImage
+	case "false":
+		*f = ""
+	default:
+		*f = dockerImageFlag(value)
+	}
+	return nil
+}
+
+func (f *dockerImageFlag) IsBoolFlag() bool { return true }
+
+// DockerSandbox is a long-lived, network-less container with the solution's
+// directory mounted at /work. Every test is a `docker exec` into it, which
+// avoids paying container start-up time per test.
+type DockerSandbox struct {
+	id    string
+	image string
+
+	// memory is the container's memory cgroup on the host, if it was found
+	memory *cgroupMemory
+}
+
+// cgroupMemory names the files of a memory cgroup holding its current and
+// peak usage, which are the same for cgroup v2 and v1 in all but name
+type cgroupMemory struct {
+	current, peak string
+}
+
+// dockerMemoryPoll is how often the container's memory is read while a test
+// runs, for kernels that cannot tell the peak of a single test
+const dockerMemoryPoll = 5 * time.Millisecond
+
+// dockerWorkDir is where the solution directory is mounted in the container
+const dockerWorkDir = "/work"
+
+// dockerUserArgs runs container processes as the host user so build output
+// in the mounted directory is not owned by root
+func dockerUserArgs() []string {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	return []string{"--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())}
+}
+
+// solutionDir is the absolute directory of the solution, mounted into containers
+func solutionDir(config *Config) (string, error) {
+	dir, err := filepath.Abs(filepath.Dir(config.FilePath))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve solution directory: %w", err)
+	}
+	return dir, nil
+}
+
+// compileInDocker builds the solution with the image's toolchain. The build
+// container has no network, so only standard-library solutions compile.
+func (c *GoCompiler) compileInDocker(outputPath string) (string, error) {
+	dir, err := solutionDir(c.config)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"run", "--rm", "--network", "none",
+		"-v", dir + ":" + dockerWorkDir, "-w", dockerWorkDir,
+		"-e", "GOCACHE=/tmp/gocache", "-e", "GOTOOLCHAIN=local"}
+	for _, entry := range c.config.GetBuildEnv() {
+		args = append(args, "-e", entry)
+	}
+	args = append(args, dockerUserArgs()...)
+	target := filepath.Base(c.config.FilePath)
+	if _, pkg := c.buildTarget(); pkg == "." {
+		target = "."
+	}
+
+	args = append(args, c.config.Docker, "go", "build", "-o", dockerWorkDir+"/"+filepath.Base(outputPath))
+	args = append(args, c.config.GetBuildFlags()...)
+	args = append(args, target)
+
+	cmd := exec.Command("docker", args...)
+	if c.config.Verbose {
+		yellow.Printf("🐳 Compiling: %s\n", cmd.String())
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", newCompileError(err, string(output))
+	}
+
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		return "", fmt.Errorf("executable not created: %w", err)
+	}
+	c.reportBinarySize(info)
+
+	return outputPath, nil
+}
+
+// StartDockerSandbox starts the container the tests run in
+func StartDockerSandbox(config *Config) (*DockerSandbox, error) {
+	dir, err := solutionDir(config)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"run", "-d", "--rm", "--network", "none",
+		"--cpus", dockerCPUs, "--memory", dockerMemory, "--memory-swap", dockerMemory, "--pids-limit", dockerPids,
+		"-v", dir + ":" + dockerWorkDir + ":ro", "-w", dockerWorkDir}
+	args = append(args, dockerUserArgs()...)
+	args = append(args, config.Docker, "sleep", "infinity")
+
+	output, err := exec.Command("docker", args...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start container from %s: %w\nOutput: %s", config.Docker, err, strings.TrimSpace(string(output)))
+	}
+
+	sandbox := &DockerSandbox{id: strings.TrimSpace(string(output)), image: config.Docker}
+	sandbox.memory = findContainerMemory(sandbox.id)
+	if config.Verbose {
+		cyan.Printf("🐳 Started container %.12s (%s, %s CPU, %s memory, no network)\n", sandbox.id, sandbox.image, dockerCPUs, dockerMemory)
+	}
+
+	return sandbox, nil
+}
+
+// Command runs the compiled solution with args inside the container. env is
+// passed explicitly since `docker exec` does not inherit it, and `timeout`
+// kills the solution in the container if the docker client is killed on our side.
+func (s *DockerSandbox) Command(ctx context.Context, executablePath string, args, env []string, limit time.Duration) *exec.Cmd {
+	dockerArgs := []string{"exec", "-i"}
+	for _, entry := range env {
+		dockerArgs = append(dockerArgs, "-e", entry)
+	}
+
+	dockerArgs = append(dockerArgs, s.id)
+	if limit > 0 {
+		dockerArgs = append(dockerArgs, "timeout", "-s", "KILL", fmt.Sprintf("%.3f", (limit+time.Second).Seconds()))
+	}
+	dockerArgs = append(dockerArgs, dockerWorkDir+"/"+filepath.Base(executablePath))
+	dockerArgs = append(dockerArgs, args...)
+
+	return exec.CommandContext(ctx, "docker", dockerArgs...)
+}
+
+// findContainerMemory locates the memory cgroup of a container on the host
+// from its init process. It is nil where the cgroup is out of reach, as with
+// Docker Desktop's virtual machine; memory usage is then unknown.
+func findContainerMemory(id string) *cgroupMemory {
+	output, err := exec.Command("docker", "inspect", "--format", "{{.State.Pid}}", id).Output()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile("/proc/" + strings.TrimSpace(string(output)) + "/cgroup")
+	if err != nil {
+		return nil
+	}
+	memory := parseMemoryCgroup(string(data), "/sys/fs/cgroup")
+	if memory == nil {
+		return nil
+	}
+	if _, err := os.Stat(memory.current); err != nil {
+		return nil
+	}
+	return memory
+}
+
+// parseMemoryCgroup finds the memory cgroup in the contents of
+// /proc/<pid>/cgroup: the "memory" controller of cgroup v1, or else the
+// unified cgroup v2 hierarchy
+func parseMemoryCgroup(procCgroup, root string) *cgroupMemory {
+	var unified string
+	for _, line := range strings.Split(strings.TrimSpace(procCgroup), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if slices.Contains(strings.Split(parts[1], ","), "memory") {
+			dir := filepath.Join(root, "memory", parts[2])
+			return &cgroupMemory{current: filepath.Join(dir, "memory.usage_in_bytes"), peak: filepath.Join(dir, "memory.max_usage_in_bytes")}
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			unified = parts[2]
+		}
+	}
+	if unified == "" {
+		return nil
+	}
+	dir := filepath.Join(root, unified)
+	return &cgroupMemory{current: filepath.Join(dir, "memory.current"), peak: filepath.Join(dir, "memory.peak")}
+}
+
+// readCgroupValue reads a byte count from a cgroup file, 0 if it cannot
+func readCgroupValue(path string) int64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	value, _ := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	return value
+}
+
+// watchMemory follows the container's memory while one test runs; the
+// returned function stops watching and reports the test's peak usage. Tests
+// run one at a time in the container, so its usage is the solution's. When
+// the container's all-time peak rose during the test that is the test's
+// exact peak; otherwise the highest sampled usage is the best estimate.
+func (s *DockerSandbox) watchMemory() func() int64 {
+	if s.memory == nil {
+		return func() int64 { return 0 }
+	}
+	peakBefore := readCgroupValue(s.memory.peak)
+	stop := make(chan struct{})
+	done := make(chan int64)
+	go func() {
+		ticker := time.NewTicker(dockerMemoryPoll)
+		defer ticker.Stop()
+		var highest int64
+		for {
+			highest = max(highest, readCgroupValue(s.memory.current))
+			select {
+			case <-stop:
+				done <- highest
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() int64 {
+		close(stop)
+		highest := <-done
+		if peak := readCgroupValue(s.memory.peak); peak > peakBefore {
+			return peak
+		}
+		return highest
+	}
+}
+
+// Stop removes the container
+func (s *DockerSandbox) Stop() {
+	exec.Command("docker", "rm", "-f", s.id).Run()
+}
+
+// containerEnv drops host-specific variables from the solution environment,
+// which mean nothing inside the image
+func containerEnv(env []string) []string {
+	var result []string
+	for _, entry := range env {
+		name := strings.SplitN(entry, "=", 2)[0]
+		if name == "PATH" || name == "HOME" || name == "TMPDIR" {
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
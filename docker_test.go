@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMemoryCgroup(t *testing.T) {
+	tests := []struct {
+		name       string
+		procCgroup string
+		want       *cgroupMemory
+	}{
+		{
+			name:       "cgroup v2",
+			procCgroup: "0::/system.slice/docker-abc.scope\n",
+			want: &cgroupMemory{
+				current: "/sys/fs/cgroup/system.slice/docker-abc.scope/memory.current",
+				peak:    "/sys/fs/cgroup/system.slice/docker-abc.scope/memory.peak",
+			},
+		},
+		{
+			name:       "cgroup v1",
+			procCgroup: "12:pids:/docker/abc\n4:memory:/docker/abc\n2:cpu,cpuacct:/docker/abc\n0::/\n",
+			want: &cgroupMemory{
+				current: "/sys/fs/cgroup/memory/docker/abc/memory.usage_in_bytes",
+				peak:    "/sys/fs/cgroup/memory/docker/abc/memory.max_usage_in_bytes",
+			},
+		},
+		{
+			name:       "no memory controller",
+			procCgroup: "3:cpu:/docker/abc\n",
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseMemoryCgroup(tt.procCgroup, "/sys/fs/cgroup"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMemoryCgroup() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
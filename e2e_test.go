@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/anurag5sh/cses-go-runner/internal/fakecses"
+)
+
+// startFakeCSES serves a fake CSES with problem 1068 and returns a
+// configuration pointing at it, with the account's credentials in the
+// environment and an empty cache
+func startFakeCSES(t *testing.T) (*fakecses.Server, *Config) {
+	t.Helper()
+	fake := fakecses.New("demo", "secret")
+	fake.AddProblem("1068", []fakecses.TestCase{
+		{Input: "3\n", Output: "3 10 5 16 8 4 2 1\n"},
+		{Input: "1\n", Output: "1\n"},
+	})
+	server := fake.Start()
+	t.Cleanup(server.Close)
+
+	t.Setenv("CSES_USERNAME", "demo")
+	t.Setenv("CSES_PASSWORD", "secret")
+	return fake, &Config{BaseURL: server.URL, CacheDir: t.TempDir()}
+}
+
+func TestE2ELogin(t *testing.T) {
+	_, config := startFakeCSES(t)
+
+	auth := NewCSESAuth(config)
+	if err := auth.Login(); err != nil {
+		t.Fatalf("Login() = %v", err)
+	}
+	// A new client picks up the saved session instead of logging in again
+	if err := NewCSESAuth(config).EnsureAuthenticated(); err != nil {
+		t.Fatalf("EnsureAuthenticated() with the saved session = %v", err)
+	}
+
+	t.Setenv("CSES_PASSWORD", "wrong")
+	config.CacheDir = t.TempDir()
+	if err := NewCSESAuth(config).Login(); err == nil {
+		t.Error("Login() with a wrong password succeeded")
+	}
+}
+
+func TestE2EDownloadTests(t *testing.T) {
+	fake, config := startFakeCSES(t)
+	auth := NewCSESAuth(config)
+	fetcher := NewTestCaseFetcher(config, auth)
+
+	testCases, err := fetcher.FetchTestCases("1068")
+	if err != nil {
+		t.Fatalf("FetchTestCases() = %v", err)
+	}
+	if len(testCases) != 2 {
+		t.Fatalf("got %d tests, want 2", len(testCases))
+	}
+	first, err := testCases[0].Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Number != 1 || first.Input != "3\n" || first.Expected != "3 10 5 16 8 4 2 1\n" {
+		t.Errorf("test 1 = %+v", first)
+	}
+
+	// The archive has not changed, so asking with its ETag gets a 304
+	meta := loadArchiveMeta(filepath.Join(config.CacheDir, "1068"))
+	if meta == nil || meta.ETag == "" {
+		t.Fatalf("archive metadata = %+v, want an ETag", meta)
+	}
+	if _, _, err := auth.DownloadTestCases("1068", meta, nil); !errors.Is(err, ErrNotModified) {
+		t.Fatalf("DownloadTestCases() with the cached ETag = %v, want ErrNotModified", err)
+	}
+	config.RefreshTests = true
+	if testCases, err := fetcher.FetchTestCases("1068"); err != nil || len(testCases) != 2 {
+		t.Fatalf("refresh of unchanged tests = %d tests, %v", len(testCases), err)
+	}
+
+	// A changed archive replaces the cached tests
+	fake.AddProblem("1068", []fakecses.TestCase{{Input: "2\n", Output: "2 1\n"}})
+	testCases, err = fetcher.FetchTestCases("1068")
+	if err != nil {
+		t.Fatalf("refresh of changed tests = %v", err)
+	}
+	if len(testCases) != 1 {
+		t.Fatalf("got %d tests after the change, want 1", len(testCases))
+	}
+	changed, err := testCases[0].Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.Input != "2\n" {
+		t.Errorf("test 1 input after the change = %q, want %q", changed.Input, "2\n")
+	}
+}
+
+func TestE2ESubmit(t *testing.T) {
+	fake, config := startFakeCSES(t)
+	auth := NewCSESAuth(config)
+	if err := auth.EnsureAuthenticated(); err != nil {
+		t.Fatal(err)
+	}
+
+	source := []byte("package main\n\nfunc main() {}\n")
+	resultURL, err := auth.SubmitSolution("1068", "main.go", source, "Go", "")
+	if err != nil {
+		t.Fatalf("SubmitSolution() = %v", err)
+	}
+	verdict, err := awaitVerdict(auth, resultURL)
+	if err != nil {
+		t.Fatalf("awaitVerdict() = %v", err)
+	}
+	if verdict != "ACCEPTED" {
+		t.Errorf("verdict = %q, want ACCEPTED", verdict)
+	}
+
+	submissions := fake.Submissions()
+	if len(submissions) != 1 {
+		t.Fatalf("the fake received %d submissions, want 1", len(submissions))
+	}
+	got := submissions[0]
+	if got.Username != "demo" || got.TaskID != "1068" || got.Language != "Go" || got.FileName != "main.go" || got.Source != string(source) {
+		t.Errorf("submission = %+v", got)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultEnvAllowlist are the only host variables passed to the solution
+// unless more are allowed with -env-allow
+var defaultEnvAllowlist = []string{"PATH", "HOME", "TMPDIR"}
+
+// windowsEnvAllowlist are needed for processes to start at all on Windows
+var windowsEnvAllowlist = []string{"SYSTEMROOT", "SYSTEMDRIVE", "TEMP", "TMP", "USERPROFILE"}
+
+// fixedEnv pins timezone and locale so output never depends on the host
+var fixedEnv = map[string]string{
+	"TZ":     "UTC",
+	"LANG":   "C",
+	"LC_ALL": "C",
+}
+
+// envFlag collects repeated -env KEY=VAL flags
+type envFlag []string
+
+func (f *envFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *envFlag) Set(value string) error {
+	if name, _, ok := strings.Cut(value, "="); !ok || name == "" {
+		return fmt.Errorf("expected KEY=VAL, got %q", value)
+	}
+	*f = append(*f, value)
+	return nil
+}
+
+// parseEnvFile reads KEY=VAL lines; blank lines, # comments, an `export `
+// prefix and quotes around the value are allowed, as in shell .env files
+func parseEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	defer file.Close()
+
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VAL", path, lineNumber)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		env[name] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	return env, nil
+}
+
+// defaultDotenv is read from the working directory unless -dotenv names
+// another file
+const defaultDotenv = ".env"
+
+// loadDotenv sets the runner's own environment (credentials, webhook, tokens)
+// from a .env file. Variables already set in the shell win over the file, so
+// the file only fills in what is missing. A missing ./.env is not an error;
+// a missing -dotenv file is. It returns the names it set.
+func loadDotenv(path string) ([]string, error) {
+	explicit := path != ""
+	if !explicit {
+		path = defaultDotenv
+	}
+	if _, err := os.Stat(path); err != nil && !explicit {
+		return nil, nil
+	}
+
+	fileEnv, err := parseEnvFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set []string
+	for name, value := range fileEnv {
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		set = append(set, name)
+	}
+	sort.Strings(set)
+	return set, nil
+}
+
+// solutionEnv builds the scrubbed environment the solution process runs with:
+// allowlisted host variables plus fixed timezone/locale settings, then the
+// variables of -env-file and -env. Go runtime knobs reach the solution when
+// allowlisted or set via -solution-gogc and -solution-procs, which take
+// precedence.
+func solutionEnv(config *Config) []string {
+	allowed := append([]string{}, defaultEnvAllowlist...)
+	if runtime.GOOS == "windows" {
+		allowed = append(allowed, windowsEnvAllowlist...)
+	}
+	allowed = append(allowed, config.GetEnvAllowlist()...)
+
+	env := make(map[string]string)
+	for _, name := range allowed {
+		if value, ok := os.LookupEnv(name); ok {
+			env[name] = value
+		}
+	}
+
+	for name, value := range fixedEnv {
+		if _, overridden := env[name]; !overridden {
+			env[name] = value
+		}
+	}
+
+	if config.EnvFile != "" {
+		// Validated before the run, so an error cannot happen here
+		fileEnv, _ := parseEnvFile(config.EnvFile)
+		for name, value := range fileEnv {
+			env[name] = value
+		}
+	}
+	for _, entry := range config.Env {
+		name, value, _ := strings.Cut(entry, "=")
+		env[name] = value
+	}
+
+	if config.SolutionGOGC != "" {
+		env["GOGC"] = config.SolutionGOGC
+	}
+	if config.SolutionProcs > 0 {
+		env["GOMAXPROCS"] = strconv.Itoa(config.SolutionProcs)
+	}
+	if config.GCStats {
+		if env["GODEBUG"] != "" {
+			env["GODEBUG"] += ","
+		}
+		env["GODEBUG"] += "gctrace=1"
+	}
+
+	result := make([]string, 0, len(env))
+	for name, value := range env {
+		result = append(result, name+"="+value)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// parseList splits a comma separated flag value, dropping empty entries
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -4,8 +4,13 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -13,83 +18,315 @@ import (
 type TestResult struct {
 	TestNumber     int
 	Passed         bool
+	Verdict        Verdict
 	Error          string
 	Duration       time.Duration
 	ActualOutput   string
 	ExpectedOutput string
+	Stderr         string
 	InputFile      string
 	ExpectedFile   string
-	MemoryUsage    string
+	MemoryUsage    int64 // peak RSS in bytes, 0 if unknown
 	ExitCode       int
+	GC             *GCStats // set with -gc-stats
+	StackOverflow  *StackOverflow
+	Timeline       []ResourceSample // set with -monitor
+
+	// Set when the test was executed more than once (-repeat)
+	Runs        int
+	PassedRuns  int
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	StdDev      time.Duration
+}
+
+// processOutput is what a single execution of the solution produced
+type processOutput struct {
+	Stdout      string
+	Stderr      string
+	ExitCode    int
+	MemoryUsage int64
+	Timeline    []ResourceSample
 }
 
 type TestExecutor struct {
 	config *Config
+
+	// sandbox is set when tests run inside a -docker container
+	sandbox *DockerSandbox
 }
 
 func NewTestExecutor(config *Config) *TestExecutor {
 	return &TestExecutor{config: config}
 }
 
-func (e *TestExecutor) Execute(ctx context.Context, executablePath string, testCase TestCase, testNumber int) TestResult {
-	startTime := time.Now()
+// testFiles returns the input and expected output files of a test: the ones
+// it was read from, or its files in the test cache
+func (e *TestExecutor) testFiles(testCase TestCase) (string, string) {
+	if testCase.InputPath != "" {
+		return testCase.InputPath, testCase.ExpectedPath
+	}
+	dir := filepath.Join(e.config.CacheDir, e.config.ProblemID)
+	return filepath.Join(dir, fmt.Sprintf("%d.in", testCase.Number)), filepath.Join(dir, fmt.Sprintf("%d.out", testCase.Number))
+}
+
+func (e *TestExecutor) Execute(ctx context.Context, executablePath string, testCase TestCase) TestResult {
+	result := TestResult{TestNumber: testCase.Number}
+	result.InputFile, result.ExpectedFile = e.testFiles(testCase)
 
-	result := TestResult{
-		TestNumber:     testNumber,
-		ExpectedOutput: testCase.Expected,
-		InputFile:      filepath.Join(e.config.CacheDir, e.config.ProblemID, fmt.Sprintf("%d.in", testCase.Number)),
-		ExpectedFile:   filepath.Join(e.config.CacheDir, e.config.ProblemID, fmt.Sprintf("%d.out", testCase.Number)),
+	testCase, err := testCase.Load()
+	if err != nil {
+		result.Error = err.Error()
+		result.Verdict = VerdictRuntimeError
+		return result
 	}
+	result.ExpectedOutput = testCase.Expected
 
 	// Execute the program
-	actualOutput, exitCode, err := e.runGoProgram(ctx, executablePath, testCase.Input)
+	startTime := time.Now()
+	output, err := e.runGoProgram(ctx, executablePath, testCase.Input)
 	result.Duration = time.Since(startTime)
-	result.ActualOutput = actualOutput
-	result.ExitCode = exitCode
+	result.ActualOutput = output.Stdout
+	result.Stderr = output.Stderr
+	if e.config.GCStats {
+		result.GC, result.Stderr = parseGCTrace(output.Stderr)
+	}
+	result.ExitCode = output.ExitCode
+	result.MemoryUsage = output.MemoryUsage
+	result.Timeline = output.Timeline
 
 	if err != nil {
 		result.Error = err.Error()
+		result.Verdict = VerdictRuntimeError
+		if ctx.Err() == context.DeadlineExceeded {
+			result.Verdict = VerdictTimeLimit
+			return result
+		}
+		if result.StackOverflow = parseStackOverflow(result.Stderr); result.StackOverflow != nil {
+			result.Error = result.StackOverflow.String()
+			return result
+		}
+		if report := parseSanitizerReport(result.Stderr); e.config.Checks != "" && report != "" {
+			result.Error = report
+			result.Verdict = VerdictSanitizer
+			return result
+		}
+
+		// CSES judges a non-zero exit as RE even when the output is right
+		if output.ExitCode > 0 && e.compareOutputs(output.Stdout, testCase.Expected) {
+			if e.config.AllowNonZeroExit {
+				result.Error = ""
+				result.Passed = true
+				result.Verdict = VerdictAccepted
+				return result
+			}
+			result.Error = fmt.Sprintf("output is correct, but %s (CSES judges this as RE; -allow-nonzero-exit ignores it)", err)
+		}
 		return result
 	}
 
 	// Compare outputs
-	if e.compareOutputs(actualOutput, testCase.Expected) {
+	if e.compareOutputs(output.Stdout, testCase.Expected) {
 		result.Passed = true
+		result.Verdict = VerdictAccepted
 	} else {
 		result.Error = "Output mismatch"
+		result.Verdict = VerdictWrongAnswer
 	}
 
 	return result
 }
 
-func (e *TestExecutor) runGoProgram(ctx context.Context, executablePath, input string) (string, int, error) {
-	cmd := exec.CommandContext(ctx, executablePath)
+// solutionCommand builds the command running the compiled solution, either on
+// the host or inside the -docker sandbox. limit bounds the run in the container.
+func solutionCommand(ctx context.Context, config *Config, sandbox *DockerSandbox, executablePath string, limit time.Duration) *exec.Cmd {
+	if sandbox != nil {
+		return sandbox.Command(ctx, executablePath, config.GetSolutionArgs(), containerEnv(solutionEnv(config)), limit)
+	}
+
+	cmd := exec.CommandContext(ctx, executablePath, config.GetSolutionArgs()...)
+	cmd.Env = solutionEnv(config)
+	return cmd
+}
+
+// signalHints explain the signals solutions usually die from
+var signalHints = map[string]string{
+	"SIGKILL": "likely OOM",
+	"SIGSEGV": "segmentation fault",
+	"SIGBUS":  "bus error",
+	"SIGABRT": "aborted",
+	"SIGFPE":  "arithmetic exception",
+	"SIGXCPU": "CPU time limit",
+}
+
+// describeSignal renders e.g. "killed by SIGKILL — likely OOM"
+func describeSignal(name string) string {
+	if hint, ok := signalHints[name]; ok {
+		return fmt.Sprintf("killed by %s — %s", name, hint)
+	}
+	return "killed by " + name
+}
+
+func (e *TestExecutor) runGoProgram(ctx context.Context, executablePath, input string) (processOutput, error) {
+	cmd := solutionCommand(ctx, e.config, e.sandbox, executablePath, e.config.GetTimeout())
 	cmd.Stdin = strings.NewReader(input)
 
+	// Each run gets its own scratch directory, so files a solution creates
+	// never collide with parallel tests or land in the source tree. In the
+	// -docker sandbox the mounted directory is read-only instead.
+	var workDir string
+	if e.sandbox == nil {
+		dir, err := prepareWorkDir(e.config, cmd, input)
+		if err != nil {
+			return processOutput{}, err
+		}
+		defer os.RemoveAll(dir)
+		workDir = dir
+	}
+
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
+	if e.config.Stream {
+		// Show the output as it is written while still capturing it for the comparison
+		cmd.Stdout = io.MultiWriter(&stdout, os.Stdout)
+		cmd.Stderr = io.MultiWriter(&stderr, os.Stderr)
+	}
 
-	err := cmd.Run()
-	exitCode := 0
+	// Resource usage of `docker exec` would be the docker client's, not the
+	// solution's, so in the sandbox it comes from the container's cgroup
+	containerPeak := func() int64 { return 0 }
+	if e.sandbox != nil {
+		containerPeak = e.sandbox.watchMemory()
+	}
+
+	var err error
+	if e.config.IsolateTiming {
+		err = startPinned(cmd)
+	} else {
+		err = cmd.Start()
+	}
+	var timeline []ResourceSample
+	if err == nil {
+		var monitor *processMonitor
+		if e.sandbox == nil {
+			monitor = startMonitor(cmd.Process.Pid, e.config.GetMonitorInterval())
+		}
+		err = cmd.Wait()
+		timeline = monitor.Stop()
+	}
+	output := processOutput{Stdout: stdout.String(), Stderr: stderr.String(), Timeline: timeline}
+	missingOutput := false
+	if workDir != "" && e.config.IOOutput != "" {
+		written, readErr := os.ReadFile(filepath.Join(workDir, e.config.IOOutput))
+		output.Stdout, missingOutput = string(written), readErr != nil
+	}
+	if e.sandbox == nil {
+		output.MemoryUsage = peakMemoryUsage(cmd.ProcessState)
+	} else {
+		output.MemoryUsage = containerPeak()
+	}
 
 	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok {
-			exitCode = exitError.ExitCode()
+			output.ExitCode = exitError.ExitCode()
 		}
 
 		if ctx.Err() == context.DeadlineExceeded {
-			return "", exitCode, fmt.Errorf("timeout exceeded (%s)", e.config.GetTimeout())
+			return output, fmt.Errorf("timeout exceeded (%s)", e.config.GetTimeout())
+		}
+
+		signal := terminationSignal(cmd.ProcessState)
+		if signal == "" && e.sandbox != nil {
+			signal = signalFromExitCode(output.ExitCode)
+		}
+		if signal != "" {
+			if stderr.Len() > 0 {
+				return output, fmt.Errorf("runtime error (%s): %s", describeSignal(signal), stderr.String())
+			}
+			return output, fmt.Errorf("runtime error (%s)", describeSignal(signal))
 		}
 
 		if stderr.Len() > 0 {
-			return "", exitCode, fmt.Errorf("runtime error (exit code %d): %s", exitCode, stderr.String())
+			return output, fmt.Errorf("runtime error (exit code %d): %s", output.ExitCode, stderr.String())
 		}
 
-		return "", exitCode, fmt.Errorf("execution failed (exit code %d): %w", exitCode, err)
+		return output, fmt.Errorf("execution failed (exit code %d): %w", output.ExitCode, err)
 	}
 
-	return stdout.String(), exitCode, nil
+	if missingOutput {
+		return output, fmt.Errorf("the solution did not write %s", e.config.IOOutput)
+	}
+
+	return output, nil
+}
+
+// prepareWorkDir gives cmd an empty temporary working directory. With
+// -io-input the test input is placed there under that name, for solutions
+// that read and write named files instead of stdin and stdout. The caller
+// removes the directory.
+func prepareWorkDir(config *Config, cmd *exec.Cmd, input string) (string, error) {
+	dir, err := os.MkdirTemp("", "cses-run-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create working directory: %w", err)
+	}
+	if config.IOInput != "" {
+		if err := os.WriteFile(filepath.Join(dir, config.IOInput), []byte(input), 0644); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("failed to write %s: %w", config.IOInput, err)
+		}
+		cmd.Stdin = strings.NewReader("")
+	}
+	cmd.Dir = dir
+	return dir, nil
+}
+
+// Attach runs the solution on a single test with stdout/stderr connected to
+// the terminal, without capturing or comparing output. Meant for printf debugging.
+func (e *TestExecutor) Attach(ctx context.Context, executablePath string, testCase TestCase) error {
+	inputFile, _ := e.testFiles(testCase)
+	cyan.Printf("📎 Attaching to test %d (input: %s)\n", testCase.Number, inputFile)
+	fmt.Println(strings.Repeat("-", 60))
+
+	ctx, cancel := context.WithTimeout(ctx, e.config.GetTimeout())
+	defer cancel()
+
+	cmd := solutionCommand(ctx, e.config, e.sandbox, executablePath, e.config.GetTimeout())
+	cmd.Stdin = strings.NewReader(testCase.Input)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if e.sandbox == nil {
+		dir, err := prepareWorkDir(e.config, cmd, testCase.Input)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		if e.config.IOOutput != "" {
+			defer func() {
+				if written, err := os.ReadFile(filepath.Join(dir, e.config.IOOutput)); err == nil {
+					cyan.Printf("📄 %s:\n", e.config.IOOutput)
+					fmt.Print(string(written))
+				}
+			}()
+		}
+	}
+
+	startTime := time.Now()
+	err := cmd.Run()
+	duration := time.Since(startTime)
+
+	fmt.Println(strings.Repeat("-", 60))
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timeout exceeded (%s)", e.config.GetTimeout())
+	}
+	if err != nil {
+		return fmt.Errorf("solution exited after %.2fms: %w", duration.Seconds()*1000, err)
+	}
+
+	green.Printf("✅ Solution exited normally after %.2fms\n", duration.Seconds()*1000)
+	return nil
 }
 
 func (e *TestExecutor) compareOutputs(actual, expected string) bool {
@@ -97,9 +334,82 @@ func (e *TestExecutor) compareOutputs(actual, expected string) bool {
 	actual = e.normalizeOutput(actual)
 	expected = e.normalizeOutput(expected)
 
+	if e.config.Numeric {
+		return numericMatch(actual, expected, e.config.Epsilon)
+	}
 	return actual == expected
 }
 
+// numericMatch compares normalized outputs line by line and token by token.
+// Integer tokens must be equal exactly; when either token has a fraction or an
+// exponent they match if they differ by at most epsilon, absolutely or
+// relative to their magnitude. So 1e+09 matches 1000000000 and 0.3333333
+// matches 0.333333333, but 1000000999 does not match 1000000000. NaN and
+// infinities never match.
+func numericMatch(actual, expected string, epsilon float64) bool {
+	actualLines := strings.Split(actual, "\n")
+	expectedLines := strings.Split(expected, "\n")
+	if len(actualLines) != len(expectedLines) {
+		return false
+	}
+
+	for i := range expectedLines {
+		actualTokens := strings.Fields(actualLines[i])
+		expectedTokens := strings.Fields(expectedLines[i])
+		if len(actualTokens) != len(expectedTokens) {
+			return false
+		}
+
+		for j, want := range expectedTokens {
+			got := actualTokens[j]
+			if got == want {
+				continue
+			}
+
+			if isIntegerToken(got) && isIntegerToken(want) {
+				a, _ := new(big.Int).SetString(got, 10)
+				b, _ := new(big.Int).SetString(want, 10)
+				if a.Cmp(b) != 0 {
+					return false
+				}
+				continue
+			}
+
+			a, errA := strconv.ParseFloat(got, 64)
+			b, errB := strconv.ParseFloat(want, 64)
+			if errA != nil || errB != nil || !isFinite(a) || !isFinite(b) {
+				return false
+			}
+			scale := math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+			if math.Abs(a-b) > epsilon*scale {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// isIntegerToken reports whether a token is a decimal integer, with an
+// optional sign, and no fraction or exponent
+func isIntegerToken(token string) bool {
+	digits := strings.TrimLeft(token, "+-")
+	if len(token)-len(digits) > 1 || digits == "" {
+		return false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isFinite reports whether f is neither NaN nor an infinity
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
 func (e *TestExecutor) normalizeOutput(output string) string {
 	// Remove trailing whitespace from each line and normalize line endings
 	lines := strings.Split(output, "\n")
@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestNumericMatch(t *testing.T) {
+	tests := []struct {
+		name             string
+		actual, expected string
+		want             bool
+	}{
+		{"identical", "1 2 3", "1 2 3", true},
+		{"integers exact", "1000000000", "1000000000", true},
+		{"integer off by a little", "1000000999", "1000000000", false},
+		{"integer off by one", "8", "7", false},
+		{"integer with sign and zeros", "+007", "7", true},
+		{"big integers", "123456789012345678901234567890", "123456789012345678901234567890", true},
+		{"big integers differ", "123456789012345678901234567891", "123456789012345678901234567890", false},
+		{"exponent matches integer", "1e+09", "1000000000", true},
+		{"fraction within epsilon", "0.3333333", "0.333333333", true},
+		{"fraction outside epsilon", "0.334", "0.333", false},
+		{"relative epsilon on large values", "123456789.5", "123456789.6", true},
+		{"NaN", "NaN", "1.5", false},
+		{"NaN both", "NaN", "NaN", true},
+		{"NaN expected", "1.5", "nan", false},
+		{"Inf", "Inf", "1e308", false},
+		{"negative Inf", "-inf", "-1.5", false},
+		{"words", "YES", "NO", false},
+		{"token count", "1 2", "1 2 3", false},
+		{"line count", "1\n2", "1 2", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := numericMatch(tt.actual, tt.expected, 1e-6); got != tt.want {
+				t.Errorf("numericMatch(%q, %q) = %v, want %v", tt.actual, tt.expected, got, tt.want)
+			}
+		})
+	}
+}
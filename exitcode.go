@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+)
+
+// Process exit codes, so scripts and hooks can branch on the failure class
+const (
+	ExitOK           = 0 // all tests passed
+	ExitTestsFailed  = 1 // at least one test failed (or a generic error)
+	ExitCompileError = 2 // the solution did not build
+	ExitFetchError   = 3 // authentication or test case download failed
+	ExitUsageError   = 4 // bad flags, arguments or input files
+)
+
+// ErrTestsFailed is returned by a run that completed but had failing tests
+var ErrTestsFailed = errors.New("some tests failed")
+
+// ExitError attaches an exit code to an error
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// withExitCode wraps err so that main exits with code when it surfaces
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: code, Err: err}
+}
+
+// exitCodeFor maps an error returned by a command to the process exit code
+func exitCodeFor(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	return ExitTestsFailed
+}
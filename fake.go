@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+
+	"github.com/anurag5sh/cses-go-runner/internal/fakecses"
+)
+
+// sampleProblem is served by the fake CSES even with an empty cache
+var sampleProblem = []fakecses.TestCase{
+	{Input: "3\n", Output: "3 10 5 16 8 4 2 1\n"},
+	{Input: "1\n", Output: "1\n"},
+}
+
+// serveFakeCSES runs an offline stand-in for cses.fi on addr. It accepts the
+// CSES_USERNAME/CSES_PASSWORD credentials (demo/demo when unset) and serves
+// every problem already in the cache plus a sample of problem 1068.
+// Submissions are accepted, or get the verdict in CSES_FAKE_VERDICT, and
+// CSES_FAKE_MAINTENANCE=1 answers everything with 503 as during maintenance.
+func serveFakeCSES(config *Config, addr string) error {
+	username := os.Getenv("CSES_USERNAME")
+	password := os.Getenv("CSES_PASSWORD")
+	if username == "" || password == "" {
+		username, password = "demo", "demo"
+	}
+
+	fake := fakecses.New(username, password)
+	if verdict := os.Getenv("CSES_FAKE_VERDICT"); verdict != "" {
+		fake.Verdict = verdict
+	}
+	fake.Maintenance = os.Getenv("CSES_FAKE_MAINTENANCE") == "1"
+	fake.AddProblem("1068", sampleProblem)
+	if err := fake.LoadProblems(config.CacheDir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		yellow.Printf("⚠️  Failed to load cached problems: %v\n", err)
+	}
+
+	green.Printf("🧪 Fake CSES running at http://%s (user %q)\n", addr, username)
+	fmt.Printf("   Problems: %v\n", fake.ProblemIDs())
+	fmt.Printf("   Use it with: %s -base-url=http://%s -cache-dir=<scratch dir> ...\n", AppName, addr)
+
+	return http.ListenAndServe(addr, fake)
+}
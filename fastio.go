@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// unbufferedCalls maps the fmt functions that read stdin or write stdout
+// unbuffered to their buffered replacement and the harness variable it takes
+var unbufferedCalls = map[string]struct{ replacement, stream string }{
+	"Scan":    {"Fscan", "reader"},
+	"Scanf":   {"Fscanf", "reader"},
+	"Scanln":  {"Fscanln", "reader"},
+	"Print":   {"Fprint", "writer"},
+	"Printf":  {"Fprintf", "writer"},
+	"Println": {"Fprintln", "writer"},
+}
+
+// ioFinding is one piece of unbuffered I/O in the solution
+type ioFinding struct {
+	Line    int
+	Call    string
+	Fixable bool
+
+	// Where the call is rewritten: the function name and the opening paren
+	nameStart, nameEnd, lparen int
+	hasArgs                    bool
+	replacement, stream        string
+}
+
+// fastIOAnalysis is the result of scanning a solution for unbuffered I/O
+type fastIOAnalysis struct {
+	file     *ast.File
+	fset     *token.FileSet
+	src      []byte
+	fmtName  string
+	findings []ioFinding
+}
+
+// analyzeFastIO finds fmt.Scan*/Print* calls and direct os.Stdin/os.Stdout use
+func analyzeFastIO(path string) (*fastIOAnalysis, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read solution: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, parseErrorDiagnostics(err)
+	}
+
+	analysis := &fastIOAnalysis{file: file, fset: fset, src: src, fmtName: importName(file, "fmt")}
+	osName := importName(file, "os")
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			sel, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			pkg, ok := sel.X.(*ast.Ident)
+			if !ok || pkg.Name != analysis.fmtName || analysis.fmtName == "" {
+				return true
+			}
+			call, ok := unbufferedCalls[sel.Sel.Name]
+			if !ok {
+				return true
+			}
+			analysis.findings = append(analysis.findings, ioFinding{
+				Line:        fset.Position(node.Pos()).Line,
+				Call:        pkg.Name + "." + sel.Sel.Name,
+				Fixable:     true,
+				nameStart:   fset.Position(sel.Sel.Pos()).Offset,
+				nameEnd:     fset.Position(sel.Sel.End()).Offset,
+				lparen:      fset.Position(node.Lparen).Offset,
+				hasArgs:     len(node.Args) > 0,
+				replacement: call.replacement,
+				stream:      call.stream,
+			})
+		case *ast.SelectorExpr:
+			// os.Stdout.Write and friends bypass any buffering; only warn about them
+			inner, ok := node.X.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			pkg, ok := inner.X.(*ast.Ident)
+			if ok && pkg.Name == osName && osName != "" && (inner.Sel.Name == "Stdout" || inner.Sel.Name == "Stdin") {
+				analysis.findings = append(analysis.findings, ioFinding{
+					Line: fset.Position(node.Pos()).Line,
+					Call: pkg.Name + "." + inner.Sel.Name + "." + node.Sel.Name,
+				})
+			}
+		}
+		return true
+	})
+
+	sort.Slice(analysis.findings, func(i, j int) bool {
+		return analysis.findings[i].Line < analysis.findings[j].Line
+	})
+	return analysis, nil
+}
+
+// importName returns the name a file refers to an imported package by, or ""
+func importName(file *ast.File, path string) string {
+	for _, spec := range file.Imports {
+		if importPath, _ := strconv.Unquote(spec.Path.Value); importPath == path {
+			if spec.Name != nil {
+				return spec.Name.Name
+			}
+			return path
+		}
+	}
+	return ""
+}
+
+// handleOptimizeIO reports unbuffered I/O in the solution; `optimize-io fix`
+// rewrites it to use a bufio reader/writer harness
+func handleOptimizeIO(config *Config, args []string) error {
+	if err := validateSolutionFile(config); err != nil {
+		return withExitCode(ExitUsageError, err)
+	}
+	fix := len(args) == 1 && args[0] == "fix"
+	if len(args) > 1 || (len(args) == 1 && !fix) {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: optimize-io [fix] -file=solution.go"))
+	}
+
+	analysis, err := analyzeFastIO(config.FilePath)
+	if err != nil {
+		return withExitCode(ExitCompileError, err)
+	}
+
+	if len(analysis.findings) == 0 {
+		green.Println("✅ No unbuffered I/O found")
+		return nil
+	}
+
+	yellow.Printf("⚠️  %d unbuffered I/O call(s) in %s — the most common cause of TLE on CSES:\n", len(analysis.findings), config.FilePath)
+	fixable := 0
+	for _, finding := range analysis.findings {
+		note := ""
+		if finding.Fixable {
+			fixable++
+			note = fmt.Sprintf(" → %s.%s(%s, ...)", analysis.fmtName, finding.replacement, finding.stream)
+		}
+		fmt.Printf("   line %-5d %s%s\n", finding.Line, finding.Call, note)
+	}
+
+	if !fix || fixable == 0 {
+		if fixable > 0 {
+			cyan.Printf("\n💡 Run `%s optimize-io fix -file=%s` to add a bufio harness\n", AppName, config.FilePath)
+		}
+		return nil
+	}
+
+	rewritten, err := analysis.rewrite()
+	if err != nil {
+		return err
+	}
+
+	backup := config.FilePath + ".orig"
+	if err := os.WriteFile(backup, analysis.src, 0644); err != nil {
+		return fmt.Errorf("failed to back up solution: %w", err)
+	}
+	if err := os.WriteFile(config.FilePath, rewritten, 0644); err != nil {
+		return fmt.Errorf("failed to write solution: %w", err)
+	}
+
+	green.Printf("\n✅ Rewrote %d call(s) to use buffered I/O (original saved as %s)\n", fixable, backup)
+	if fixable < len(analysis.findings) {
+		yellow.Println("⚠️  Direct os.Stdin/os.Stdout use was left as is; switch it to reader/writer by hand")
+	}
+	return nil
+}
+
+// rewrite returns the source with fmt calls switched to reader/writer, the
+// harness variables declared and writer flushed when main returns
+func (a *fastIOAnalysis) rewrite() ([]byte, error) {
+	for _, name := range []string{"reader", "writer"} {
+		if a.file.Scope.Lookup(name) != nil {
+			return nil, fmt.Errorf("the solution already declares %q; add the harness by hand", name)
+		}
+	}
+
+	var main *ast.FuncDecl
+	for _, decl := range a.file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" && fn.Body != nil {
+			main = fn
+		}
+	}
+	if main == nil {
+		return nil, fmt.Errorf("no func main found")
+	}
+
+	// Edits are applied back to front so earlier offsets stay valid
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	for _, finding := range a.findings {
+		if !finding.Fixable {
+			continue
+		}
+		stream := finding.stream
+		if finding.hasArgs {
+			stream += ", "
+		}
+		edits = append(edits,
+			edit{finding.nameStart, finding.nameEnd, finding.replacement},
+			edit{finding.lparen + 1, finding.lparen + 1, stream})
+	}
+
+	edits = append(edits, edit{a.offset(main.Body.Lbrace) + 1, a.offset(main.Body.Lbrace) + 1, "\n\tdefer writer.Flush()\n"})
+
+	harness := "\n\nvar (\n\treader = bufio.NewReaderSize(os.Stdin, 1<<20)\n\twriter = bufio.NewWriterSize(os.Stdout, 1<<20)\n)\n"
+	lastImport := a.file.Name.End()
+	for _, decl := range a.file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			lastImport = gen.End()
+		}
+	}
+	edits = append(edits, edit{a.offset(lastImport), a.offset(lastImport), harness})
+
+	var missing []string
+	for _, path := range []string{"bufio", "os"} {
+		if importName(a.file, path) == "" {
+			missing = append(missing, strconv.Quote(path))
+		}
+	}
+	if len(missing) > 0 {
+		var imports *ast.GenDecl
+		for _, decl := range a.file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+				imports = gen
+				break
+			}
+		}
+
+		switch {
+		case imports == nil:
+			edits = append(edits, edit{a.offset(a.file.Name.End()), a.offset(a.file.Name.End()),
+				"\n\nimport (\n\t" + strings.Join(missing, "\n\t") + "\n)"})
+		case imports.Lparen.IsValid():
+			edits = append(edits, edit{a.offset(imports.Lparen) + 1, a.offset(imports.Lparen) + 1,
+				"\n\t" + strings.Join(missing, "\n\t")})
+		default:
+			// import "fmt" becomes a group
+			spec := string(a.src[a.offset(imports.Specs[0].Pos()):a.offset(imports.Specs[0].End())])
+			edits = append(edits, edit{a.offset(imports.Pos()), a.offset(imports.End()),
+				"import (\n\t" + spec + "\n\t" + strings.Join(missing, "\n\t") + "\n)"})
+		}
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	src := append([]byte(nil), a.src...)
+	for _, e := range edits {
+		src = append(src[:e.start], append([]byte(e.text), src[e.end:]...)...)
+	}
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format rewritten solution: %w", err)
+	}
+	return formatted, nil
+}
+
+func (a *fastIOAnalysis) offset(pos token.Pos) int {
+	return a.fset.Position(pos).Offset
+}
+
+// displayIOHint suggests optimize-io when a run hit TLE and the solution uses unbuffered I/O
+func displayIOHint(config *Config, results []TestResult) {
+	for _, result := range results {
+		if result.Verdict != VerdictTimeLimit {
+			continue
+		}
+
+		analysis, err := analyzeFastIO(config.FilePath)
+		if err != nil || len(analysis.findings) == 0 {
+			return
+		}
+		yellow.Printf("💡 The solution uses unbuffered I/O (%d call(s)); `%s optimize-io -file=%s` shows where\n",
+			len(analysis.findings), AppName, config.FilePath)
+		return
+	}
+}
@@ -3,75 +3,220 @@ package main
 import (
 	"archive/zip"
 	"bytes"
+	"cmp"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type TestCase struct {
 	Input    string
 	Expected string
 	Number   int
+
+	// Cached test cases are read from disk only when they run, so a large
+	// problem never has all of its data in memory at once
+	InputPath    string
+	ExpectedPath string
+	Size         int64
+	lazy         bool
+}
+
+// Load returns the test case with its input and expected output read into memory
+func (tc TestCase) Load() (TestCase, error) {
+	if !tc.lazy {
+		return tc, nil
+	}
+
+	input, err := os.ReadFile(tc.InputPath)
+	if err != nil {
+		return tc, fmt.Errorf("failed to read test input: %w", err)
+	}
+
+	expected, err := os.ReadFile(tc.ExpectedPath)
+	if err != nil {
+		return tc, fmt.Errorf("failed to read expected output: %w", err)
+	}
+
+	tc.Input, tc.Expected, tc.lazy = string(input), string(expected), false
+	return tc, nil
+}
+
+// DataSize is the number of bytes of input and expected output of the test
+func (tc TestCase) DataSize() int64 {
+	if tc.lazy {
+		return tc.Size
+	}
+	return int64(len(tc.Input) + len(tc.Expected))
 }
 
 type TestCaseFetcher struct {
 	config *Config
 	auth   *CSESAuth
+
+	// Optional observer of archive downloads (e.g. the prefetch progress bars)
+	onDownload func(read, total int64)
 }
 
-func NewTestCaseFetcher(config *Config) *TestCaseFetcher {
+// NewTestCaseFetcher creates a fetcher that downloads through the shared auth session
+func NewTestCaseFetcher(config *Config, auth *CSESAuth) *TestCaseFetcher {
 	return &TestCaseFetcher{
 		config: config,
-		auth:   NewCSESAuth(config),
+		auth:   auth,
 	}
 }
 
+func (f *TestCaseFetcher) Name() string {
+	return "CSES"
+}
+
 func (f *TestCaseFetcher) FetchTestCases(problemID string) ([]TestCase, error) {
 	cacheDir := filepath.Join(f.config.CacheDir, problemID)
 
 	// Check if we have cached test cases
-	if testCases, err := f.loadCachedTestCases(cacheDir); err == nil && len(testCases) > 0 {
+	cached, err := f.loadCachedTestCases(cacheDir)
+	hasCache := err == nil && len(cached) > 0
+	var known *ArchiveMeta
+	if hasCache {
+		known = loadArchiveMeta(cacheDir)
+	}
+	retry := known != nil && known.RetryRefresh
+	if hasCache && !f.config.RefreshTests && !retry {
 		if f.config.Verbose {
 			green.Printf("📋 Using cached test cases from %s\n", cacheDir)
 		}
-		return testCases, nil
+		touchTestSet(cacheDir)
+		return cached, nil
+	}
+
+	if isImportedProblem(problemID) {
+		return nil, fmt.Errorf("no samples of %s are cached; import them with `%s tests import-samples -url=<problem-url>`", problemID, AppName)
 	}
 
-	// Fetch from CSES
+	// Fetch from CSES; a refresh only downloads the archive again if it changed
 	if f.config.Verbose {
 		yellow.Printf("🔍 Fetching test cases from CSES for problem %s...\n", problemID)
 	}
 
-	testCases, err := f.fetchFromCSES(problemID)
+	testCases, meta, err := f.fetchFromCSES(problemID, known)
+	if hasCache && errors.Is(err, ErrUnavailable) {
+		// Run on the cached tests and check for changes again next time
+		yellow.Printf("⚠️  %v; using the cached tests of problem %s and checking for changes on the next run\n", unavailableCause(err), problemID)
+		if known == nil {
+			known = &ArchiveMeta{}
+		}
+		known.RetryRefresh = true
+		saveArchiveMeta(cacheDir, known)
+		touchTestSet(cacheDir)
+		return cached, nil
+	}
+	if hasCache && (errors.Is(err, ErrNotModified) || (err == nil && known != nil && meta.SHA256 == known.SHA256)) {
+		green.Printf("📋 Tests of problem %s are unchanged, using the cache\n", problemID)
+		known.FetchedAt = time.Now()
+		known.RetryRefresh = false
+		saveArchiveMeta(cacheDir, known)
+		touchTestSet(cacheDir)
+		return cached, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from CSES: %w", err)
 	}
 
+	if hasCache {
+		yellow.Printf("🔄 Tests of problem %s changed, replacing the cached copy\n", problemID)
+		// Cached files may be hard links shared with other tests, so they are
+		// removed rather than overwritten
+		for _, testCase := range cached {
+			os.Remove(testCase.InputPath)
+			os.Remove(testCase.ExpectedPath)
+		}
+	}
+
 	// Cache the test cases
 	if err := f.cacheTestCases(cacheDir, testCases); err != nil {
 		yellow.Printf("⚠️  Failed to cache test cases: %v\n", err)
+		return testCases, nil
+	}
+	saveArchiveMeta(cacheDir, meta)
+	touchTestSet(cacheDir)
+	enforceCacheQuota(f.config, problemID)
+
+	// Hand out the lazily loaded cached copies so the downloaded data can be freed
+	if cached, err := f.loadCachedTestCases(cacheDir); err == nil && len(cached) == len(testCases) {
+		return cached, nil
 	}
 
 	return testCases, nil
 }
 
-func (f *TestCaseFetcher) fetchFromCSES(problemID string) ([]TestCase, error) {
+// fetchFromCSES downloads and extracts the tests of a problem. With the
+// metadata of the cached archive, ErrNotModified means the cache is current.
+func (f *TestCaseFetcher) fetchFromCSES(problemID string, known *ArchiveMeta) ([]TestCase, *ArchiveMeta, error) {
 	// Ensure we're authenticated
 	if err := f.auth.EnsureAuthenticated(); err != nil {
-		return nil, fmt.Errorf("authentication required: %w", err)
+		return nil, nil, fmt.Errorf("authentication required: %w", err)
 	}
 
 	// Get the test cases zip file
-	zipData, err := f.auth.DownloadTestCases(problemID)
+	zipData, meta, err := f.auth.DownloadTestCases(problemID, known, f.onDownload)
+	if errors.Is(err, ErrSessionExpired) {
+		// The session died mid-run: log in again with the stored credentials and retry once
+		yellow.Println("🔐 Session expired, re-authenticating...")
+		if err := f.auth.Login(); err != nil {
+			return nil, nil, fmt.Errorf("re-authentication failed: %w", err)
+		}
+		zipData, meta, err = f.auth.DownloadTestCases(problemID, known, f.onDownload)
+	}
+	if errors.Is(err, ErrNotModified) {
+		return nil, nil, err
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to download test cases: %w", err)
+		return nil, nil, fmt.Errorf("failed to download test cases: %w", err)
 	}
 
 	// Extract and parse the zip file
-	return f.extractTestCasesFromZip(zipData)
+	testCases, err := f.extractTestCasesFromZip(zipData)
+	return testCases, meta, err
+}
+
+// Limits on test archives, which are extracted into memory. CSES archives
+// stay far below them; an archive beyond them is broken or a zip bomb.
+const (
+	maxArchiveEntries   = 10000
+	maxArchiveEntrySize = 256 << 20
+	maxArchiveTotalSize = 1 << 30
+)
+
+// ArchiveError identifies the entry a test archive was rejected for
+type ArchiveError struct {
+	Entry  string
+	Reason string
+}
+
+func (e *ArchiveError) Error() string {
+	return fmt.Sprintf("invalid test archive: entry %q %s", e.Entry, e.Reason)
+}
+
+// checkArchiveEntryName rejects names that would point outside the archive
+// if they were ever written to disk: absolute paths, drive letters and ..
+func checkArchiveEntryName(name string) error {
+	slashed := strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(slashed, "/") || filepath.VolumeName(name) != "" || (len(slashed) > 1 && slashed[1] == ':') {
+		return &ArchiveError{Entry: name, Reason: "has an absolute path"}
+	}
+	for _, part := range strings.Split(slashed, "/") {
+		if part == ".." {
+			return &ArchiveError{Entry: name, Reason: "leaves the archive with .."}
+		}
+	}
+	return nil
 }
 
 func (f *TestCaseFetcher) extractTestCasesFromZip(zipData []byte) ([]TestCase, error) {
@@ -80,60 +225,81 @@ func (f *TestCaseFetcher) extractTestCasesFromZip(zipData []byte) ([]TestCase, e
 	if err != nil {
 		return nil, fmt.Errorf("failed to read zip file: %w", err)
 	}
+	if len(reader.File) > maxArchiveEntries {
+		return nil, fmt.Errorf("invalid test archive: %d entries, more than the %d allowed", len(reader.File), maxArchiveEntries)
+	}
 
 	var testCases []TestCase
 	inputs := make(map[int]string)
 	outputs := make(map[int]string)
+	sources := make(map[string]string) // "in 3" -> the entry test 3's input came from
+	var total int64
 
 	// Process each file in the zip
 	for _, file := range reader.File {
+		if err := checkArchiveEntryName(file.Name); err != nil {
+			return nil, err
+		}
 		if file.FileInfo().IsDir() {
 			continue
 		}
 
-		// Open the file
-		rc, err := file.Open()
-		if err != nil {
+		// Parse filename to get test case number
+		filename := file.Name
+		isInput, testNum := f.parseTestFile(filename)
+		if testNum <= 0 {
 			continue
 		}
+		kind, target := "out", outputs
+		if isInput {
+			kind, target = "in", inputs
+		}
+		key := fmt.Sprintf("%s %d", kind, testNum)
+		if earlier, ok := sources[key]; ok {
+			return nil, &ArchiveError{Entry: filename, Reason: fmt.Sprintf("is test %d again (already read from %q)", testNum, earlier)}
+		}
+		sources[key] = filename
 
-		content, err := io.ReadAll(rc)
+		// The sizes in the header may lie, so the limits apply to what is read
+		if file.UncompressedSize64 > maxArchiveEntrySize {
+			return nil, &ArchiveError{Entry: filename, Reason: fmt.Sprintf("is larger than %s", formatBytes(maxArchiveEntrySize))}
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, &ArchiveError{Entry: filename, Reason: fmt.Sprintf("cannot be opened: %v", err)}
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxArchiveEntrySize+1))
 		rc.Close()
 		if err != nil {
-			continue
+			return nil, &ArchiveError{Entry: filename, Reason: fmt.Sprintf("cannot be read: %v", err)}
 		}
-
-		// Parse filename to get test case number
-		filename := file.Name
-		if strings.HasSuffix(filename, ".in") {
-			// Input file
-			testNum := f.parseTestNumber(filename, ".in")
-			if testNum > 0 {
-				inputs[testNum] = string(content)
-			}
-		} else if strings.HasSuffix(filename, ".out") {
-			// Output file
-			testNum := f.parseTestNumber(filename, ".out")
-			if testNum > 0 {
-				outputs[testNum] = string(content)
-			}
+		if len(content) > maxArchiveEntrySize {
+			return nil, &ArchiveError{Entry: filename, Reason: fmt.Sprintf("is larger than %s", formatBytes(maxArchiveEntrySize))}
 		}
+		if total += int64(len(content)); total > maxArchiveTotalSize {
+			return nil, &ArchiveError{Entry: filename, Reason: fmt.Sprintf("takes the archive past %s", formatBytes(maxArchiveTotalSize))}
+		}
+
+		target[testNum] = string(content)
 	}
 
-	// Create test cases from matched input/output pairs
-	for testNum := range inputs {
-		if output, exists := outputs[testNum]; exists {
-			testCases = append(testCases, TestCase{
-				Input:    inputs[testNum],
-				Expected: output,
-				Number:   testNum,
-			})
-		}
+	if err := checkArchivePairs(inputs, outputs, sources); err != nil {
+		return nil, err
+	}
+
+	// Create test cases from the input/output pairs
+	for testNum, input := range inputs {
+		testCases = append(testCases, TestCase{
+			Input:    input,
+			Expected: outputs[testNum],
+			Number:   testNum,
+		})
 	}
 
 	if len(testCases) == 0 {
 		return nil, fmt.Errorf("no valid test cases found in zip file")
 	}
+	sortTestCases(testCases)
 
 	if f.config.Verbose {
 		green.Printf("📦 Extracted %d test cases from zip file\n", len(testCases))
@@ -142,28 +308,181 @@ func (f *TestCaseFetcher) extractTestCasesFromZip(zipData []byte) ([]TestCase, e
 	return testCases, nil
 }
 
-func (f *TestCaseFetcher) parseTestNumber(filename, suffix string) int {
-	// Remove the suffix to get the base name
-	base := strings.TrimSuffix(filename, suffix)
+// checkArchivePairs rejects an archive with an input that has no expected
+// output or the other way round. CSES archives are always complete, so a
+// missing half means a truncated or broken download, which must not pass as
+// a smaller test set. The error names the entry of the lowest such test and
+// lists every incomplete test.
+func checkArchivePairs(inputs, outputs map[int]string, sources map[string]string) error {
+	var noOutput, noInput []int
+	for number := range inputs {
+		if _, ok := outputs[number]; !ok {
+			noOutput = append(noOutput, number)
+		}
+	}
+	for number := range outputs {
+		if _, ok := inputs[number]; !ok {
+			noInput = append(noInput, number)
+		}
+	}
+	if len(noOutput) == 0 && len(noInput) == 0 {
+		return nil
+	}
+	slices.Sort(noOutput)
+	slices.Sort(noInput)
+
+	var missing []string
+	if len(noOutput) > 0 {
+		missing = append(missing, "no expected output for test(s) "+formatTestNumbers(noOutput))
+	}
+	if len(noInput) > 0 {
+		missing = append(missing, "no input for test(s) "+formatTestNumbers(noInput))
+	}
 
-	// Handle different filename patterns
-	// e.g., "1.in", "test1.in", "input1.in", etc.
-	patterns := []string{
-		base,                               // Direct number
-		strings.TrimPrefix(base, "test"),   // Remove "test" prefix
-		strings.TrimPrefix(base, "input"),  // Remove "input" prefix
-		strings.TrimPrefix(base, "output"), // Remove "output" prefix
+	entry := ""
+	switch {
+	case len(noInput) == 0 || (len(noOutput) > 0 && noOutput[0] < noInput[0]):
+		entry = sources[fmt.Sprintf("in %d", noOutput[0])]
+	default:
+		entry = sources[fmt.Sprintf("out %d", noInput[0])]
 	}
+	return &ArchiveError{Entry: entry, Reason: "is not paired: " + strings.Join(missing, "; ")}
+}
 
-	for _, pattern := range patterns {
-		if num, err := strconv.Atoi(pattern); err == nil && num > 0 {
-			return num
+// sortTestCases orders tests by number. Archives and directories are read
+// through maps and file names sort 10 before 2, and "test 3" must be the same
+// test on every run.
+func sortTestCases(testCases []TestCase) {
+	slices.SortFunc(testCases, func(a, b TestCase) int {
+		return cmp.Compare(a.Number, b.Number)
+	})
+}
+
+// warnIncompleteTests reports the tests that have an input but no expected
+// output or the other way round. They cannot be run and are left out, which
+// must not go unnoticed.
+func warnIncompleteTests(inputs, outputs map[int]string, where string) {
+	var noOutput, noInput []int
+	for number := range inputs {
+		if _, ok := outputs[number]; !ok {
+			noOutput = append(noOutput, number)
+		}
+	}
+	for number := range outputs {
+		if _, ok := inputs[number]; !ok {
+			noInput = append(noInput, number)
 		}
 	}
+	incomplete := len(noOutput) + len(noInput)
+	if incomplete == 0 {
+		return
+	}
+
+	total := len(inputs) + len(noInput)
+	yellow.Printf("⚠️  Skipping %d of %d tests in %s, their files are incomplete:\n", incomplete, total, where)
+	if len(noOutput) > 0 {
+		yellow.Printf("   no expected output for test(s) %s\n", formatTestNumbers(noOutput))
+	}
+	if len(noInput) > 0 {
+		yellow.Printf("   no input for test(s) %s\n", formatTestNumbers(noInput))
+	}
+}
+
+// formatTestNumbers lists test numbers in order, runs shortened to ranges:
+// "1-3, 7, 9-10"
+func formatTestNumbers(numbers []int) string {
+	slices.Sort(numbers)
+	var parts []string
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+		if j == i {
+			parts = append(parts, strconv.Itoa(numbers[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", numbers[i], numbers[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Directory and name prefixes that mark a .txt file as a test's input or
+// expected output, longest first so "input" is not read as "in" + "put"
+var (
+	testInputNames  = []string{"inputs", "input", "in"}
+	testOutputNames = []string{"outputs", "output", "answers", "answer", "expected", "ans", "out"}
+	testNamePrefix  = []string{"inputs", "input", "outputs", "output", "answers", "answer", "expected", "test", "ans", "in", "out"}
+)
+
+// parseTestFile tells whether the file (an archive entry or a path) holds a
+// test's input or its expected output, and which test; number is 0 for other
+// files. Recognized layouts, numbers zero-padded or not:
+//
+//	1.in + 1.out (or 1.ans), test1.in, input1.in + output1.out
+//	input/01.txt + output/01.txt (also in/, out/, answers/, ...)
+//	input01.txt + output01.txt
+func (f *TestCaseFetcher) parseTestFile(name string) (input bool, number int) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	base := path.Base(name)
+	dir := strings.ToLower(path.Base(path.Dir(name)))
+	if strings.HasPrefix(base, ".") || strings.HasPrefix(name, "__MACOSX/") {
+		return false, 0
+	}
 
+	ext := path.Ext(base)
+	stem := strings.ToLower(strings.TrimSuffix(base, ext))
+	switch strings.ToLower(ext) {
+	case ".in":
+		input = true
+	case ".out", ".ans":
+		input = false
+	case ".txt":
+		switch {
+		case slices.Contains(testInputNames, dir):
+			input = true
+		case slices.Contains(testOutputNames, dir):
+			input = false
+		case hasAnyPrefix(stem, testInputNames):
+			input = true
+		case hasAnyPrefix(stem, testOutputNames):
+			input = false
+		default:
+			return false, 0
+		}
+	default:
+		return false, 0
+	}
+	return input, f.parseTestNumber(stem)
+}
+
+// parseTestNumber reads the number of a file name without its extension:
+// 1, 01, test1, input_01, output-1
+func (f *TestCaseFetcher) parseTestNumber(stem string) int {
+	for _, prefix := range testNamePrefix {
+		if strings.HasPrefix(stem, prefix) {
+			stem = strings.TrimPrefix(stem, prefix)
+			break
+		}
+	}
+	stem = strings.TrimLeft(stem, "_-. ")
+
+	if num, err := strconv.Atoi(stem); err == nil && num > 0 {
+		return num
+	}
 	return 0
 }
 
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func (f *TestCaseFetcher) loadCachedTestCases(cacheDir string) ([]TestCase, error) {
 	files, err := os.ReadDir(cacheDir)
 	if err != nil {
@@ -178,25 +497,28 @@ func (f *TestCaseFetcher) loadCachedTestCases(cacheDir string) ([]TestCase, erro
 			inputPath := filepath.Join(cacheDir, file.Name())
 			outputPath := filepath.Join(cacheDir, number+".out")
 
-			input, err := os.ReadFile(inputPath)
+			inputInfo, err := os.Stat(inputPath)
 			if err != nil {
 				continue
 			}
 
-			output, err := os.ReadFile(outputPath)
+			outputInfo, err := os.Stat(outputPath)
 			if err != nil {
 				continue
 			}
 
 			testNum, _ := strconv.Atoi(number)
 			testCases = append(testCases, TestCase{
-				Input:    string(input),
-				Expected: string(output),
-				Number:   testNum,
+				Number:       testNum,
+				InputPath:    inputPath,
+				ExpectedPath: outputPath,
+				Size:         inputInfo.Size() + outputInfo.Size(),
+				lazy:         true,
 			})
 		}
 	}
 
+	sortTestCases(testCases)
 	return testCases, nil
 }
 
@@ -205,21 +527,25 @@ func (f *TestCaseFetcher) cacheTestCases(cacheDir string, testCases []TestCase)
 		return err
 	}
 
+	store := newDedupWriter()
 	for _, testCase := range testCases {
 		inputPath := filepath.Join(cacheDir, fmt.Sprintf("%d.in", testCase.Number))
 		outputPath := filepath.Join(cacheDir, fmt.Sprintf("%d.out", testCase.Number))
 
-		if err := os.WriteFile(inputPath, []byte(testCase.Input), 0644); err != nil {
+		if err := store.write(inputPath, testCase.Input); err != nil {
 			return err
 		}
 
-		if err := os.WriteFile(outputPath, []byte(testCase.Expected), 0644); err != nil {
+		if err := store.write(outputPath, testCase.Expected); err != nil {
 			return err
 		}
 	}
 
 	if f.config.Verbose {
 		green.Printf("💾 Cached %d test cases to %s\n", len(testCases), cacheDir)
+		if store.linked > 0 {
+			cyan.Printf("🔁 %d duplicate file(s) stored once (see `%s tests list -problem=%s`)\n", store.linked, AppName, filepath.Base(cacheDir))
+		}
 	}
 
 	return nil
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// zipEntry is one file of a test archive built by makeZip
+type zipEntry struct {
+	name, content string
+}
+
+func makeZip(t *testing.T, entries ...zipEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		f, err := w.Create(entry.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractTestCasesFromZip(t *testing.T) {
+	f := NewTestCaseFetcher(&Config{}, nil)
+	data := makeZip(t,
+		zipEntry{"10.in", "10\n"},
+		zipEntry{"10.out", "ten\n"},
+		zipEntry{"2.in", "2\n"},
+		zipEntry{"2.out", "two\n"},
+		zipEntry{"tests/", ""},
+		zipEntry{"__MACOSX/._2.in", "junk"},
+		zipEntry{"README.md", "not a test"},
+	)
+
+	testCases, err := f.extractTestCasesFromZip(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(testCases) != 2 {
+		t.Fatalf("got %d tests, want 2", len(testCases))
+	}
+	for i, want := range []TestCase{{Number: 2, Input: "2\n", Expected: "two\n"}, {Number: 10, Input: "10\n", Expected: "ten\n"}} {
+		got := testCases[i]
+		if got.Number != want.Number || got.Input != want.Input || got.Expected != want.Expected {
+			t.Errorf("test %d = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestExtractTestCasesFromZipLayouts(t *testing.T) {
+	f := NewTestCaseFetcher(&Config{}, nil)
+	layouts := map[string][]zipEntry{
+		"numbered .in/.ans": {{"1.in", "a"}, {"1.ans", "b"}},
+		"test prefix":       {{"test1.in", "a"}, {"test1.out", "b"}},
+		"input/output dirs": {{"tests/input/01.txt", "a"}, {"tests/output/01.txt", "b"}},
+		"in/answers dirs":   {{"in/1.txt", "a"}, {"answers/1.txt", "b"}},
+		"prefixed .txt":     {{"input01.txt", "a"}, {"output01.txt", "b"}},
+		"separated prefix":  {{"input_1.in", "a"}, {"output-1.out", "b"}},
+		"backslashes":       {{`input\1.txt`, "a"}, {`output\1.txt`, "b"}},
+	}
+	for name, entries := range layouts {
+		t.Run(name, func(t *testing.T) {
+			testCases, err := f.extractTestCasesFromZip(makeZip(t, entries...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(testCases) != 1 || testCases[0].Number != 1 || testCases[0].Input != "a" || testCases[0].Expected != "b" {
+				t.Errorf("got %+v, want test 1 with input a and output b", testCases)
+			}
+		})
+	}
+}
+
+func TestExtractTestCasesFromZipRejects(t *testing.T) {
+	f := NewTestCaseFetcher(&Config{}, nil)
+	tests := []struct {
+		name    string
+		entries []zipEntry
+		entry   string
+	}{
+		{"absolute path", []zipEntry{{"/etc/1.in", "1"}, {"1.out", "1"}}, "/etc/1.in"},
+		{"drive letter", []zipEntry{{"C:/tests/1.in", "1"}, {"1.out", "1"}}, "C:/tests/1.in"},
+		{"parent directory", []zipEntry{{"tests/../../1.in", "1"}, {"1.out", "1"}}, "tests/../../1.in"},
+		{"duplicate test", []zipEntry{{"1.in", "1"}, {"1.out", "1"}, {"input/1.txt", "2"}}, "input/1.txt"},
+		{"input without output", []zipEntry{{"1.in", "1"}, {"1.out", "1"}, {"2.in", "2"}, {"3.in", "3"}}, "2.in"},
+		{"output without input", []zipEntry{{"1.in", "1"}, {"1.out", "1"}, {"tests/4.ans", "4"}}, "tests/4.ans"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.extractTestCasesFromZip(makeZip(t, tt.entries...))
+			var archiveErr *ArchiveError
+			if !errors.As(err, &archiveErr) {
+				t.Fatalf("got error %v, want an *ArchiveError", err)
+			}
+			if archiveErr.Entry != tt.entry {
+				t.Errorf("ArchiveError.Entry = %q, want %q", archiveErr.Entry, tt.entry)
+			}
+		})
+	}
+}
+
+func TestExtractTestCasesFromZipUnpaired(t *testing.T) {
+	f := NewTestCaseFetcher(&Config{}, nil)
+	data := makeZip(t,
+		zipEntry{"1.in", "1"}, zipEntry{"1.out", "1"},
+		zipEntry{"3.in", "3"}, zipEntry{"2.in", "2"}, zipEntry{"7.in", "7"},
+		zipEntry{"5.out", "5"}, zipEntry{"9.out", "9"},
+	)
+
+	_, err := f.extractTestCasesFromZip(data)
+	var archiveErr *ArchiveError
+	if !errors.As(err, &archiveErr) {
+		t.Fatalf("got error %v, want an *ArchiveError", err)
+	}
+	if archiveErr.Entry != "2.in" {
+		t.Errorf("ArchiveError.Entry = %q, want the lowest unpaired entry 2.in", archiveErr.Entry)
+	}
+	for _, want := range []string{"no expected output for test(s) 2-3, 7", "no input for test(s) 5, 9"} {
+		if !strings.Contains(archiveErr.Reason, want) {
+			t.Errorf("ArchiveError.Reason = %q, want it to contain %q", archiveErr.Reason, want)
+		}
+	}
+}
+
+func TestExtractTestCasesFromZipEmpty(t *testing.T) {
+	f := NewTestCaseFetcher(&Config{}, nil)
+	if _, err := f.extractTestCasesFromZip(makeZip(t, zipEntry{"notes.txt", "nothing"})); err == nil {
+		t.Error("an archive without tests was accepted")
+	}
+	if _, err := f.extractTestCasesFromZip([]byte("not a zip")); err == nil {
+		t.Error("data that is not a zip was accepted")
+	}
+}
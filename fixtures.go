@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// fixtureFile is the file inside the fixtures directory holding the recorded exchanges
+const fixtureFile = "fixtures.json"
+
+// fixtureEntry is one recorded HTTP response. Request bodies (which carry the
+// password) are never stored, and session cookies are replaced by a placeholder.
+type fixtureEntry struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// newFixtureTransport returns the transport for -record-fixtures or
+// -replay-fixtures, or nil when neither is set
+func newFixtureTransport(config *Config) http.RoundTripper {
+	switch {
+	case config.ReplayFixtures != "":
+		return &replayTransport{dir: config.ReplayFixtures}
+	case config.RecordFixtures != "":
+		return &recordingTransport{next: http.DefaultTransport, dir: config.RecordFixtures}
+	}
+	return nil
+}
+
+// fixtureKey identifies a request; identical requests are replayed in recorded order
+func fixtureKey(method string, u *url.URL) string {
+	return method + " " + u.RequestURI()
+}
+
+// recordingTransport passes requests through to CSES and saves every response
+type recordingTransport struct {
+	next http.RoundTripper
+	dir  string
+
+	mu      sync.Mutex
+	entries []fixtureEntry
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	header.Del("Content-Length")
+	redactCookies(header)
+
+	t.mu.Lock()
+	t.entries = append(t.entries, fixtureEntry{
+		Method: req.Method,
+		Path:   req.URL.RequestURI(),
+		Status: resp.StatusCode,
+		Header: header,
+		Body:   body,
+	})
+	err = t.save()
+	t.mu.Unlock()
+
+	if err != nil {
+		yellow.Printf("⚠️  Failed to record fixture: %v\n", err)
+	}
+
+	return resp, nil
+}
+
+// save rewrites the fixture file; callers must hold t.mu
+func (t *recordingTransport) save() error {
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create fixtures directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(t.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixtures: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(t.dir, fixtureFile), data, 0644)
+}
+
+// redactCookies replaces the PHP session ID so fixtures can be shared safely
+func redactCookies(header http.Header) {
+	cookies := header.Values("Set-Cookie")
+	header.Del("Set-Cookie")
+	for _, cookie := range cookies {
+		if strings.HasPrefix(cookie, "PHPSESSID=") {
+			rest := ""
+			if i := strings.Index(cookie, ";"); i >= 0 {
+				rest = cookie[i:]
+			}
+			cookie = "PHPSESSID=fixture-session" + rest
+		}
+		header.Add("Set-Cookie", cookie)
+	}
+}
+
+// replayTransport answers requests from a fixtures directory without touching the network
+type replayTransport struct {
+	dir string
+
+	once    sync.Once
+	loadErr error
+	mu      sync.Mutex
+	queues  map[string][]fixtureEntry
+}
+
+func (t *replayTransport) load() {
+	data, err := os.ReadFile(filepath.Join(t.dir, fixtureFile))
+	if err != nil {
+		t.loadErr = fmt.Errorf("failed to read fixtures: %w", err)
+		return
+	}
+
+	var entries []fixtureEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.loadErr = fmt.Errorf("failed to parse fixtures: %w", err)
+		return
+	}
+
+	t.queues = make(map[string][]fixtureEntry)
+	for _, entry := range entries {
+		key := entry.Method + " " + entry.Path
+		t.queues[key] = append(t.queues[key], entry)
+	}
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.once.Do(t.load)
+	if t.loadErr != nil {
+		return nil, t.loadErr
+	}
+
+	key := fixtureKey(req.Method, req.URL)
+
+	t.mu.Lock()
+	queue := t.queues[key]
+	if len(queue) == 0 {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("no recorded response for %s in %s", key, t.dir)
+	}
+	entry := queue[0]
+	t.queues[key] = queue[1:]
+	t.mu.Unlock()
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.Status, http.StatusText(entry.Status)),
+		StatusCode:    entry.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        entry.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gcTracePattern matches a line GODEBUG=gctrace=1 prints per collection:
+// "gc 3 @0.012s 8%: 0.003+0.037+0.002 ms clock, ..., 4->4->1 MB, ..."
+// The first and last clock times are the stop-the-world pauses.
+var gcTracePattern = regexp.MustCompile(`^gc \d+ @\S+ \d+%: ([0-9.]+)\+[0-9.]+\+([0-9.]+) ms clock, .*?(\d+)->(\d+)->(\d+) MB`)
+
+// A test is allocation heavy when it allocates this much, or when the
+// collector pauses it for gcHeavyPauseShare of its time
+const (
+	gcHeavyAllocation = 128 << 20
+	gcHeavyPauseShare = 0.10
+)
+
+// GCStats summarizes the garbage collections of one test run (-gc-stats)
+type GCStats struct {
+	Cycles int
+	Pause  time.Duration // stop-the-world time of all collections
+
+	// Allocated counts what was allocated up to the last collection, in the
+	// whole megabytes gctrace reports; it is 0 if nothing was collected
+	Allocated int64
+}
+
+// parseGCTrace takes the gctrace lines out of the solution's stderr and sums
+// them up. The rest of stderr is returned as the solution wrote it.
+func parseGCTrace(stderr string) (*GCStats, string) {
+	stats := &GCStats{}
+	var rest []string
+	var liveMB int64
+	for _, line := range strings.SplitAfter(stderr, "\n") {
+		match := gcTracePattern.FindStringSubmatch(line)
+		if match == nil {
+			rest = append(rest, line)
+			continue
+		}
+		stats.Cycles++
+		for _, pause := range []string{match[1], match[2]} {
+			ms, _ := strconv.ParseFloat(pause, 64)
+			stats.Pause += time.Duration(ms * float64(time.Millisecond))
+		}
+
+		// Everything on the heap at the end of a collection beyond what the
+		// previous one left alive was allocated in between
+		endMB, _ := strconv.ParseInt(match[4], 10, 64)
+		stats.Allocated += max(endMB-liveMB, 0) << 20
+		liveMB, _ = strconv.ParseInt(match[5], 10, 64)
+	}
+	return stats, strings.Join(rest, "")
+}
+
+// Heavy reports whether the run allocated a lot or spent a large share of
+// duration in collector pauses
+func (s *GCStats) Heavy(duration time.Duration) bool {
+	return s.Allocated >= gcHeavyAllocation || (duration > 0 && s.Pause.Seconds() >= gcHeavyPauseShare*duration.Seconds())
+}
+
+func (s *GCStats) String() string {
+	if s.Cycles == 0 {
+		return "no collections"
+	}
+	return fmt.Sprintf("%d collection(s), %.2fms paused, %s allocated", s.Cycles, s.Pause.Seconds()*1000, formatBytes(s.Allocated))
+}
+
+// displayHeavyAllocations names the tests that allocate heavily, which are
+// the first place to look for buffers to reuse or slices to preallocate
+func displayHeavyAllocations(results []TestResult) {
+	var heavy []int
+	for _, result := range results {
+		if result.GC != nil && result.GC.Heavy(result.Duration) {
+			heavy = append(heavy, result.TestNumber)
+		}
+	}
+	if len(heavy) > 0 {
+		yellow.Printf("🗑️  Allocation-heavy test(s) %s: %s or more allocated, or %.0f%%+ of the time in GC pauses\n",
+			formatTestNumbers(heavy), formatBytes(gcHeavyAllocation), gcHeavyPauseShare*100)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// GenSpec is a parsed -gen-spec, a tiny language describing random inputs so
+// simple problems can be stress tested without a generator program:
+//
+//	n:1..2e5                  an integer, on its own line
+//	a:n ints 1..1e9           n integers on one line
+//	edges:m*2 ints 1..n       m lines of 2 integers
+//	s:n chars a..z            a string of n letters
+//	p:n perm                  a permutation of 1..n
+//
+// Declarations are separated by spaces; a trailing comma keeps the next one
+// on the same line (`n:1..10, m:1..10`). Counts and bounds may refer to
+// earlier integers, optionally plus or minus a constant (`k:1..n-1`).
+type GenSpec struct {
+	lines [][]genDecl
+}
+
+type genDecl struct {
+	name       string
+	kind       string // "int", "ints", "chars" or "perm"
+	count, per genExpr
+	grid       bool // count*per ints: count lines of per integers
+	lo, hi     genExpr
+	from, to   byte // chars
+}
+
+// genExpr is a constant or an earlier integer plus an offset
+type genExpr struct {
+	variable string
+	value    int64
+}
+
+// ParseGenSpec parses a -gen-spec string
+func ParseGenSpec(spec string) (*GenSpec, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty generator spec")
+	}
+
+	parsed := &GenSpec{}
+	defined := make(map[string]bool)
+	sameLine := false
+
+	for len(fields) > 0 {
+		// A declaration runs until the next token that starts one
+		end := 1
+		for end < len(fields) && !strings.Contains(fields[end], ":") {
+			end++
+		}
+		words := fields[:end]
+		fields = fields[end:]
+
+		last := words[len(words)-1]
+		continues := strings.HasSuffix(last, ",")
+		words[len(words)-1] = strings.TrimSuffix(last, ",")
+
+		decl, err := parseGenDecl(words, defined)
+		if err != nil {
+			return nil, err
+		}
+		if decl.kind == "int" {
+			defined[decl.name] = true
+		}
+
+		if sameLine {
+			parsed.lines[len(parsed.lines)-1] = append(parsed.lines[len(parsed.lines)-1], decl)
+		} else {
+			parsed.lines = append(parsed.lines, []genDecl{decl})
+		}
+		sameLine = continues
+	}
+
+	return parsed, nil
+}
+
+func parseGenDecl(words []string, defined map[string]bool) (genDecl, error) {
+	name, first, _ := strings.Cut(words[0], ":")
+	decl := genDecl{name: name}
+	if name == "" {
+		return decl, fmt.Errorf("missing name in %q", strings.Join(words, " "))
+	}
+	if first != "" {
+		words = append([]string{first}, words[1:]...)
+	} else {
+		words = words[1:]
+	}
+
+	expr := func(s string) (genExpr, error) {
+		return parseGenExpr(s, defined)
+	}
+
+	var err error
+	switch {
+	case len(words) == 1:
+		decl.kind = "int"
+		decl.lo, decl.hi, err = parseGenRange(words[0], expr)
+	case len(words) == 2 && words[1] == "perm":
+		decl.kind = "perm"
+		decl.count, err = expr(words[0])
+	case len(words) == 3 && words[1] == "ints":
+		decl.kind = "ints"
+		count, per, hasPer := strings.Cut(words[0], "*")
+		if decl.count, err = expr(count); err != nil {
+			break
+		}
+		if decl.grid = hasPer; hasPer {
+			if decl.per, err = expr(per); err != nil {
+				break
+			}
+		}
+		decl.lo, decl.hi, err = parseGenRange(words[2], expr)
+	case len(words) == 3 && words[1] == "chars":
+		decl.kind = "chars"
+		if decl.count, err = expr(words[0]); err != nil {
+			break
+		}
+		from, to, ok := strings.Cut(words[2], "..")
+		if !ok || len(from) != 1 || len(to) != 1 || from[0] > to[0] {
+			err = fmt.Errorf("invalid character range %q (e.g. a..z)", words[2])
+			break
+		}
+		decl.from, decl.to = from[0], to[0]
+	default:
+		err = fmt.Errorf("cannot parse %q (see the -gen-spec examples in the README)", strings.Join(words, " "))
+	}
+	if err != nil {
+		return decl, fmt.Errorf("%s: %w", name, err)
+	}
+	return decl, nil
+}
+
+func parseGenRange(s string, expr func(string) (genExpr, error)) (genExpr, genExpr, error) {
+	lo, hi, ok := strings.Cut(s, "..")
+	if !ok {
+		return genExpr{}, genExpr{}, fmt.Errorf("invalid range %q (e.g. 1..2e5)", s)
+	}
+	loExpr, err := expr(lo)
+	if err != nil {
+		return genExpr{}, genExpr{}, err
+	}
+	hiExpr, err := expr(hi)
+	if err != nil {
+		return genExpr{}, genExpr{}, err
+	}
+	return loExpr, hiExpr, nil
+}
+
+// parseGenExpr parses 42, 2e5, -1e9, n, n-1 or n+2
+func parseGenExpr(s string, defined map[string]bool) (genExpr, error) {
+	if value, err := parseGenNumber(s); err == nil {
+		return genExpr{value: value}, nil
+	}
+
+	name, offset := s, int64(0)
+	if i := strings.LastIndexAny(s, "+-"); i > 0 {
+		value, err := parseGenNumber(s[i+1:])
+		if err != nil {
+			return genExpr{}, fmt.Errorf("invalid offset in %q", s)
+		}
+		name, offset = s[:i], value
+		if s[i] == '-' {
+			offset = -offset
+		}
+	}
+
+	if !defined[name] {
+		return genExpr{}, fmt.Errorf("%q is neither a number nor an earlier integer", s)
+	}
+	return genExpr{variable: name, value: offset}, nil
+}
+
+// parseGenNumber accepts integers, also written as 2e5
+func parseGenNumber(s string) (int64, error) {
+	if value, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return value, nil
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value != math.Trunc(value) || math.Abs(value) > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid integer %q", s)
+	}
+	return int64(value), nil
+}
+
+func (g genExpr) eval(values map[string]int64) int64 {
+	if g.variable == "" {
+		return g.value
+	}
+	return values[g.variable] + g.value
+}
+
+// Generate renders one random input; the same seed always gives the same input
+func (s *GenSpec) Generate(seed uint64) (string, error) {
+	r := rand.New(rand.NewPCG(seed, 0))
+	values := make(map[string]int64)
+
+	var out strings.Builder
+	for _, line := range s.lines {
+		for i, decl := range line {
+			if i > 0 {
+				out.WriteByte(' ')
+			}
+			if err := decl.generate(r, values, &out); err != nil {
+				return "", fmt.Errorf("%s: %w", decl.name, err)
+			}
+		}
+		out.WriteByte('\n')
+	}
+	return out.String(), nil
+}
+
+func (d genDecl) generate(r *rand.Rand, values map[string]int64, out *strings.Builder) error {
+	if d.kind == "int" {
+		value, err := randomInt(r, d.lo.eval(values), d.hi.eval(values))
+		if err != nil {
+			return err
+		}
+		values[d.name] = value
+		out.WriteString(strconv.FormatInt(value, 10))
+		return nil
+	}
+
+	count := d.count.eval(values)
+	if count < 0 {
+		return fmt.Errorf("negative count %d", count)
+	}
+
+	switch d.kind {
+	case "perm":
+		for i, value := range r.Perm(int(count)) {
+			if i > 0 {
+				out.WriteByte(' ')
+			}
+			out.WriteString(strconv.Itoa(value + 1))
+		}
+	case "chars":
+		for i := int64(0); i < count; i++ {
+			out.WriteByte(d.from + byte(r.IntN(int(d.to-d.from)+1)))
+		}
+	case "ints":
+		rows, per := int64(1), count
+		if d.grid {
+			rows, per = count, d.per.eval(values)
+		}
+		for row := int64(0); row < rows; row++ {
+			if row > 0 {
+				out.WriteByte('\n')
+			}
+			for i := int64(0); i < per; i++ {
+				value, err := randomInt(r, d.lo.eval(values), d.hi.eval(values))
+				if err != nil {
+					return err
+				}
+				if i > 0 {
+					out.WriteByte(' ')
+				}
+				out.WriteString(strconv.FormatInt(value, 10))
+			}
+		}
+	}
+	return nil
+}
+
+// randomInt returns a uniform integer in [lo, hi]
+func randomInt(r *rand.Rand, lo, hi int64) (int64, error) {
+	if lo > hi {
+		return 0, fmt.Errorf("empty range %d..%d", lo, hi)
+	}
+	span := uint64(hi-lo) + 1
+	if span == 0 {
+		return int64(r.Uint64()), nil
+	}
+	return lo + int64(r.Uint64N(span)), nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseGenSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"n:1..2e5", false},
+		{"n:1..10 a:n ints 1..1e9", false},
+		{"n:1..10, m:1..10 edges:m*2 ints 1..n", false},
+		{"n:1..10 s:n chars a..z", false},
+		{"n:1..10 p:n perm", false},
+		{"n:2..10 k:1..n-1", false},
+		{"", true},
+		{"a:n ints 1..5", true},          // n is not declared
+		{"n:1..10 s:n chars z..a", true}, // reversed character range
+		{"n:1to10", true},
+		{"n:1.5..10", true},
+		{":1..10", true},
+		{"n:1..10 a:n floats 1..5", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseGenSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseGenSpec(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenSpecGenerate(t *testing.T) {
+	spec, err := ParseGenSpec("n:1..20, k:1..n a:n ints -5..5 p:n perm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for seed := range uint64(50) {
+		input, err := spec.Generate(seed)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		again, _ := spec.Generate(seed)
+		if input != again {
+			t.Fatalf("seed %d generated two different inputs", seed)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(input, "\n"), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("seed %d: got %d lines, want 3:\n%s", seed, len(lines), input)
+		}
+		header := atoiFields(t, lines[0])
+		n, k := header[0], header[1]
+		if n < 1 || n > 20 || k < 1 || k > n {
+			t.Errorf("seed %d: n=%d k=%d out of range", seed, n, k)
+		}
+		a := atoiFields(t, lines[1])
+		if len(a) != n || slices.Min(a) < -5 || slices.Max(a) > 5 {
+			t.Errorf("seed %d: a = %v, want %d values in -5..5", seed, a, n)
+		}
+		p := atoiFields(t, lines[2])
+		slices.Sort(p)
+		for i, v := range p {
+			if v != i+1 {
+				t.Errorf("seed %d: %v is not a permutation of 1..%d", seed, lines[2], n)
+				break
+			}
+		}
+	}
+}
+
+func atoiFields(t *testing.T, line string) []int {
+	t.Helper()
+	var values []int
+	for _, field := range strings.Fields(line) {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			t.Fatalf("not an integer in %q: %v", line, err)
+		}
+		values = append(values, value)
+	}
+	return values
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// commitOnPass commits the solution with a standardized message after a run
+// in which every test passed, when -git-commit-on-pass is set
+func (r *TestRunner) commitOnPass() {
+	if !r.config.GitCommitOnPass || r.lastRun == nil {
+		return
+	}
+	if r.config.Test != 0 {
+		cyan.Println("📝 Not committing: only one test ran (-test)")
+		return
+	}
+
+	message := acceptedCommitMessage(lookupProblem(r.config, r.config.ProblemID), r.lastRun)
+	if output, err := exec.Command("git", "add", "--", r.config.FilePath).CombinedOutput(); err != nil {
+		yellow.Printf("⚠️  git add failed: %s\n", strings.TrimSpace(string(output)))
+		return
+	}
+
+	// diff --quiet exits 0 when the staged file matches HEAD
+	if exec.Command("git", "diff", "--cached", "--quiet", "--", r.config.FilePath).Run() == nil {
+		cyan.Println("📝 Solution already committed, nothing to commit")
+		return
+	}
+
+	output, err := exec.Command("git", "commit", "-m", message, "--", r.config.FilePath).CombinedOutput()
+	if err != nil {
+		yellow.Printf("⚠️  git commit failed: %s\n", strings.TrimSpace(string(output)))
+		return
+	}
+	green.Printf("📝 Committed %s: %s\n", r.config.FilePath, strings.SplitN(message, "\n", 2)[0])
+}
+
+// acceptedCommitMessage is e.g. "Solve 1068: Weird Algorithm" with the timing summary in the body
+func acceptedCommitMessage(problem ProblemInfo, record *RunRecord) string {
+	subject := "Solve " + problem.ID
+	if problem.Name != "" {
+		subject += ": " + problem.Name
+	}
+
+	var slowest TestRecord
+	var total float64
+	for _, test := range record.Tests {
+		total += test.Duration
+		if test.Duration > slowest.Duration {
+			slowest = test
+		}
+	}
+
+	return fmt.Sprintf("%s\n\nAll %d tests passed; slowest %.2fms (test %d), average %.2fms.",
+		subject, record.Total, slowest.Duration, slowest.Number, total/float64(len(record.Tests)))
+}
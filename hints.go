@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// Hint is one performance suggestion for the solution
+type Hint struct {
+	Line       int
+	Message    string
+	Suggestion string
+}
+
+// hintChecker walks a type-checked solution looking for common CSES pitfalls
+type hintChecker struct {
+	fset    *token.FileSet
+	info    *types.Info
+	fmtName string
+	hints   []Hint
+
+	loopDepth int
+	function  *types.Func // the function declaration being walked
+	recursive map[*types.Func]bool
+}
+
+// analyzeHints type-checks the solution (tolerating errors, so hints work on
+// code that does not build yet) and returns the hints in line order
+func analyzeHints(path string) ([]Hint, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, parseErrorDiagnostics(err)
+	}
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: importer.Default(), Error: func(error) {}}
+	conf.Check("main", fset, []*ast.File{file}, info)
+
+	c := &hintChecker{fset: fset, info: info, fmtName: importName(file, "fmt"), recursive: make(map[*types.Func]bool)}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		c.function, _ = info.Defs[fn.Name].(*types.Func)
+		c.walk(fn.Body)
+	}
+
+	if len(c.recursive) > 0 && !callsFunction(file, info, "runtime/debug", "SetMaxStack") {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			if object, ok := info.Defs[fn.Name].(*types.Func); ok && c.recursive[object] {
+				c.add(fn.Pos(), fmt.Sprintf("%s is recursive", fn.Name.Name),
+					"deep recursion (e.g. DFS over a 2·10^5 node path) grows the goroutine stack, which counts towards the memory limit; "+
+						"use an explicit stack, or raise the limit with debug.SetMaxStack if it is the stack size that fails")
+			}
+		}
+	}
+
+	sort.SliceStable(c.hints, func(i, j int) bool { return c.hints[i].Line < c.hints[j].Line })
+	return c.hints, nil
+}
+
+func (c *hintChecker) add(pos token.Pos, message, suggestion string) {
+	c.hints = append(c.hints, Hint{Line: c.fset.Position(pos).Line, Message: message, Suggestion: suggestion})
+}
+
+func (c *hintChecker) walk(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			c.loopDepth++
+			var body *ast.BlockStmt
+			if loop, ok := node.(*ast.ForStmt); ok {
+				body = loop.Body
+			} else {
+				body = node.(*ast.RangeStmt).Body
+			}
+			c.walk(body)
+			c.loopDepth--
+			return false
+		case *ast.CallExpr:
+			c.checkCall(node)
+		case *ast.AssignStmt:
+			c.checkConcat(node)
+		case *ast.CompositeLit:
+			c.checkMap(node, node.Type)
+		}
+		return true
+	})
+}
+
+func (c *hintChecker) checkCall(call *ast.CallExpr) {
+	// Direct recursion, reported once per function after the walk
+	if ident, ok := call.Fun.(*ast.Ident); ok && c.function != nil && c.info.Uses[ident] == c.function {
+		c.recursive[c.function] = true
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok && c.function != nil && c.info.Uses[sel.Sel] == c.function {
+		c.recursive[c.function] = true
+	}
+
+	if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "make" && len(call.Args) > 0 {
+		c.checkMap(call, call.Args[0])
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || c.loopDepth == 0 {
+		return
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != c.fmtName || c.fmtName == "" {
+		return
+	}
+
+	switch sel.Sel.Name {
+	case "Scan", "Scanf", "Scanln":
+		c.add(call.Pos(), fmt.Sprintf("fmt.%s in a loop reads stdin unbuffered", sel.Sel.Name),
+			fmt.Sprintf("read through a bufio.Reader (`%s optimize-io fix` adds one)", AppName))
+	case "Print", "Printf", "Println":
+		c.add(call.Pos(), fmt.Sprintf("fmt.%s in a loop writes stdout unbuffered", sel.Sel.Name),
+			fmt.Sprintf("write through a bufio.Writer (`%s optimize-io fix` adds one)", AppName))
+	case "Fscan", "Fscanf", "Fscanln":
+		c.add(call.Pos(), fmt.Sprintf("fmt.%s in a loop is reflection-based", sel.Sel.Name),
+			"for 10^6 or more numbers a hand-written integer reader over bufio.Reader is several times faster")
+	}
+}
+
+// checkConcat flags s += ... and s = s + ... on strings inside loops
+func (c *hintChecker) checkConcat(assign *ast.AssignStmt) {
+	if c.loopDepth == 0 || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 || !c.isString(assign.Lhs[0]) {
+		return
+	}
+
+	concat := assign.Tok == token.ADD_ASSIGN
+	if binary, ok := assign.Rhs[0].(*ast.BinaryExpr); ok && assign.Tok == token.ASSIGN && binary.Op == token.ADD {
+		if lhs, ok := assign.Lhs[0].(*ast.Ident); ok {
+			if x, ok := binary.X.(*ast.Ident); ok && x.Name == lhs.Name {
+				concat = true
+			}
+		}
+	}
+
+	if concat {
+		c.add(assign.Pos(), "string concatenation in a loop copies the whole string every time (quadratic)",
+			"collect the pieces in a strings.Builder or []byte, or write them straight to a bufio.Writer")
+	}
+}
+
+// checkMap flags maps keyed by integers, which are usually dense indices
+func (c *hintChecker) checkMap(node ast.Node, typeExpr ast.Expr) {
+	if typeExpr == nil || c.info.TypeOf(typeExpr) == nil {
+		return
+	}
+	mapType, ok := c.info.TypeOf(typeExpr).Underlying().(*types.Map)
+	if !ok {
+		return
+	}
+	key, ok := mapType.Key().Underlying().(*types.Basic)
+	if !ok || key.Info()&types.IsInteger == 0 {
+		return
+	}
+
+	c.add(node.Pos(), fmt.Sprintf("map[%s]%s for integer keys", mapType.Key(), mapType.Elem()),
+		"if the keys are bounded (e.g. 1..n or coordinates after compression), a slice is much faster and smaller")
+}
+
+func (c *hintChecker) isString(expr ast.Expr) bool {
+	t := c.info.TypeOf(expr)
+	if t == nil {
+		return false
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// callsFunction reports whether the file calls pkgPath.name anywhere
+func callsFunction(file *ast.File, info *types.Info, pkgPath, name string) bool {
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == name {
+			if fn, ok := info.Uses[sel.Sel].(*types.Func); ok && fn.Pkg() != nil && fn.Pkg().Path() == pkgPath {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// handleHints prints the performance hints for the solution
+func handleHints(config *Config) error {
+	if err := validateSolutionFile(config); err != nil {
+		return withExitCode(ExitUsageError, err)
+	}
+
+	hints, err := analyzeHints(config.FilePath)
+	if err != nil {
+		return withExitCode(ExitCompileError, err)
+	}
+
+	if len(hints) == 0 {
+		green.Println("✅ No performance hints")
+		return nil
+	}
+
+	yellow.Printf("💡 %d hint(s) for %s:\n", len(hints), config.FilePath)
+	for _, hint := range hints {
+		fmt.Println()
+		cyan.Printf("   line %d: ", hint.Line)
+		fmt.Println(hint.Message)
+		fmt.Printf("   → %s\n", hint.Suggestion)
+	}
+	return nil
+}
@@ -0,0 +1,495 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxStoredOutput caps how much of a failed test's output is kept per run
+const maxStoredOutput = 64 * 1024
+
+// RunRecord is a single persisted run of a solution against a problem
+type RunRecord struct {
+	ID         string       `json:"id"`
+	ProblemID  string       `json:"problem_id"`
+	FilePath   string       `json:"file_path"`
+	SourceHash string       `json:"source_hash"`
+	TestsHash  string       `json:"tests_hash,omitempty"`
+	ConfigHash string       `json:"config_hash,omitempty"`
+	Compiler   string       `json:"compiler,omitempty"`
+	StartedAt  time.Time    `json:"started_at"`
+	Duration   float64      `json:"duration_ms"`
+	TimeLimit  float64      `json:"time_limit_ms,omitempty"`
+	BuildTime  float64      `json:"build_ms,omitempty"`
+	BinarySize int64        `json:"binary_bytes,omitempty"`
+	Total      int          `json:"total"`
+	Passed     int          `json:"passed"`
+	Failed     int          `json:"failed"`
+	Tests      []TestRecord `json:"tests"`
+}
+
+// TestRecord is the stored outcome of one test within a run
+type TestRecord struct {
+	Number         int     `json:"number"`
+	Passed         bool    `json:"passed"`
+	Verdict        Verdict `json:"verdict,omitempty"`
+	Error          string  `json:"error,omitempty"`
+	Duration       float64 `json:"duration_ms"`
+	ExitCode       int     `json:"exit_code"`
+	MemoryUsage    int64   `json:"memory_bytes"`
+	ExpectedOutput string  `json:"expected_output,omitempty"`
+	ActualOutput   string  `json:"actual_output,omitempty"`
+}
+
+// AllPassed reports whether every test in the run passed
+func (r *RunRecord) AllPassed() bool {
+	return r.Total > 0 && r.Failed == 0
+}
+
+// HistoryStore persists run records as JSON files in the cache directory, one
+// per run. Plain files rather than an embedded database keep the runner free
+// of cgo and storage dependencies, and leave the history readable and easy to
+// copy between machines. index.json holds the summary of every run, so that
+// listing the history reads one file instead of every record.
+type HistoryStore struct {
+	dir string
+}
+
+// historyIndexFile is the summary of every run in the history directory
+const historyIndexFile = "index.json"
+
+// NewHistoryStore creates a store rooted at the configured history directory
+func NewHistoryStore(config *Config) *HistoryStore {
+	return &HistoryStore{dir: config.GetHistoryDir()}
+}
+
+// NewRunRecord builds a record from the results of a finished run
+func NewRunRecord(config *Config, startedAt time.Time, results []TestResult) *RunRecord {
+	record := &RunRecord{
+		ID:        fmt.Sprintf("%d-%s", startedAt.UnixNano(), config.ProblemID),
+		ProblemID: config.ProblemID,
+		FilePath:  config.FilePath,
+		Compiler:  config.GetCompiler(),
+		StartedAt: startedAt,
+		Duration:  time.Since(startedAt).Seconds() * 1000,
+		TimeLimit: config.GetTimeout().Seconds() * 1000,
+		Total:     len(results),
+	}
+
+	if abs, err := filepath.Abs(config.FilePath); err == nil {
+		record.FilePath = abs
+	}
+	if config.RecordedPath != "" {
+		record.FilePath = config.RecordedPath
+	}
+
+	if hash, err := hashFile(config.FilePath); err == nil {
+		record.SourceHash = hash
+	}
+	record.ConfigHash = hashVerdictConfig(config)
+
+	for _, result := range results {
+		test := TestRecord{
+			Number:      result.TestNumber,
+			Passed:      result.Passed,
+			Verdict:     result.Verdict,
+			Error:       result.Error,
+			Duration:    result.Duration.Seconds() * 1000,
+			ExitCode:    result.ExitCode,
+			MemoryUsage: result.MemoryUsage,
+		}
+
+		if result.Passed {
+			record.Passed++
+		} else {
+			record.Failed++
+			// Keep outputs of failures so diffs can be shown later
+			test.ExpectedOutput = truncateOutput(result.ExpectedOutput, maxStoredOutput)
+			test.ActualOutput = truncateOutput(result.ActualOutput, maxStoredOutput)
+		}
+
+		record.Tests = append(record.Tests, test)
+	}
+
+	return record
+}
+
+// Save writes a run record to disk
+func (h *HistoryStore) Save(record *RunRecord) error {
+	if err := os.MkdirAll(h.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run record: %w", err)
+	}
+
+	path := filepath.Join(h.dir, record.ID+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run record: %w", err)
+	}
+
+	// Reading the index adds the new record to it
+	_, err = h.List()
+	return err
+}
+
+// Load reads a single run record by ID
+func (h *HistoryStore) Load(id string) (*RunRecord, error) {
+	if strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("invalid run ID %q", id)
+	}
+
+	data, err := os.ReadFile(filepath.Join(h.dir, id+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run %s: %w", id, err)
+	}
+
+	var record RunRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse run %s: %w", id, err)
+	}
+
+	return &record, nil
+}
+
+// List returns the summaries of all stored runs, newest first, without their
+// tests; Load or Find read a whole run. Records missing from the index, saved
+// by an older version or a concurrent run, are read once and added to it, and
+// runs whose record was removed are dropped from it. Unreadable records are
+// skipped.
+func (h *HistoryStore) List() ([]*RunRecord, error) {
+	files, err := os.ReadDir(h.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory: %w", err)
+	}
+
+	indexed := make(map[string]*RunRecord)
+	if data, err := os.ReadFile(filepath.Join(h.dir, historyIndexFile)); err == nil {
+		var summaries []*RunRecord
+		if json.Unmarshal(data, &summaries) == nil {
+			for _, summary := range summaries {
+				indexed[summary.ID] = summary
+			}
+		}
+	}
+
+	var records []*RunRecord
+	stale := false
+	for _, file := range files {
+		if file.IsDir() || file.Name() == historyIndexFile || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		id := strings.TrimSuffix(file.Name(), ".json")
+		summary, ok := indexed[id]
+		if !ok {
+			record, err := h.Load(id)
+			if err != nil {
+				continue
+			}
+			summary = record.summary()
+			stale = true
+		}
+		delete(indexed, id)
+		records = append(records, summary)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartedAt.After(records[j].StartedAt)
+	})
+
+	if stale || len(indexed) > 0 {
+		if err := h.writeIndex(records); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// summary is the record without its tests, as kept in the index
+func (r *RunRecord) summary() *RunRecord {
+	summary := *r
+	summary.Tests = nil
+	return &summary
+}
+
+// writeIndex replaces the index in one step, so a concurrent List never
+// reads half of it
+func (h *HistoryStore) writeIndex(summaries []*RunRecord) error {
+	data, err := json.Marshal(summaries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history index: %w", err)
+	}
+
+	temp, err := os.CreateTemp(h.dir, historyIndexFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write history index: %w", err)
+	}
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), filepath.Join(h.dir, historyIndexFile))
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to write history index: %w", err)
+	}
+	return nil
+}
+
+// Find resolves a run reference: either a full run ID, or a 1-based index
+// into the newest-first list as printed by the history command.
+func (h *HistoryStore) Find(ref string) (*RunRecord, error) {
+	if index, err := strconv.Atoi(ref); err == nil {
+		records, err := h.List()
+		if err != nil {
+			return nil, err
+		}
+		if index < 1 || index > len(records) {
+			return nil, fmt.Errorf("run #%d does not exist (%d runs recorded)", index, len(records))
+		}
+		return h.Load(records[index-1].ID)
+	}
+
+	return h.Load(ref)
+}
+
+// Latest returns the newest run whose summary match accepts, or nil if there
+// is none
+func (h *HistoryStore) Latest(match func(*RunRecord) bool) (*RunRecord, error) {
+	records, err := h.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if match(record) {
+			return h.Load(record.ID)
+		}
+	}
+	return nil, nil
+}
+
+// hashFile returns the hex SHA-256 of a file's contents
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func truncateOutput(output string, max int) string {
+	if len(output) > max {
+		return output[:max] + "..."
+	}
+	return output
+}
+
+// formatBytes renders a byte count for display
+func formatBytes(n int64) string {
+	switch {
+	case n <= 0:
+		return "-"
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", n)
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+// handleHistory implements `history`, `history show <run>` and `history compare <run> <run>`
+func handleHistory(config *Config, args []string) error {
+	store := NewHistoryStore(config)
+
+	if len(args) == 0 || args[0] == "list" {
+		return listHistory(store, config.ProblemID)
+	}
+
+	switch args[0] {
+	case "show":
+		if len(args) != 2 {
+			return withExitCode(ExitUsageError, fmt.Errorf("usage: history show <run>"))
+		}
+		record, err := store.Find(args[1])
+		if err != nil {
+			return err
+		}
+		showRun(record)
+		return nil
+	case "compare":
+		if len(args) != 3 {
+			return withExitCode(ExitUsageError, fmt.Errorf("usage: history compare <run> <run>"))
+		}
+		a, err := store.Find(args[1])
+		if err != nil {
+			return err
+		}
+		b, err := store.Find(args[2])
+		if err != nil {
+			return err
+		}
+		compareRuns(a, b)
+		return nil
+	}
+
+	return withExitCode(ExitUsageError, fmt.Errorf("unknown history subcommand: %s", args[0]))
+}
+
+func listHistory(store *HistoryStore, problemID string) error {
+	records, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		yellow.Println("⚠️  No runs recorded yet")
+		return nil
+	}
+
+	fmt.Printf("%-4s %-19s %-8s %-9s %-10s %-8s %s\n", "#", "STARTED", "PROBLEM", "RESULT", "DURATION", "SOURCE", "ID")
+	for i, record := range records {
+		if problemID != "" && record.ProblemID != problemID {
+			continue
+		}
+
+		verdict := green
+		if !record.AllPassed() {
+			verdict = red
+		}
+
+		fmt.Printf("%-4d %-19s %-8s ", i+1, record.StartedAt.Local().Format("2006-01-02 15:04:05"), record.ProblemID)
+		verdict.Printf("%-9s", fmt.Sprintf("%d/%d", record.Passed, record.Total))
+		fmt.Printf(" %-10s %-8s %s\n", fmt.Sprintf("%.0fms", record.Duration), shortHash(record.SourceHash), record.ID)
+	}
+
+	if problemID != "" {
+		showTimeToSolve(records, problemID)
+	}
+
+	return nil
+}
+
+func showRun(record *RunRecord) {
+	cyan.Printf("📋 Run %s\n", record.ID)
+	fmt.Printf("   Problem:  %s\n", record.ProblemID)
+	fmt.Printf("   Solution: %s (%s)\n", record.FilePath, shortHash(record.SourceHash))
+	if record.Compiler != "" {
+		fmt.Printf("   Compiler: %s\n", record.Compiler)
+	}
+	if record.BinarySize > 0 {
+		fmt.Printf("   Build:    %.2fs, %s binary\n", record.BuildTime/1000, formatBytes(record.BinarySize))
+	}
+	fmt.Printf("   Started:  %s\n", record.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("   Duration: %.2fms\n\n", record.Duration)
+
+	for _, test := range record.Tests {
+		if test.Passed {
+			green.Printf("✅ Test %-3d", test.Number)
+		} else {
+			red.Printf("❌ Test %-3d", test.Number)
+		}
+		fmt.Printf(" %9.2fms %9s", test.Duration, formatBytes(test.MemoryUsage))
+		if test.Error != "" {
+			fmt.Printf("  %s", strings.SplitN(test.Error, "\n", 2)[0])
+		}
+		fmt.Println()
+	}
+}
+
+func compareRuns(a, b *RunRecord) {
+	cyan.Printf("🔀 Comparing %s → %s\n", a.ID, b.ID)
+	if a.ProblemID != b.ProblemID {
+		yellow.Printf("⚠️  Runs are for different problems (%s vs %s)\n", a.ProblemID, b.ProblemID)
+	}
+	if a.SourceHash == b.SourceHash {
+		fmt.Println("   Source unchanged")
+	} else {
+		fmt.Printf("   Source changed: %s → %s\n", shortHash(a.SourceHash), shortHash(b.SourceHash))
+	}
+	if a.Compiler != b.Compiler {
+		fmt.Printf("   Compiler: %s → %s\n", compilerLabel(a.Compiler), compilerLabel(b.Compiler))
+	}
+	if a.BinarySize > 0 && b.BinarySize > 0 && a.BinarySize != b.BinarySize {
+		fmt.Printf("   Binary:   %s → %s (%+.0f%%)\n", formatBytes(a.BinarySize), formatBytes(b.BinarySize),
+			(float64(b.BinarySize)/float64(a.BinarySize)-1)*100)
+	}
+	fmt.Println()
+
+	before := make(map[int]TestRecord)
+	for _, test := range a.Tests {
+		before[test.Number] = test
+	}
+
+	fixed, broken := 0, 0
+	fmt.Printf("%-6s %-8s %-8s %12s %12s %10s\n", "TEST", "BEFORE", "AFTER", "TIME BEFORE", "TIME AFTER", "DELTA")
+	for _, after := range b.Tests {
+		prev, exists := before[after.Number]
+		if !exists {
+			fmt.Printf("%-6d %-8s %-8s %12s %11.2fms %10s\n", after.Number, "-", passLabel(after.Passed), "-", after.Duration, "-")
+			continue
+		}
+
+		line := fmt.Sprintf("%-6d %-8s %-8s %11.2fms %11.2fms %+9.2fms", after.Number,
+			passLabel(prev.Passed), passLabel(after.Passed), prev.Duration, after.Duration, after.Duration-prev.Duration)
+
+		switch {
+		case !prev.Passed && after.Passed:
+			fixed++
+			green.Println(line)
+		case prev.Passed && !after.Passed:
+			broken++
+			red.Println(line)
+		default:
+			fmt.Println(line)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("   %d/%d → %d/%d passed", a.Passed, a.Total, b.Passed, b.Total)
+	if fixed > 0 {
+		green.Printf("  (%d fixed)", fixed)
+	}
+	if broken > 0 {
+		red.Printf("  (%d broken)", broken)
+	}
+	fmt.Println()
+}
+
+func passLabel(passed bool) string {
+	if passed {
+		return "pass"
+	}
+	return "FAIL"
+}
+
+// compilerLabel names the backend of a record; runs saved before -compiler existed used gc
+func compilerLabel(compiler string) string {
+	if compiler == "" {
+		return CompilerGC
+	}
+	return compiler
+}
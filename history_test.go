@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testRecord(id string, startedAt time.Time) *RunRecord {
+	return &RunRecord{
+		ID:        id,
+		ProblemID: "1068",
+		StartedAt: startedAt,
+		Total:     2,
+		Passed:    1,
+		Failed:    1,
+		Tests: []TestRecord{
+			{Number: 1, Passed: true},
+			{Number: 2, Verdict: VerdictWrongAnswer, ActualOutput: "1", ExpectedOutput: "2"},
+		},
+	}
+}
+
+func TestHistoryStoreIndex(t *testing.T) {
+	store := NewHistoryStore(&Config{CacheDir: t.TempDir()})
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, id := range []string{"a", "b", "c"} {
+		if err := store.Save(testRecord(id, start.Add(time.Duration(i)*time.Minute))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(store.dir, historyIndexFile)); err != nil {
+		t.Fatalf("no index written: %v", err)
+	}
+
+	records, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0].ID != "c" || records[2].ID != "a" {
+		t.Fatalf("List() = %v, want c, b, a", recordIDs(records))
+	}
+	if records[0].Tests != nil || records[0].Passed != 1 || records[0].Total != 2 {
+		t.Errorf("summary = %+v, want the counts without the tests", records[0])
+	}
+
+	// Find and Latest read the whole record
+	found, err := store.Find("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.ID != "c" || len(found.Tests) != 2 || found.Tests[1].ActualOutput != "1" {
+		t.Errorf("Find(1) = %+v, want run c with its tests", found)
+	}
+	latest, err := store.Latest(func(record *RunRecord) bool { return record.ID == "b" })
+	if err != nil || latest == nil || len(latest.Tests) != 2 {
+		t.Errorf("Latest() = %+v, %v, want run b with its tests", latest, err)
+	}
+}
+
+func TestHistoryStoreIndexRepairs(t *testing.T) {
+	store := NewHistoryStore(&Config{CacheDir: t.TempDir()})
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.Save(testRecord("a", start)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(testRecord("b", start.Add(time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+
+	// A record the index does not know, as saved by an older version, and a
+	// record removed behind the store's back
+	data, err := json.Marshal(testRecord("c", start.Add(2*time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(store.dir, "c.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(store.dir, "a.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := recordIDs(records); len(got) != 2 || got[0] != "c" || got[1] != "b" {
+		t.Fatalf("List() = %v, want c, b", got)
+	}
+
+	os.Remove(filepath.Join(store.dir, historyIndexFile))
+	if records, err := store.List(); err != nil || len(records) != 2 {
+		t.Fatalf("List() without an index = %d records, %v", len(records), err)
+	}
+}
+
+func recordIDs(records []*RunRecord) []string {
+	var ids []string
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
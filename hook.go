@@ -0,0 +1,391 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// hookMarker identifies hook scripts written by this tool so we never
+// overwrite or remove a user's own hook
+const hookMarker = "# installed by " + AppName
+
+// problemIDPattern matches a standalone 4-digit CSES problem ID in a path
+var problemIDPattern = regexp.MustCompile(`(?:^|[^0-9])([0-9]{4})(?:[^0-9]|$)`)
+
+// handleHook implements `hook install`, `hook uninstall` and `hook run`
+func handleHook(config *Config, args []string, hookType string) error {
+	if hookType != "pre-commit" && hookType != "pre-push" {
+		return withExitCode(ExitUsageError, fmt.Errorf("unsupported hook type %q (use pre-commit or pre-push)", hookType))
+	}
+
+	if len(args) == 0 {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: hook install|uninstall|run [-hook-type=pre-commit|pre-push]"))
+	}
+
+	switch args[0] {
+	case "install":
+		return installHook(config, hookType)
+	case "uninstall":
+		return uninstallHook(hookType)
+	case "run":
+		return runHook(config, hookType)
+	}
+
+	return withExitCode(ExitUsageError, fmt.Errorf("unknown hook subcommand: %s", args[0]))
+}
+
+func gitHookPath(hookType string) (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", fmt.Errorf("not inside a git repository: %w", err)
+	}
+	return filepath.Join(strings.TrimSpace(string(output)), hookType), nil
+}
+
+func installHook(config *Config, hookType string) error {
+	path, err := gitHookPath(hookType)
+	if err != nil {
+		return err
+	}
+
+	if existing, err := os.ReadFile(path); err == nil && !strings.Contains(string(existing), hookMarker) {
+		return fmt.Errorf("%s already exists and was not installed by %s", path, AppName)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate %s executable: %w", AppName, err)
+	}
+
+	script := fmt.Sprintf("#!/bin/sh\n%s\nexec %q hook run -hook-type=%s -cache-dir=%q -config-dir=%q -timeout=%q\n",
+		hookMarker, executable, hookType, config.CacheDir, config.ConfigDir, config.Timeout)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		return fmt.Errorf("failed to write hook: %w", err)
+	}
+
+	green.Printf("✅ Installed %s hook at %s\n", hookType, path)
+	return nil
+}
+
+func uninstallHook(hookType string) error {
+	path, err := gitHookPath(hookType)
+	if err != nil {
+		return err
+	}
+
+	existing, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		yellow.Printf("⚠️  No %s hook installed\n", hookType)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read hook: %w", err)
+	}
+	if !strings.Contains(string(existing), hookMarker) {
+		return fmt.Errorf("%s was not installed by %s, leaving it alone", path, AppName)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove hook: %w", err)
+	}
+
+	green.Printf("✅ Removed %s hook\n", hookType)
+	return nil
+}
+
+// hookTarget is a solution changed by the commit or push and the revision
+// it is tested at: "" for the index (pre-commit) or the commit being pushed
+type hookTarget struct {
+	file string // path in the working tree
+	rev  string
+}
+
+// changedSolutions lists the Go files touched by the commit or push and the
+// root of the repository they are in
+func changedSolutions(hookType string) (string, []hookTarget, error) {
+	topLevel, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to locate repository root: %w", err)
+	}
+	root := strings.TrimSpace(string(topLevel))
+
+	var targets []hookTarget
+	add := func(output []byte, rev string) {
+		for _, name := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			target := hookTarget{file: filepath.Join(root, name), rev: rev}
+			if strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") && !slices.Contains(targets, target) {
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	if hookType == "pre-commit" {
+		output, err := exec.Command("git", "diff", "--cached", "--name-only", "--diff-filter=ACM").Output()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to list changed files: %w", err)
+		}
+		add(output, "")
+		return root, targets, nil
+	}
+
+	// git passes the pushed refs on stdin; without them (hook run by hand)
+	// the branch is compared with its upstream
+	updates := [][2]string{{"@{upstream}", "HEAD"}}
+	if !isTerminal(os.Stdin) {
+		if pushed, err := readPushedRefs(os.Stdin); err == nil && pushed != nil {
+			updates = pushed
+		}
+	}
+	for _, update := range updates {
+		remote, local := update[0], update[1]
+		output, err := exec.Command("git", "diff", "--name-only", "--diff-filter=ACM", remote+"..."+local).Output()
+		if err != nil {
+			// A new branch, or no upstream yet: fall back to the last commit
+			output, err = exec.Command("git", "diff-tree", "-r", "--root", "--no-commit-id", "--name-only", "--diff-filter=ACM", local).Output()
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to list changed files: %w", err)
+		}
+		add(output, local)
+	}
+	return root, targets, nil
+}
+
+// readPushedRefs reads the "<local ref> <local sha> <remote ref> <remote sha>"
+// lines a pre-push hook gets and returns the remote and local sha of every
+// ref that is updated. Deleted refs have nothing to test.
+func readPushedRefs(r io.Reader) ([][2]string, error) {
+	var updates [][2]string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || isZeroSHA(fields[1]) {
+			continue
+		}
+		updates = append(updates, [2]string{fields[3], fields[1]})
+	}
+	return updates, scanner.Err()
+}
+
+// isZeroSHA reports whether sha is git's all-zero object name, which stands
+// for a ref that does not exist
+func isZeroSHA(sha string) bool {
+	return strings.Trim(sha, "0") == ""
+}
+
+// committedTree writes the solution's directory as rev has it (the index
+// when rev is ""), with the go.mod and go.sum of the module it is in, to a
+// temporary directory. The working tree may have changes that are not part
+// of the commit; the copy builds like the solution normally does, with its
+// build-tagged siblings and embedded files. It returns the path of the
+// solution in the copy and a function removing the copy.
+func committedTree(root, rev, file string) (string, func(), error) {
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		return "", nil, err
+	}
+	rel = filepath.ToSlash(rel)
+
+	// The module files of every directory up to the root, where they exist
+	var moduleFiles []string
+	for dir := path.Dir(rel); ; dir = path.Dir(dir) {
+		moduleFiles = append(moduleFiles, path.Join(dir, "go.mod"), path.Join(dir, "go.sum"))
+		if dir == "." {
+			break
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "cses-hook-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	if rev == "" {
+		err = checkoutIndex(root, dir, append([]string{path.Dir(rel)}, moduleFiles...))
+	} else {
+		err = extractRevision(root, dir, rev, path.Dir(rel), moduleFiles)
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return filepath.Join(dir, filepath.FromSlash(rel)), cleanup, nil
+}
+
+// checkoutIndex writes the staged files matching paths below dir
+func checkoutIndex(root, dir string, paths []string) error {
+	list := exec.Command("git", append([]string{"-C", root, "ls-files", "-z", "--"}, paths...)...)
+	staged, err := list.Output()
+	if err != nil {
+		return fmt.Errorf("failed to list staged files: %w", err)
+	}
+
+	checkout := exec.Command("git", "-C", root, "checkout-index", "-z", "--stdin", "--prefix="+dir+string(filepath.Separator))
+	checkout.Stdin = bytes.NewReader(staged)
+	if output, err := checkout.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to check out staged files: %w %s", err, bytes.TrimSpace(output))
+	}
+	return nil
+}
+
+// extractRevision writes solutionDir and those of moduleFiles that exist at
+// rev below dir
+func extractRevision(root, dir, rev, solutionDir string, moduleFiles []string) error {
+	existing, err := exec.Command("git", append([]string{"-C", root, "ls-tree", "-z", "--name-only", rev, "--"}, moduleFiles...)...).Output()
+	if err != nil {
+		return fmt.Errorf("failed to list files of %s: %w", rev, err)
+	}
+	paths := []string{solutionDir}
+	for _, name := range strings.Split(string(existing), "\x00") {
+		if name != "" {
+			paths = append(paths, name)
+		}
+	}
+
+	archive := exec.Command("git", append([]string{"-C", root, "archive", "--format=tar", rev, "--"}, paths...)...)
+	data, err := archive.Output()
+	if err != nil {
+		return fmt.Errorf("failed to read %s from git: %w", rev, err)
+	}
+
+	reader := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s from git: %w", rev, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := checkArchiveEntryName(header.Name); err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, content, header.FileInfo().Mode().Perm()); err != nil {
+			return err
+		}
+	}
+}
+
+// inferProblemID finds a CSES problem ID in a solution path, preferring the
+// file name over the directories that contain it
+func inferProblemID(path string) string {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if matches := problemIDPattern.FindStringSubmatch(parts[i]); matches != nil {
+			return matches[1]
+		}
+	}
+	return ""
+}
+
+// everPassed reports whether the history has an all-pass run for this solution
+func everPassed(records []*RunRecord, problemID, filePath string) bool {
+	for _, record := range records {
+		if record.ProblemID == problemID && record.FilePath == filePath && record.AllPassed() {
+			return true
+		}
+	}
+	return false
+}
+
+// runHook tests every changed solution and fails if one that used to pass no longer does
+func runHook(config *Config, hookType string) error {
+	root, targets, err := changedSolutions(hookType)
+	if err != nil {
+		return err
+	}
+
+	records, err := NewHistoryStore(config).List()
+	if err != nil {
+		return err
+	}
+
+	auth := NewCSESAuth(config)
+
+	var broken []string
+	for _, target := range targets {
+		file := target.file
+		problemID := inferProblemID(file)
+		if problemID == "" {
+			if config.Verbose {
+				yellow.Printf("⚠️  Skipping %s: no problem ID in path\n", file)
+			}
+			continue
+		}
+
+		wasPassing := everPassed(records, problemID, file)
+
+		committed, cleanup, err := committedTree(root, target.rev, file)
+		if err != nil {
+			red.Printf("❌ %s: %v\n", file, err)
+			broken = append(broken, file)
+			continue
+		}
+
+		runConfig := *config
+		runConfig.FilePath = committed
+		runConfig.RecordedPath = file
+		runConfig.ProblemID = problemID
+
+		cyan.Printf("🪝 %s → problem %s\n", file, problemID)
+		results, err := NewTestRunner(&runConfig, auth).Execute(context.Background())
+		cleanup()
+		if err != nil {
+			red.Printf("❌ %s: %v\n", file, err)
+			if wasPassing {
+				broken = append(broken, file)
+			}
+			continue
+		}
+
+		failed := 0
+		for _, result := range results {
+			if !result.Passed {
+				failed++
+			}
+		}
+
+		switch {
+		case failed == 0:
+			green.Printf("✅ %s: %d/%d passed\n", file, len(results), len(results))
+		case wasPassing:
+			red.Printf("❌ %s: %d/%d failed (previously passing)\n", file, failed, len(results))
+			broken = append(broken, file)
+		default:
+			yellow.Printf("⚠️  %s: %d/%d failed (not passing before either)\n", file, failed, len(results))
+		}
+	}
+
+	if len(broken) > 0 {
+		return fmt.Errorf("%d previously passing solution(s) now fail: %s", len(broken), strings.Join(broken, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadPushedRefs(t *testing.T) {
+	zero := strings.Repeat("0", 40)
+	input := "refs/heads/main 1111 refs/heads/main 2222\n" +
+		"refs/heads/new 3333 refs/heads/new " + zero + "\n" +
+		"(delete) " + zero + " refs/heads/old 4444\n"
+
+	updates, err := readPushedRefs(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"2222", "1111"}, {zero, "3333"}}
+	if !slices.Equal(updates, want) {
+		t.Errorf("readPushedRefs() = %q, want %q", updates, want)
+	}
+}
+
+func TestCommittedTree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root, "-c", "user.name=t", "-c", "user.email=t@t"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("go.mod", "module solutions\n")
+	write("1068/main.go", "committed")
+	write("1068/helper.go", "helper")
+	write("1068/testdata/in.txt", "asset")
+	write("1083/main.go", "other problem")
+	git("add", "-A")
+	git("commit", "-q", "-m", "first")
+	write("1068/main.go", "staged")
+	git("add", "1068/main.go")
+	write("1068/main.go", "working tree")
+
+	read := func(dir, name string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		return string(data)
+	}
+	for rev, want := range map[string]string{"": "staged", "HEAD": "committed"} {
+		solution, cleanup, err := committedTree(root, rev, filepath.Join(root, "1068", "main.go"))
+		if err != nil {
+			t.Fatalf("committedTree(%q) = %v", rev, err)
+		}
+		copyRoot := filepath.Dir(filepath.Dir(solution))
+		if got := read(copyRoot, "1068/main.go"); got != want {
+			t.Errorf("committedTree(%q) solution = %q, want %q", rev, got, want)
+		}
+		if read(copyRoot, "1068/helper.go") != "helper" || read(copyRoot, "1068/testdata/in.txt") != "asset" || read(copyRoot, "go.mod") == "" {
+			t.Errorf("committedTree(%q) left out the package's siblings or go.mod", rev)
+		}
+		if read(copyRoot, "1083/main.go") != "" {
+			t.Errorf("committedTree(%q) copied other problems", rev)
+		}
+		cleanup()
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var moduleLinePattern = regexp.MustCompile(`(?m)^module\s+"?([^"\s]+)"?`)
+
+// externalImport is an import of the solution that is not in the standard library
+type externalImport struct {
+	Path   string
+	Line   int
+	Module bool // a package of the solution's own module
+}
+
+// findExternalImports returns the imports that CSES, which compiles the file
+// on its own, cannot resolve. Standard library paths have no dot in their
+// first element; within a module, its own packages have none either.
+func findExternalImports(fset *token.FileSet, file *ast.File, modulePath string) []externalImport {
+	var external []externalImport
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		first, _, _ := strings.Cut(path, "/")
+		inModule := modulePath != "" && (path == modulePath || strings.HasPrefix(path, modulePath+"/"))
+		if !inModule && !strings.Contains(first, ".") && path != "C" {
+			continue
+		}
+		external = append(external, externalImport{Path: path, Line: fset.Position(spec.Pos()).Line, Module: inModule})
+	}
+	return external
+}
+
+// modulePath reads the module path from a go.mod, "" if there is none
+func modulePath(goMod string) string {
+	if goMod == "" {
+		return ""
+	}
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return ""
+	}
+	if match := moduleLinePattern.FindSubmatch(data); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+// warnExternalImports warns about imports from outside the standard library
+// before the build, whose own error for a missing module is less clear and
+// which might succeed locally where CSES will not
+func (c *GoCompiler) warnExternalImports(fset *token.FileSet, file *ast.File) {
+	dir, err := filepath.Abs(filepath.Dir(c.config.FilePath))
+	if err != nil {
+		return
+	}
+	external := findExternalImports(fset, file, modulePath(findGoMod(dir)))
+	if len(external) == 0 {
+		return
+	}
+
+	yellow.Printf("⚠️  %s imports packages outside the standard library, which CSES rejects:\n", filepath.Base(c.config.FilePath))
+	for _, imp := range external {
+		switch {
+		case imp.Path == "C":
+			fmt.Printf("   line %d: C (cgo)\n", imp.Line)
+		case imp.Module:
+			fmt.Printf("   line %d: %s (a package of this module; CSES only gets the one file)\n", imp.Line, imp.Path)
+		default:
+			fmt.Printf("   line %d: %s\n", imp.Line, imp.Path)
+		}
+	}
+}
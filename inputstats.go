@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// maxFirstLine is how much of the first input line the stats show
+const maxFirstLine = 60
+
+// InputStats summarizes a test input without showing it
+type InputStats struct {
+	Size      int64
+	Lines     int
+	Tokens    int
+	FirstLine string
+
+	// Smallest and largest numeric token; Numeric counts how many tokens were numbers
+	Min, Max float64
+	Numeric  int
+}
+
+// readInputStats streams the input once, so it is cheap even for inputs of
+// tens of megabytes
+func readInputStats(path string) (InputStats, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return InputStats{}, fmt.Errorf("failed to open input: %w", err)
+	}
+	defer file.Close()
+
+	var stats InputStats
+	reader := bufio.NewReaderSize(file, 1<<16)
+	var token, firstLine []byte
+	inFirstLine := true
+	var last byte
+
+	flush := func() {
+		if len(token) == 0 {
+			return
+		}
+		stats.Tokens++
+		if value, err := strconv.ParseFloat(string(token), 64); err == nil {
+			if stats.Numeric == 0 || value < stats.Min {
+				stats.Min = value
+			}
+			if stats.Numeric == 0 || value > stats.Max {
+				stats.Max = value
+			}
+			stats.Numeric++
+		}
+		token = token[:0]
+	}
+
+	for {
+		b, err := reader.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return InputStats{}, fmt.Errorf("failed to read input: %w", err)
+		}
+		stats.Size++
+		last = b
+
+		switch b {
+		case '\n':
+			stats.Lines++
+			inFirstLine = false
+			flush()
+		case ' ', '\t', '\r':
+			flush()
+		default:
+			token = append(token, b)
+		}
+
+		if inFirstLine && len(firstLine) <= maxFirstLine {
+			firstLine = append(firstLine, b)
+		}
+	}
+	flush()
+
+	// A last line without a trailing newline still counts
+	if stats.Size > 0 && last != '\n' {
+		stats.Lines++
+	}
+
+	stats.FirstLine = strings.TrimRight(string(firstLine), " \t\r")
+	if len(stats.FirstLine) > maxFirstLine {
+		stats.FirstLine = stats.FirstLine[:maxFirstLine] + "..."
+	}
+	return stats, nil
+}
+
+// String formats the stats as one line, e.g.
+// 1.5MB, 200001 lines, 400001 tokens, first line "200000 5", values 1..1000000000
+func (s InputStats) String() string {
+	parts := []string{
+		formatBytes(s.Size),
+		fmt.Sprintf("%d lines", s.Lines),
+		fmt.Sprintf("%d tokens", s.Tokens),
+		fmt.Sprintf("first line %q", s.FirstLine),
+	}
+	if s.Numeric > 0 {
+		parts = append(parts, fmt.Sprintf("values %s..%s", formatNumber(s.Min), formatNumber(s.Max)))
+	}
+	if s.Numeric < s.Tokens {
+		parts = append(parts, fmt.Sprintf("%d non-numeric tokens", s.Tokens-s.Numeric))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
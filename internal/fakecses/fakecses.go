@@ -0,0 +1,573 @@
+// Package fakecses is an in-memory stand-in for cses.fi. It implements just
+// enough of the site for the runner to log in, download test zips and submit
+// solutions, so network features can be developed and tested offline.
+package fakecses
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TestCase is one input/output pair of a fake problem
+type TestCase struct {
+	Input  string
+	Output string
+}
+
+// Submission is a solution received through the submit form
+type Submission struct {
+	ID       int
+	Username string
+	TaskID   string
+	Language string
+	Option   string
+	FileName string
+	Source   string
+	Verdict  string
+}
+
+type session struct {
+	username  string
+	csrfToken string
+}
+
+// Server serves the fake site. Create it with New and either mount it as an
+// http.Handler or call Start for an httptest server.
+type Server struct {
+	username string
+	password string
+
+	// Verdict is reported for every new submission (default "ACCEPTED")
+	Verdict string
+
+	// Maintenance answers every request with 503 and a maintenance notice
+	Maintenance bool
+
+	mu          sync.Mutex
+	sessions    map[string]*session
+	problems    map[string][]TestCase
+	submissions []Submission
+
+	mux *http.ServeMux
+}
+
+// New creates a fake CSES that accepts a single user
+func New(username, password string) *Server {
+	s := &Server{
+		username: username,
+		password: password,
+		Verdict:  "ACCEPTED",
+		sessions: make(map[string]*session),
+		problems: make(map[string][]TestCase),
+		mux:      http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("/", s.handleHome)
+	s.mux.HandleFunc("/login", s.handleLogin)
+	s.mux.HandleFunc("/logout", s.handleLogout)
+	s.mux.HandleFunc("/problemset/", s.handleProblemset)
+	s.mux.HandleFunc("/problemset/stats", s.handleStats)
+	s.mux.HandleFunc("/problemset/stats/", s.handleProblemStats)
+	s.mux.HandleFunc("/problemset/task/", s.handleTask)
+	s.mux.HandleFunc("/problemset/tests/", s.handleTests)
+	s.mux.HandleFunc("/problemset/submit/", s.handleSubmitPage)
+	s.mux.HandleFunc("/course/send.php", s.handleSend)
+	s.mux.HandleFunc("/problemset/result/", s.handleResult)
+
+	return s
+}
+
+// Start serves the fake on a random local port; close the returned server when done
+func (s *Server) Start() *httptest.Server {
+	return httptest.NewServer(s)
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if s.Maintenance {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, "<!DOCTYPE html><html><body><p>CSES is down for maintenance.</p></body></html>")
+		return
+	}
+	s.mux.ServeHTTP(w, req)
+}
+
+// AddProblem registers the tests served for a problem ID
+func (s *Server) AddProblem(id string, tests []TestCase) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.problems[id] = tests
+}
+
+// LoadProblems registers every <dir>/<id>/N.in + N.out pair, the layout of the runner's cache
+func (s *Server) LoadProblems(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read problem directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+
+		inputs, _ := filepath.Glob(filepath.Join(dir, entry.Name(), "*.in"))
+		var tests []TestCase
+		for _, inputPath := range inputs {
+			input, err := os.ReadFile(inputPath)
+			if err != nil {
+				continue
+			}
+			output, err := os.ReadFile(strings.TrimSuffix(inputPath, ".in") + ".out")
+			if err != nil {
+				continue
+			}
+			tests = append(tests, TestCase{Input: string(input), Output: string(output)})
+		}
+
+		if len(tests) > 0 {
+			s.AddProblem(entry.Name(), tests)
+		}
+	}
+
+	return nil
+}
+
+// Submissions returns a copy of everything submitted so far
+func (s *Server) Submissions() []Submission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Submission(nil), s.submissions...)
+}
+
+// currentSession returns the caller's session, creating an anonymous one if needed
+func (s *Server) currentSession(w http.ResponseWriter, req *http.Request) *session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cookie, err := req.Cookie("PHPSESSID"); err == nil {
+		if sess, exists := s.sessions[cookie.Value]; exists {
+			return sess
+		}
+	}
+
+	id := randomHex(16)
+	sess := &session{}
+	s.sessions[id] = sess
+	http.SetCookie(w, &http.Cookie{Name: "PHPSESSID", Value: id, Path: "/"})
+	return sess
+}
+
+// rotateToken issues a new CSRF token, invalidating the previous one like CSES does
+func (s *Server) rotateToken(sess *session) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess.csrfToken = randomHex(16)
+	return sess.csrfToken
+}
+
+// checkToken reports whether the posted token is the session's current one
+func (s *Server) checkToken(sess *session, token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return token != "" && token == sess.csrfToken
+}
+
+func (s *Server) loggedIn(sess *session) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sess.username
+}
+
+func (s *Server) page(w http.ResponseWriter, sess *session, body string) {
+	header := `<a href="/login">Login</a>`
+	if user := s.loggedIn(sess); user != "" {
+		header = fmt.Sprintf(`<a class="account" href="/user/1">%s</a> <a href="/logout">Log out</a>`, html.EscapeString(user))
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, "<!DOCTYPE html><html><body><div class=\"header\">%s</div>%s</body></html>", header, body)
+}
+
+func (s *Server) form(action, token, fields string) string {
+	return fmt.Sprintf(`<form method="post" action="%s" enctype="multipart/form-data">`+
+		`<input type="hidden" name="csrf_token" value="%s">%s</form>`, action, token, fields)
+}
+
+func (s *Server) handleHome(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/" {
+		http.NotFound(w, req)
+		return
+	}
+	s.page(w, s.currentSession(w, req), "<h1>CSES</h1>")
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, req *http.Request) {
+	sess := s.currentSession(w, req)
+
+	if req.Method == http.MethodPost {
+		req.ParseForm()
+		if !s.checkToken(sess, req.PostFormValue("csrf_token")) {
+			http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+			return
+		}
+		if req.PostFormValue("nick") == s.username && req.PostFormValue("pass") == s.password {
+			s.mu.Lock()
+			sess.username = s.username
+			s.mu.Unlock()
+			http.Redirect(w, req, "/", http.StatusFound)
+			return
+		}
+		token := s.rotateToken(sess)
+		s.page(w, sess, "<p>Invalid username or password</p>"+s.form("/login", token, `<input name="nick"><input name="pass">`))
+		return
+	}
+
+	token := s.rotateToken(sess)
+	s.page(w, sess, s.form("/login", token, `<input name="nick"><input name="pass">`))
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, req *http.Request) {
+	sess := s.currentSession(w, req)
+	s.mu.Lock()
+	sess.username = ""
+	s.mu.Unlock()
+	http.Redirect(w, req, "/", http.StatusFound)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, req *http.Request) {
+	sess := s.currentSession(w, req)
+	if s.loggedIn(sess) == "" {
+		s.page(w, sess, "<p>Please login to see the statistics</p>")
+		return
+	}
+	s.page(w, sess, "<h1>Statistics</h1>")
+}
+
+// handleProblemStats renders a problem's statistics like CSES: general counts,
+// then the fastest accepted solutions (every fake run takes 0.01 s)
+func (s *Server) handleProblemStats(w http.ResponseWriter, req *http.Request) {
+	sess := s.currentSession(w, req)
+	if s.loggedIn(sess) == "" {
+		s.page(w, sess, "<p>Please login to see the statistics</p>")
+		return
+	}
+	id := strings.Trim(strings.TrimPrefix(req.URL.Path, "/problemset/stats/"), "/")
+
+	s.mu.Lock()
+	var accepted []Submission
+	total := 0
+	for _, submission := range s.submissions {
+		if submission.TaskID != id {
+			continue
+		}
+		total++
+		if submission.Verdict == "ACCEPTED" {
+			accepted = append(accepted, submission)
+		}
+	}
+	s.mu.Unlock()
+
+	var body strings.Builder
+	fmt.Fprintf(&body, `<h1>Statistics</h1><h4>General</h4><table class="narrow">`+
+		`<tr><td>Total submissions</td><td>%d</td></tr><tr><td>Accepted submissions</td><td>%d</td></tr></table>`, total, len(accepted))
+	body.WriteString(`<h4>Fastest solutions</h4><table class="narrow"><tr><th>#</th><th>User</th><th>Time</th><th>Language</th></tr>`)
+	for i, submission := range accepted {
+		fmt.Fprintf(&body, `<tr><td>%d</td><td><a href="/user/1">%s</a></td><td>0.01 s</td><td>%s</td></tr>`,
+			i+1, html.EscapeString(submission.Username), html.EscapeString(submission.Language))
+	}
+	body.WriteString(`</table>`)
+
+	s.page(w, sess, body.String())
+}
+
+// handleProblemset renders the task list like CSES: one category holding every
+// problem, with the user's score icons
+func (s *Server) handleProblemset(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/problemset/" {
+		http.NotFound(w, req)
+		return
+	}
+	sess := s.currentSession(w, req)
+	user := s.loggedIn(sess)
+
+	// full marks accepted tasks, zero ones with only rejected submissions
+	s.mu.Lock()
+	scores := make(map[string]string)
+	for _, submission := range s.submissions {
+		if user == "" || submission.Username != user {
+			continue
+		}
+		if submission.Verdict == "ACCEPTED" {
+			scores[submission.TaskID] = "full"
+		} else if scores[submission.TaskID] == "" {
+			scores[submission.TaskID] = "zero"
+		}
+	}
+	s.mu.Unlock()
+
+	var list strings.Builder
+	list.WriteString(`<h2>Fake Problems</h2><ul class="task-list">`)
+	for _, id := range s.ProblemIDs() {
+		score := ""
+		if icon := scores[id]; icon != "" {
+			score = `<span class="task-score icon ` + icon + `"></span>`
+		}
+		fmt.Fprintf(&list, `<li class="task"><a href="/problemset/task/%s">Problem %s</a><span class="detail">0 / 0</span>%s</li>`, id, id, score)
+	}
+	list.WriteString(`</ul>`)
+
+	s.page(w, sess, list.String())
+}
+
+// handleTask renders a task page with the constraints block of CSES; every
+// fake problem has the usual 1 second and 512 MB limits
+func (s *Server) handleTask(w http.ResponseWriter, req *http.Request) {
+	sess := s.currentSession(w, req)
+	id := strings.Trim(strings.TrimPrefix(req.URL.Path, "/problemset/task/"), "/")
+	s.mu.Lock()
+	_, exists := s.problems[id]
+	s.mu.Unlock()
+	if !exists {
+		http.NotFound(w, req)
+		return
+	}
+
+	s.page(w, sess, fmt.Sprintf(`<h1>Problem %s</h1><ul class="task-constraints">`+
+		`<li><b>Time limit:</b> 1.00 s</li><li><b>Memory limit:</b> 512 MB</li></ul>`, html.EscapeString(id)))
+}
+
+// requireLogin redirects anonymous sessions to the login page like CSES does
+func (s *Server) requireLogin(w http.ResponseWriter, req *http.Request) (*session, bool) {
+	sess := s.currentSession(w, req)
+	if s.loggedIn(sess) == "" {
+		http.Redirect(w, req, "/login", http.StatusFound)
+		return nil, false
+	}
+	return sess, true
+}
+
+func (s *Server) handleTests(w http.ResponseWriter, req *http.Request) {
+	sess, ok := s.requireLogin(w, req)
+	if !ok {
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(req.URL.Path, "/problemset/tests/"), "/")
+	s.mu.Lock()
+	tests, exists := s.problems[id]
+	s.mu.Unlock()
+	if !exists {
+		http.NotFound(w, req)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		token := s.rotateToken(sess)
+		s.page(w, sess, s.form(req.URL.Path, token, `<input type="submit" name="download" value="Download">`))
+		return
+	}
+
+	req.ParseForm()
+	if !s.checkToken(sess, req.PostFormValue("csrf_token")) {
+		http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+		return
+	}
+
+	// The archive is built the same way every time, so its hash is a stable ETag
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for i, test := range tests {
+		for _, entry := range [][2]string{
+			{fmt.Sprintf("%d.in", i+1), test.Input},
+			{fmt.Sprintf("%d.out", i+1), test.Output},
+		} {
+			file, err := archive.Create(entry[0])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			io.WriteString(file, entry[1])
+		}
+	}
+	if err := archive.Close(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	if req.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tests_%s.zip"`, id))
+	w.Write(buf.Bytes())
+}
+
+func (s *Server) handleSubmitPage(w http.ResponseWriter, req *http.Request) {
+	sess, ok := s.requireLogin(w, req)
+	if !ok {
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(req.URL.Path, "/problemset/submit/"), "/")
+	token := s.rotateToken(sess)
+	s.page(w, sess, s.form("/course/send.php", token, fmt.Sprintf(
+		`<input type="hidden" name="task" value="%s">`+
+			`<select name="lang"><option value="C++">C++</option><option value="Go" selected>Go</option><option value="Python3">Python3</option></select>`+
+			`<select name="option"><option value="C++11">C++11</option><option value="C++17">C++17</option><option value="C++20">C++20</option>`+
+			`<option value="CPython3">CPython3</option><option value="PyPy3">PyPy3</option></select>`+
+			`<input type="file" name="file">`, html.EscapeString(id))))
+}
+
+func (s *Server) handleSend(w http.ResponseWriter, req *http.Request) {
+	sess, ok := s.requireLogin(w, req)
+	if !ok {
+		return
+	}
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := req.ParseMultipartForm(16 << 20); err != nil {
+		http.Error(w, "invalid submission form", http.StatusBadRequest)
+		return
+	}
+	if !s.checkToken(sess, req.FormValue("csrf_token")) {
+		http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+		return
+	}
+
+	file, header, err := req.FormFile("file")
+	if err != nil {
+		http.Error(w, "missing file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	source, _ := io.ReadAll(file)
+
+	s.mu.Lock()
+	submission := Submission{
+		ID:       len(s.submissions) + 1,
+		Username: sess.username,
+		TaskID:   req.FormValue("task"),
+		Language: req.FormValue("lang"),
+		Option:   req.FormValue("option"),
+		FileName: header.Filename,
+		Source:   string(source),
+		Verdict:  s.Verdict,
+	}
+	s.submissions = append(s.submissions, submission)
+	s.mu.Unlock()
+
+	http.Redirect(w, req, fmt.Sprintf("/problemset/result/%d/", submission.ID), http.StatusFound)
+}
+
+func (s *Server) handleResult(w http.ResponseWriter, req *http.Request) {
+	sess, ok := s.requireLogin(w, req)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(req.URL.Path, "/problemset/result/"), "/"))
+	s.mu.Lock()
+	var submission *Submission
+	if err == nil && id >= 1 && id <= len(s.submissions) {
+		submission = &s.submissions[id-1]
+	}
+	s.mu.Unlock()
+	if submission == nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	body := fmt.Sprintf(`<table class="summary-table">`+
+		`<tr><td>Task:</td><td>%s</td></tr>`+
+		`<tr><td>Status:</td><td id="status">READY</td></tr>`+
+		`<tr><td>Result:</td><td><span class="verdict">%s</span></td></tr></table>`,
+		html.EscapeString(submission.TaskID), html.EscapeString(submission.Verdict))
+	s.page(w, sess, body+s.testDetails(submission))
+}
+
+// shownTestSize is how much of a test's data a result page shows before
+// cutting it short with "..."
+const shownTestSize = 1000
+
+// testDetails renders the test results of a rejected submission: every test
+// passes up to the last one, which fails with the submission's verdict and
+// shows its data
+func (s *Server) testDetails(submission *Submission) string {
+	s.mu.Lock()
+	tests := s.problems[submission.TaskID]
+	s.mu.Unlock()
+	if submission.Verdict == "ACCEPTED" || len(tests) == 0 {
+		return ""
+	}
+
+	shown := func(data string) string {
+		if len(data) > shownTestSize {
+			data = data[:shownTestSize] + "..."
+		}
+		return html.EscapeString(data)
+	}
+
+	var body strings.Builder
+	for i, test := range tests {
+		verdict := "ACCEPTED"
+		if i == len(tests)-1 {
+			verdict = submission.Verdict
+		}
+		fmt.Fprintf(&body, `<h4 id="test%d">Test %d</h4><p>Verdict: <span class="verdict">%s</span></p>`, i+1, i+1, html.EscapeString(verdict))
+		if i == len(tests)-1 {
+			fmt.Fprintf(&body, `<p>input</p><pre>%s</pre><p>correct output</p><pre>%s</pre>`, shown(test.Input), shown(test.Output))
+		}
+	}
+	return body.String()
+}
+
+// ProblemIDs lists the registered problems in numeric order
+func (s *Server) ProblemIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.problems))
+	for id := range s.problems {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.Atoi(ids[i])
+		b, _ := strconv.Atoi(ids[j])
+		return a < b
+	})
+	return ids
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
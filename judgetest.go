@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	judgeTestHeaderPattern  = regexp.MustCompile(`<h4 id="test([0-9]+)">`)
+	judgeTestVerdictPattern = regexp.MustCompile(`<span class="verdict[^"]*">([^<]*)</span>`)
+	judgeTestInputPattern   = regexp.MustCompile(`(?s)<p>input</p>\s*<pre[^>]*>(.*?)</pre>`)
+	judgeTestOutputPattern  = regexp.MustCompile(`(?s)<p>correct output</p>\s*<pre[^>]*>(.*?)</pre>`)
+)
+
+// JudgeTest is a test CSES shows on a submission's result page
+type JudgeTest struct {
+	Number   int
+	Verdict  string
+	Input    string
+	Expected string
+}
+
+// parseFailingJudgeTest returns the first failing test of a result page whose
+// input and correct output are shown in full. CSES cuts the data of large
+// tests short with "...", and such a test cannot be replayed.
+func parseFailingJudgeTest(page string) (*JudgeTest, bool) {
+	headers := judgeTestHeaderPattern.FindAllStringSubmatchIndex(page, -1)
+	for i, header := range headers {
+		end := len(page)
+		if i+1 < len(headers) {
+			end = headers[i+1][0]
+		}
+		block := page[header[1]:end]
+
+		verdict := judgeTestVerdictPattern.FindStringSubmatch(block)
+		if verdict == nil || strings.EqualFold(strings.TrimSpace(verdict[1]), "ACCEPTED") {
+			continue
+		}
+		input := judgeTestInputPattern.FindStringSubmatch(block)
+		output := judgeTestOutputPattern.FindStringSubmatch(block)
+		if input == nil || output == nil {
+			continue
+		}
+
+		test := &JudgeTest{
+			Verdict:  strings.TrimSpace(verdict[1]),
+			Input:    samplePreText(input[1]),
+			Expected: samplePreText(output[1]),
+		}
+		fmt.Sscan(page[header[2]:header[3]], &test.Number)
+		if strings.HasSuffix(test.Input, "...\n") || strings.HasSuffix(test.Expected, "...\n") {
+			return nil, false
+		}
+		return test, true
+	}
+	return nil, false
+}
+
+// importJudgeTest adds a judge's test to the cached tests of the problem,
+// numbered after the last one, and returns its local number. A test whose
+// input is cached already is not added again; exists is then true. Tests
+// that were never downloaded are downloaded first, or the imported test would
+// pass for the whole suite.
+func importJudgeTest(config *Config, auth *CSESAuth, test *JudgeTest) (number int, exists bool, err error) {
+	cacheDir := filepath.Join(config.CacheDir, config.ProblemID)
+	fetcher := NewTestCaseFetcher(config, auth)
+
+	cached, err := fetcher.loadCachedTestCases(cacheDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, false, err
+	}
+	if len(cached) == 0 {
+		if cached, err = fetcher.FetchTestCases(config.ProblemID); err != nil {
+			return 0, false, err
+		}
+	}
+	inputHash := sha256.Sum256([]byte(test.Input))
+	for _, testCase := range cached {
+		cachedHash, _, _, _, err := hashTestCase(testCase)
+		if err != nil {
+			return 0, false, err
+		}
+		if cachedHash == inputHash {
+			return testCase.Number, true, nil
+		}
+		number = max(number, testCase.Number)
+	}
+
+	number++
+	if err := fetcher.cacheTestCases(cacheDir, []TestCase{{Number: number, Input: test.Input, Expected: test.Expected}}); err != nil {
+		return 0, false, err
+	}
+	return number, false, nil
+}
+
+// replayJudgeTest imports the failing test of a rejected submission and offers
+// to run the solution on it. Nothing happens when CSES does not show the test.
+func replayJudgeTest(config *Config, auth *CSESAuth, resultURL string) {
+	page, err := auth.FetchSubmissionResult(resultURL)
+	if err != nil {
+		return
+	}
+	test, ok := parseFailingJudgeTest(page)
+	if !ok {
+		return
+	}
+
+	number, exists, err := importJudgeTest(config, auth, test)
+	if err != nil {
+		yellow.Printf("⚠️  Failed to import the failing test: %v\n", err)
+		return
+	}
+	if exists {
+		cyan.Printf("🔎 Judge test %d (%s) is local test %d\n", test.Number, strings.ToLower(test.Verdict), number)
+	} else {
+		green.Printf("📥 Imported judge test %d (%s) as local test %d\n", test.Number, strings.ToLower(test.Verdict), number)
+	}
+
+	if !isTerminal(os.Stdin) {
+		fmt.Printf("   Run it with: %s -file=%s -problem=%s -test=%d\n", AppName, config.FilePath, config.ProblemID, number)
+		return
+	}
+	fmt.Print("   Run it locally now? [Y/n] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "" && answer != "y" && answer != "yes" {
+		return
+	}
+
+	fmt.Println()
+	config.Test = number
+	if err := NewTestRunner(config, auth).Run(); err != nil && !errors.Is(err, ErrTestsFailed) {
+		red.Printf("❌ %v\n", err)
+	}
+}
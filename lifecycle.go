@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lifecycle events a hooks file can attach scripts to
+const (
+	EventPreCompile = "pre-compile"
+	EventPostRun    = "post-run"
+	EventOnFailure  = "on-failure"
+	EventOnAllPass  = "on-all-pass"
+)
+
+// LifecycleHooks is a hooks.yaml file mapping events to shell commands:
+//
+//	pre-compile:
+//	  - gofmt -l . | (! grep .)
+//	on-failure:
+//	  - ./scripts/log-failure.sh
+//	on-all-pass:
+//	  - ./scripts/submit.sh
+//
+// Every command gets a HookContext as JSON on stdin. A failing pre-compile
+// command aborts the run; failures of the other events are only reported.
+type LifecycleHooks struct {
+	PreCompile []string `yaml:"pre-compile"`
+	PostRun    []string `yaml:"post-run"`
+	OnFailure  []string `yaml:"on-failure"`
+	OnAllPass  []string `yaml:"on-all-pass"`
+}
+
+// HookContext is what a lifecycle hook receives on stdin
+type HookContext struct {
+	Event     string     `json:"event"`
+	ProblemID string     `json:"problem_id"`
+	FilePath  string     `json:"file_path"`
+	Error     string     `json:"error,omitempty"`
+	Run       *RunRecord `json:"run,omitempty"`
+}
+
+// LoadLifecycleHooks reads the hooks file of config. A missing file at the
+// default location means no hooks; a missing -hooks file is an error.
+func LoadLifecycleHooks(config *Config) (*LifecycleHooks, error) {
+	path := config.GetHooksPath()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && config.HooksFile == "" {
+		return &LifecycleHooks{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hooks file: %w", err)
+	}
+
+	var hooks LifecycleHooks
+	if err := yaml.Unmarshal(data, &hooks); err != nil {
+		return nil, fmt.Errorf("failed to parse hooks file %s: %w", path, err)
+	}
+	return &hooks, nil
+}
+
+// commands returns the commands attached to event
+func (h *LifecycleHooks) commands(event string) []string {
+	switch event {
+	case EventPreCompile:
+		return h.PreCompile
+	case EventPostRun:
+		return h.PostRun
+	case EventOnFailure:
+		return h.OnFailure
+	case EventOnAllPass:
+		return h.OnAllPass
+	}
+	return nil
+}
+
+// Fire runs the commands of event in order with context on stdin, stopping at
+// the first one that fails
+func (h *LifecycleHooks) Fire(context HookContext) error {
+	commands := h.commands(context.Event)
+	if len(commands) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(context)
+	if err != nil {
+		return err
+	}
+
+	for _, command := range commands {
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/C", command)
+		} else {
+			cmd = exec.Command("sh", "-c", command)
+		}
+		cmd.Stdin = bytes.NewReader(payload)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Env = append(os.Environ(), "CSES_HOOK_EVENT="+context.Event, "CSES_PROBLEM_ID="+context.ProblemID)
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", context.Event, command, err)
+		}
+	}
+	return nil
+}
+
+// fireHooks runs the hooks of event for the runner's solution, warning about
+// (rather than returning) failures
+func (r *TestRunner) fireHooks(event string, runErr error) {
+	if r.hooks == nil {
+		return
+	}
+
+	context := HookContext{Event: event, ProblemID: r.config.ProblemID, FilePath: r.config.FilePath, Run: r.lastRun}
+	if runErr != nil {
+		context.Error = runErr.Error()
+	}
+	if err := r.hooks.Fire(context); err != nil {
+		yellow.Printf("⚠️  %v\n", err)
+	}
+}
+
+// fireRunHooks runs post-run and then on-failure or on-all-pass for a finished run
+func (r *TestRunner) fireRunHooks(results []TestResult, runErr error) {
+	r.fireHooks(EventPostRun, runErr)
+
+	passed := runErr == nil
+	for _, result := range results {
+		if !result.Passed {
+			passed = false
+		}
+	}
+	if passed {
+		r.fireHooks(EventOnAllPass, nil)
+	} else {
+		r.fireHooks(EventOnFailure, runErr)
+	}
+}
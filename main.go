@@ -1,13 +1,13 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
-	"os/user"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 )
@@ -34,18 +34,60 @@ func printUsage() {
 	fmt.Println("Commands:")
 	fmt.Println("  run    - Run tests for a solution (default)")
 	fmt.Println("  auth   - Authenticate with CSES using environment variables")
-	fmt.Println("  clean  - Clean cache directory")
+	fmt.Println("  auth switch <name> | auth whoami - Use another named CSES account, or show the current one")
+	fmt.Println("  exec   - Compile and run the solution once on stdin or -input")
+	fmt.Println("  submit [-if-pass [-force] | -require-local-pass] [-submit-lang=L] [-dry-run] - Submit the solution to CSES and wait for the verdict")
+	fmt.Println("  alias add <name> <problem> | list | remove <name> - Friendly problem names usable wherever a problem ID is")
+	fmt.Println("  notes edit|show <problem> - Study notes of a problem (approach, complexity, pitfalls)")
+	fmt.Println("  cache info - Show the cache and config directories, cached tests and recent evictions")
+	fmt.Println("  clean [cache | config] - Remove the cache (default) or the config directory")
+	fmt.Println("  serve  - Serve the local web dashboard (with -web) or an offline fake CSES (with -fake-cses)")
+	fmt.Println("  history [show <run> | compare <run> <run>] - List and inspect past runs")
+	fmt.Println("  hook install|uninstall|run - Manage a git hook that re-tests changed solutions")
+	fmt.Println("  status [problem] - Time from the first run of each problem to its first all-pass run")
+	fmt.Println("  stats [-problem=<id>] - Charts from the history, or a problem's CSES statistics vs your runtime")
+	fmt.Println("  session [start | end] - Scoreboard of the problems attempted in a practice session (or today)")
+	fmt.Println("  practice [-problems=... -duration=... | end] - Virtual contest with a countdown and penalty standings")
+	fmt.Println("  new    - Create <problem>.go (or -file) from a template")
+	fmt.Println("  template list | add <name> <file> | use <name> - Manage solution templates")
+	fmt.Println("  suggest [category] - Pick an unsolved problem to practice next")
+	fmt.Println("  optimize-io [fix] - Find unbuffered fmt.Scan/Print I/O; fix rewrites it to use bufio")
+	fmt.Println("  hints  - Point out common performance pitfalls in the solution")
+	fmt.Println("  archive <problem> - Copy the accepted solution to -archive-dir with its verdict and timings")
+	fmt.Println("  open [-submissions] <problem> - Open a problem (by ID or name) on CSES in the browser")
+	fmt.Println("  update [-check] - Install the latest release of the runner (checksum verified)")
+	fmt.Println("  share  - Upload the solution and its latest results to a secret GitHub Gist (GITHUB_TOKEN)")
+	fmt.Println("  tests list - Show the cached tests of -problem, marking repeated inputs")
+	fmt.Println("  tests import-samples -url=<problem-url> - Import the samples of a Codeforces or AtCoder problem")
+	fmt.Println("  stress - Compare the solution with -brute on random inputs from -gen or -gen-spec")
+	fmt.Println("  asm [function pattern] - Show the assembly of the solution's functions, marking bounds checks")
+	fmt.Println("  bench -go-versions=1.22,1.23 - Time every test with the solution built by each Go version")
+	fmt.Println()
+	fmt.Println("Editor integration:")
+	fmt.Printf("  %s -stdio  - Serve JSON-RPC (run, cancel, version, shutdown) on stdin/stdout\n", AppName)
 	fmt.Println()
 	fmt.Println("Flags:")
 	flag.PrintDefaults()
+	fmt.Println("\nExit Codes:")
+	fmt.Println("  0 - All tests passed")
+	fmt.Println("  1 - Some tests failed")
+	fmt.Println("  2 - Compile error")
+	fmt.Println("  3 - Authentication or test case fetch error")
+	fmt.Println("  4 - Usage error")
 	fmt.Println("\nEnvironment Variables:")
 	fmt.Println("  CSES_USERNAME - Your CSES username")
 	fmt.Println("  CSES_PASSWORD - Your CSES password")
+	fmt.Println("  CSES_NOTIFY_WEBHOOK - Default for -notify-webhook")
+	fmt.Println("  GITHUB_TOKEN - Token with the gist scope, for share")
+	fmt.Println("  Variables missing from the shell are read from ./.env (or -dotenv)")
 	fmt.Println("\nExamples:")
 	fmt.Printf("  %s auth\n", AppName)
 	fmt.Printf("  %s -file=solution.go -problem=1068\n", AppName)
 	fmt.Printf("  %s run -file=solution.go -problem=1068 -timeout=5s -verbose\n", AppName)
+	fmt.Printf("  %s -problem=1068,1083 -file='solutions/*.go'\n", AppName)
 	fmt.Printf("  %s clean\n", AppName)
+	fmt.Printf("  %s serve -web -addr=127.0.0.1:8080\n", AppName)
+	fmt.Printf("  %s serve -fake-cses -addr=127.0.0.1:8081\n", AppName)
 }
 
 func main() {
@@ -54,17 +96,100 @@ func main() {
 		problemID = flag.String("problem", "", "CSES problem ID")
 		timeout   = flag.String("timeout", "1s", "Timeout for each test case (default: 2s)")
 		verbose   = flag.Bool("verbose", false, "Enable verbose output")
-		cacheDir  = flag.String("cache-dir", "~/.cache/cses-go-runner", "Directory to cache test cases")
-		parallel  = flag.Int("parallel", 4, "Number of parallel test executions")
+		cacheDir  = flag.String("cache-dir", "", "Directory for test cases, binaries and sessions (default: $XDG_CACHE_HOME/cses-go-runner)")
+		cacheMax  = flag.String("cache-max-size", "", "Evict the least recently used tests when cached tests exceed this size (e.g. 2GB)")
+		refresh   = flag.Bool("refresh-tests", false, "Check CSES for changed tests; unchanged archives are not downloaded again")
+		ioInput   = flag.String("io-input", "", "Name of the file the solution reads its input from, instead of stdin (e.g. input.txt)")
+		ioOutput  = flag.String("io-output", "", "Name of the file the solution writes its output to, instead of stdout (e.g. output.txt)")
+		showNotes = flag.Bool("show-notes", false, "Show the problem's notes (see notes edit) at the top of the run output")
+		configDir = flag.String("config-dir", "", "Directory for templates and hooks (default: $XDG_CONFIG_HOME/cses-go-runner)")
+		parallel  = flag.Int("parallel", 0, "Number of parallel test executions (0: auto from CPU count and memory)")
 		help      = flag.Bool("help", false, "Show help message")
 		version   = flag.Bool("version", false, "Show version")
 		showDiff  = flag.Bool("diff", false, "Show diff for failed test cases")
 		maxOutput = flag.Int("max-output", 1000, "Maximum output length to display")
+		summary   = flag.String("summary", SummaryTable, "Result summary style: table, compact or failures-only")
+		htmlOut   = flag.String("report-html", "", "Write a standalone HTML report of the run to this file")
+		saveFail  = flag.Bool("save-failed", false, "Write input/expected/actual of failing tests to -failed-dir")
+		failedDir = flag.String("failed-dir", "failed", "Directory for -save-failed artifacts (<dir>/<problem>/<test>/)")
+		mdOut     = flag.String("report-md", "", "Write a GitHub-flavored Markdown summary of the run to this file")
 		optimize  = flag.Bool("optimize", true, "Enable compiler optimizations")
-		race      = flag.Bool("race", false, "Enable race detector")
+		race      = flag.Bool("race", false, "Run a separate race detector pass after the timed tests")
+		raceTests = flag.Int("race-tests", 3, "Number of tests, smallest first, the -race pass runs (0 runs all)")
+		checks    = flag.String("checks", "", "Build the solution with a sanitizer, asan or msan, for cgo and unsafe code")
 		forceAuth = flag.Bool("force-auth", false, "Force re-authentication")
+		repeat    = flag.Int("repeat", 1, "Run every test N times to detect nondeterministic solutions")
+		testNum   = flag.Int("test", 0, "Run only this test number")
+		inputFile = flag.String("input", "", "Input file for the exec command (default: stdin)")
+		stream    = flag.Bool("stream", false, "With -test, show the solution's stdout/stderr live while still comparing the output")
+		attach    = flag.Bool("attach", false, "With -test, stream the solution's stdout/stderr live instead of comparing output")
+		order     = flag.String("order", OrderSequential, "Test execution order: sequential, shuffle, slowest-first or failed-first")
+		seed      = flag.Int64("shuffle-seed", 0, "Seed for -order=shuffle (default: random)")
+		cachedRes = flag.Bool("cached-results", false, "Replay the verdicts of the last run instead of running again if neither the source nor the tests changed")
+		stdio     = flag.Bool("stdio", false, "Serve newline-delimited JSON-RPC on stdin/stdout for editor integrations")
+		web       = flag.Bool("web", false, "Serve the local web dashboard (serve command)")
+		addr      = flag.String("addr", "127.0.0.1:8080", "Listen address for the web dashboard")
+		notify    = flag.Bool("notify", false, "Send a desktop notification when the run finishes")
+		webhook   = flag.String("notify-webhook", "", "Webhook URL (e.g. Slack) to POST the run summary to (default $CSES_NOTIFY_WEBHOOK)")
+		notifyMin = flag.String("notify-min", "0s", "Only notify for runs that take at least this long")
+		envAllow  = flag.String("env-allow", "", "Comma separated host environment variables to pass to the solution (e.g. GOGC,GOMAXPROCS)")
+		gogc      = flag.String("solution-gogc", "", "GOGC value for the solution process (e.g. 200 or off)")
+		procs     = flag.Int("solution-procs", 0, "GOMAXPROCS for the solution process (0 leaves it unset)")
+		gogcSweep = flag.Bool("gogc-sweep", false, "Benchmark several GOGC values on the slowest test and report the best")
+		gcStats   = flag.Bool("gc-stats", false, "Report garbage collections, GC pause time and allocated bytes of every test (GODEBUG=gctrace=1)")
+		monitor   = flag.String("monitor", "", "Sample the solution's RSS and CPU at this interval (e.g. 20ms) and draw a timeline per test")
+		maxStack  = flag.String("max-stack", "", "Goroutine stack limit of the solution (e.g. 64MB), to catch recursion too deep for the judge")
+		hookType  = flag.String("hook-type", "pre-commit", "Git hook to manage with the hook command (pre-commit or pre-push)")
+		problems  = flag.String("problems", "", "Comma separated problem IDs of a practice contest")
+		duration  = flag.String("duration", "2h", "Length of a practice contest")
+		tmplName  = flag.String("template", "", "Template used by new (default fastio; see template list)")
+		showWS    = flag.Bool("show-whitespace", false, "Show tabs (→), carriage returns (␍) and trailing spaces (·) in diffs")
+		numeric   = flag.Bool("numeric", false, "Compare numbers by value (1e+09 == 1000000000) within -epsilon")
+		epsilon   = flag.Float64("epsilon", 1e-6, "Absolute or relative tolerance for -numeric")
+		dumpDir   = flag.String("dump-output-dir", "", "Write the full output of failed tests to <dir>/<problem>/<test>.actual.txt")
+		brute     = flag.String("brute", "", "Brute force solution the stress command compares against")
+		generator = flag.String("gen", "", "Generator program for stress; it gets the seed as its first argument")
+		genSpec   = flag.String("gen-spec", "", "Input description for stress instead of -gen, e.g. \"n:1..10 a:n ints 1..100\"")
+		archDir   = flag.String("archive-dir", "solutions", "Root directory of the archive command (<dir>/<category>/<id>-<name>/)")
+		sampleURL = flag.String("url", "", "Codeforces or AtCoder problem page to import sample tests from (tests import-samples)")
+		subsPage  = flag.Bool("submissions", false, "Open the problem's submissions page instead of the task (open command)")
+		ifPass    = flag.Bool("if-pass", false, "Run the tests first and only submit if they pass and no TLE/MLE is likely (submit command)")
+		localPass = flag.Bool("require-local-pass", false, "Only submit a source that passed every local test (reuses the recorded run if unchanged)")
+		language  = flag.String("submit-lang", "", "Language to submit in, as offered by the CSES submit form, optionally with its variant: C++:C++17, Python3:PyPy3 (default Go)")
+		dryRun    = flag.Bool("dry-run", false, "Show what submit would send, after its checks, without sending it")
+		force     = flag.Bool("force", false, "With -if-pass, submit even if the likely verdict is TLE or MLE")
+		checkOnly = flag.Bool("check", false, "Only report whether a newer release exists (update command)")
+		gitCommit = flag.Bool("git-commit-on-pass", false, "Commit the solution to git when every test passes")
+		hooksFile = flag.String("hooks", "", "Lifecycle hooks file (default: hooks.yaml in -cache-dir)")
+		gitAdd    = flag.Bool("git-add", false, "Stage archived solutions with git add")
+		manifest  = flag.String("manifest", "", "Run every problem of a problems.yaml manifest (-problem=a,b selects some)")
+		stressN   = flag.Int("iterations", 100, "Number of random inputs the stress command tries")
+		seedRange = flag.String("seed-range", "", "Seeds for stress to try, all of them, e.g. 1..10000 (overrides -iterations)")
+		goVers    = flag.String("go-versions", "", "Comma separated Go versions the bench command builds the solution with, e.g. 1.22,1.23.4,local")
+		dotenv    = flag.String("dotenv", "", "File of KEY=VAL lines for the runner's own environment, e.g. CSES_USERNAME (default ./.env)")
+		reveal    = flag.Bool("reveal-secrets", false, "Print session tokens instead of redacting them, for debugging authentication")
+		refPages  = flag.Bool("refresh-pages", false, "Fetch CSES pages (statistics, task pages, problem list) again instead of using the page cache")
+		account   = flag.String("account", "", "Named CSES account to use for this command (see auth switch)")
+		baseURL   = flag.String("base-url", DefaultBaseURL, "CSES base URL (e.g. a local fake started with serve -fake-cses)")
+		fakeCSES  = flag.Bool("fake-cses", false, "Serve an offline fake CSES on -addr instead of the dashboard (serve command)")
+		recordFix = flag.String("record-fixtures", "", "Record every CSES HTTP response to this directory")
+		replayFix = flag.String("replay-fixtures", "", "Answer CSES requests from fixtures recorded with -record-fixtures")
+		isolate   = flag.Bool("isolate-timing", false, "Run tests one at a time pinned to a single CPU for accurate timings")
+		warmup    = flag.Bool("warmup", false, "Run every test once untimed before the measured run(s)")
+		allowExit = flag.Bool("allow-nonzero-exit", false, "Accept correct output even if the solution exits with a non-zero status")
+		tags      = flag.String("tags", "", "Comma separated build tags passed to go build")
+		cgo       = flag.Bool("cgo", false, "Build the solution with CGO enabled (dynamically linked)")
+		backend   = flag.String("compiler", CompilerGC, "Compiler backend: gc, gccgo or tinygo")
+		solArgs   = flag.String("args", "", "Command line arguments passed to the solution, e.g. -args=\"--mode=fast\"")
+		envFile   = flag.String("env-file", "", "File of KEY=VAL lines added to the solution's environment")
 	)
 
+	var extraEnv envFlag
+	flag.Var(&extraEnv, "env", "Set KEY=VAL in the solution's environment (repeatable, e.g. -env DEBUG=1)")
+
+	var docker dockerImageFlag
+	flag.Var(&docker, "docker", "Compile and run inside a CPU/memory limited container without network (-docker or -docker=image, default "+defaultDockerImage+")")
+
 	// Handle version and help before parsing to avoid issues with commands
 	if len(os.Args) > 1 {
 		if os.Args[1] == "--version" || os.Args[1] == "-version" {
@@ -84,7 +209,7 @@ func main() {
 	if len(os.Args) > 1 {
 		// Check if first argument is a known command
 		firstArg := os.Args[1]
-		if firstArg == "auth" || firstArg == "clean" || firstArg == "run" {
+		if isCommand(firstArg) {
 			command = firstArg
 			flagArgs = os.Args[2:] // Skip program name and command
 		} else {
@@ -95,8 +220,36 @@ func main() {
 		flagArgs = os.Args[1:]
 	}
 
-	// Parse flags from the remaining arguments
-	flag.CommandLine.Parse(flagArgs)
+	// Parse flags from the remaining arguments. A bad flag is a usage error,
+	// not the flag package's exit status 2, which means a compile error here.
+	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+	args, err := parseArgs(flag.CommandLine, flagArgs)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		os.Exit(ExitUsageError)
+	}
+
+	// Session tokens never reach the terminal unless asked for
+	color.Output = redactingWriter{color.Output}
+	color.Error = redactingWriter{color.Error}
+	if *reveal {
+		revealSecrets()
+	}
+
+	// Before anything reads the environment: credentials, webhook, XDG dirs
+	loaded, err := loadDotenv(*dotenv)
+	if err != nil {
+		red.Printf("Error: %v\n", err)
+		os.Exit(ExitUsageError)
+	}
+	if *verbose && len(loaded) > 0 {
+		cyan.Printf("🔧 Loaded %s from the .env file\n", strings.Join(loaded, ", "))
+	}
+	if *webhook == "" {
+		*webhook = os.Getenv("CSES_NOTIFY_WEBHOOK")
+	}
 
 	if *version {
 		fmt.Printf("%s v%s\n", AppName, AppVersion)
@@ -114,75 +267,412 @@ func main() {
 		Timeout:   *timeout,
 		Verbose:   *verbose,
 		CacheDir:  *cacheDir,
+		ConfigDir: *configDir,
 		Parallel:  *parallel,
 		ShowDiff:  *showDiff,
 		MaxOutput: *maxOutput,
+		Summary:   *summary,
 		Optimize:  *optimize,
 		Race:      *race,
+		RaceTests: *raceTests,
+		Checks:    *checks,
 		ForceAuth: *forceAuth,
+		Repeat:    *repeat,
+		Test:      *testNum,
+		Attach:    *attach,
+		Stream:    *stream,
+		EnvAllow:  *envAllow,
+		Env:       extraEnv,
+		EnvFile:   *envFile,
+		Args:      *solArgs,
+
+		Order:       *order,
+		ShuffleSeed: *seed,
+
+		CachedResults: *cachedRes,
+
+		SolutionGOGC:  *gogc,
+		SolutionProcs: *procs,
+		GOGCSweep:     *gogcSweep,
+		GCStats:       *gcStats,
+		MaxStack:      *maxStack,
+		Monitor:       *monitor,
+
+		Notify:        *notify,
+		NotifyWebhook: *webhook,
+		NotifyMin:     *notifyMin,
+
+		ReportHTML:     *htmlOut,
+		ReportMarkdown: *mdOut,
+
+		SaveFailed: *saveFail,
+		FailedDir:  *failedDir,
+
+		BaseURL:      *baseURL,
+		Account:      *account,
+		RefreshPages: *refPages,
+
+		RecordFixtures: *recordFix,
+		ReplayFixtures: *replayFix,
+
+		IsolateTiming: *isolate,
+		Warmup:        *warmup,
+
+		Docker: string(docker),
+
+		AllowNonZeroExit: *allowExit,
+
+		Tags: *tags,
+		CGO:  *cgo,
+
+		Compiler: *backend,
+
+		DumpOutputDir:  *dumpDir,
+		ShowWhitespace: *showWS,
+
+		Numeric: *numeric,
+		Epsilon: *epsilon,
+
+		GitCommitOnPass: *gitCommit,
+
+		HooksFile: *hooksFile,
+
+		CacheMaxSize: *cacheMax,
+		RefreshTests: *refresh,
+
+		ShowNotes: *showNotes,
+
+		IOInput:  *ioInput,
+		IOOutput: *ioOutput,
+	}
+
+	if config.ShuffleSeed == 0 {
+		config.ShuffleSeed = time.Now().UnixNano()
+	}
+
+	if config.RecordFixtures != "" && config.ReplayFixtures != "" {
+		red.Println("Error: -record-fixtures and -replay-fixtures cannot be combined")
+		os.Exit(ExitUsageError)
+	}
+
+	if config.EnvFile != "" {
+		if _, err := parseEnvFile(config.EnvFile); err != nil {
+			red.Printf("Error: %v\n", err)
+			os.Exit(ExitUsageError)
+		}
+	}
+
+	if config.Account != "" && !accountNamePattern.MatchString(config.Account) {
+		red.Printf("Error: invalid account name %q (letters, digits, - and _)\n", config.Account)
+		os.Exit(ExitUsageError)
 	}
 
-	//Ensure cache exists
-	enusureCacheDir(config)
+	// Resolve and create the cache and config directories
+	resolveDirs(config)
+	config.ProblemID = resolveAliases(config, config.ProblemID)
+
+	if *stdio {
+		if err := ServeStdio(config); err != nil {
+			red.Fprintf(os.Stderr, "❌ RPC server failed: %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	}
 
 	switch command {
 	case "auth":
-		if err := handleAuth(config); err != nil {
-			red.Printf("❌ Authentication failed: %v\n", err)
-			os.Exit(1)
+		if err := handleAuth(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
 		}
 		return
 	case "clean":
-		if err := os.RemoveAll(*cacheDir); err != nil {
-			red.Printf("Error cleaning cache: %v\n", err)
-			os.Exit(1)
+		if err := handleClean(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "alias":
+		if err := handleAlias(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "notes":
+		if err := handleNotes(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "cache":
+		if err := handleCache(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "serve":
+		if err := handleServe(config, *web, *fakeCSES, *addr); err != nil {
+			red.Printf("❌ Server failed: %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "history":
+		if err := handleHistory(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "hook":
+		if err := handleHook(config, args, *hookType); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "submit":
+		if err := handleSubmit(config, SubmitOptions{IfPass: *ifPass, Force: *force, RequireLocalPass: *localPass, Language: *language, DryRun: *dryRun}); err != nil {
+			if !errors.Is(err, ErrTestsFailed) {
+				red.Printf("❌ %v\n", err)
+			}
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "exec":
+		if err := handleExec(config, *inputFile); err != nil {
+			red.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "status":
+		if err := handleStatus(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "stats":
+		if err := handleStats(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "session":
+		if err := handleSession(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "practice":
+		if err := handlePractice(config, args, resolveAliases(config, *problems), *duration); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "suggest":
+		if err := handleSuggest(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "new":
+		if err := handleNew(config, *tmplName); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "template":
+		if err := handleTemplate(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "optimize-io":
+		if err := handleOptimizeIO(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "hints":
+		if err := handleHints(config); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "archive":
+		if err := handleArchive(config, args, *archDir, *gitAdd); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "open":
+		if err := handleOpen(config, args, *subsPage); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "update":
+		if err := handleUpdate(*checkOnly); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "share":
+		if err := handleShare(config); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "tests":
+		if err := handleTests(config, args, *sampleURL); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "stress":
+		options := StressOptions{Brute: *brute, Generator: *generator, Spec: *genSpec, Iterations: *stressN, SeedRange: *seedRange}
+		if err := handleStress(config, options); err != nil {
+			if !errors.Is(err, ErrTestsFailed) {
+				red.Printf("❌ %v\n", err)
+			}
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "bench":
+		if err := handleBench(config, *goVers); err != nil {
+			if !errors.Is(err, ErrTestsFailed) {
+				red.Printf("❌ %v\n", err)
+			}
+			os.Exit(exitCodeFor(err))
+		}
+		return
+	case "asm":
+		if err := handleAsm(config, args); err != nil {
+			red.Printf("❌ %v\n", err)
+			os.Exit(exitCodeFor(err))
 		}
-		green.Println("Cache cleaned successfully")
 		return
 	case "run":
 		// Continue with normal execution
 	default:
 		red.Printf("Unknown command: %s\n", command)
 		printUsage()
-		os.Exit(1)
+		os.Exit(ExitUsageError)
+	}
+
+	if *manifest != "" {
+		runs, err := LoadManifest(*manifest)
+		if err == nil && config.ProblemID != "" {
+			runs, err = selectProblemRuns(runs, config.ProblemID)
+		}
+		if err != nil {
+			red.Printf("Error: %v\n", err)
+			os.Exit(ExitUsageError)
+		}
+		showContestClock(config)
+		if err := runProblems(config, runs); err != nil {
+			if !errors.Is(err, ErrTestsFailed) {
+				red.Printf("❌ %v\n", err)
+			}
+			os.Exit(exitCodeFor(err))
+		}
+		return
 	}
 
 	// Validate required flags for run command
-	if *filePath == "" || *problemID == "" {
+	if *filePath == "" || config.ProblemID == "" {
 		red.Println("Error: Both -file and -problem flags are required for run command")
 		printUsage()
-		os.Exit(1)
-	}
-
-	// Validate file exists and is a Go file
-	if _, err := os.Stat(*filePath); os.IsNotExist(err) {
-		red.Printf("Error: File %s does not exist\n", *filePath)
-		os.Exit(1)
+		os.Exit(ExitUsageError)
 	}
 
-	if !strings.HasSuffix(*filePath, ".go") {
-		red.Printf("Error: File %s is not a Go file (.go extension required)\n", *filePath)
-		os.Exit(1)
+	if strings.Contains(config.ProblemID, ",") {
+		runs, err := resolveProblemRuns(config.ProblemID, *filePath)
+		if err != nil {
+			red.Printf("Error: %v\n", err)
+			os.Exit(ExitUsageError)
+		}
+		showContestClock(config)
+		if err := runProblems(config, runs); err != nil {
+			if !errors.Is(err, ErrTestsFailed) {
+				red.Printf("❌ %v\n", err)
+			}
+			os.Exit(exitCodeFor(err))
+		}
+		return
 	}
 
-	// Validate problem ID
-	if _, err := strconv.Atoi(*problemID); err != nil {
-		red.Printf("Error: Invalid problem ID %s\n", *problemID)
-		os.Exit(1)
+	if err := validateRunConfig(config); err != nil {
+		red.Printf("Error: %v\n", err)
+		os.Exit(ExitUsageError)
 	}
 
-	runner := NewTestRunner(config)
+	runner := NewTestRunner(config, NewCSESAuth(config))
 
-	cyan.Printf("🚀 Starting CSES Go Test Runner for problem %s\n", *problemID)
+	cyan.Printf("🚀 Starting CSES Go Test Runner for problem %s\n", config.ProblemID)
 	cyan.Printf("📁 Solution file: %s\n", *filePath)
+	showContestClock(config)
 
 	if err := runner.Run(); err != nil {
-		red.Printf("❌ Runner failed: %v\n", err)
-		os.Exit(1)
+		if !errors.Is(err, ErrTestsFailed) {
+			red.Printf("❌ Runner failed: %v\n", err)
+		}
+		os.Exit(exitCodeFor(err))
 	}
 }
 
-func handleAuth(config *Config) error {
+// parseArgs parses flags that may appear before, between or after positional
+// arguments (e.g. `history show 3 -cache-dir=...`) and returns the positionals,
+// or the first flag error
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// isCommand reports whether name is one of the subcommands
+func isCommand(name string) bool {
+	switch name {
+	case "auth", "submit", "alias", "notes", "cache", "clean", "run", "exec", "serve", "history", "hook", "asm", "status", "stats", "session", "practice", "suggest", "new", "template", "optimize-io", "hints", "stress", "bench", "tests", "archive", "share", "open", "update":
+		return true
+	}
+	return false
+}
+
+func handleServe(config *Config, web, fakeCSES bool, addr string) error {
+	if fakeCSES {
+		return serveFakeCSES(config, addr)
+	}
+
+	if !web {
+		return withExitCode(ExitUsageError, fmt.Errorf("serve needs -web (dashboard) or -fake-cses (offline CSES)"))
+	}
+
+	dashboard, err := NewDashboardServer(config)
+	if err != nil {
+		return err
+	}
+
+	return dashboard.ListenAndServe(addr)
+}
+
+// handleAuth implements `auth` (log in), `auth switch <name>` and `auth whoami`
+func handleAuth(config *Config, args []string) error {
+	switch {
+	case len(args) == 2 && args[0] == "switch":
+		return handleAuthSwitch(config, args[1])
+	case len(args) == 1 && args[0] == "whoami":
+		return handleAuthWhoami(config)
+	case len(args) > 0:
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: auth | auth switch <name> | auth whoami"))
+	}
+
 	auth := NewCSESAuth(config)
 
 	if config.ForceAuth {
@@ -193,28 +683,101 @@ func handleAuth(config *Config) error {
 	}
 
 	if err := auth.EnsureAuthenticated(); err != nil {
-		return fmt.Errorf("authentication failed: %w", err)
+		return withExitCode(ExitFetchError, fmt.Errorf("authentication failed: %w", err))
 	}
 
 	green.Println("✅ Authentication successful")
 	return nil
 }
 
-func enusureCacheDir(config *Config) {
-	// Get the current user
-	currentUser, err := user.Current()
-	if err != nil {
-		fmt.Println("Error getting current user:", err)
-		return
+// validateSolutionFile checks that the solution file exists and is a Go file
+func validateSolutionFile(config *Config) error {
+	if config.FilePath == "" {
+		return fmt.Errorf("the -file flag is required")
+	}
+
+	if _, err := os.Stat(config.FilePath); os.IsNotExist(err) {
+		return fmt.Errorf("file %s does not exist", config.FilePath)
+	}
+
+	if !strings.HasSuffix(config.FilePath, ".go") {
+		return fmt.Errorf("file %s is not a Go file (.go extension required)", config.FilePath)
+	}
+
+	return nil
+}
+
+// validateRunConfig checks that the solution file and problem ID of a run are usable
+func validateRunConfig(config *Config) error {
+	if config.FilePath == "" || config.ProblemID == "" {
+		return fmt.Errorf("both file and problem are required")
+	}
+
+	if err := validateSolutionFile(config); err != nil {
+		return err
+	}
+
+	// Validate problem ID; samples imported from other judges have IDs like cf-1352A
+	if _, err := strconv.Atoi(config.ProblemID); err != nil && !isImportedProblem(config.ProblemID) {
+		return fmt.Errorf("invalid problem ID %s", config.ProblemID)
+	}
+
+	for _, name := range []string{config.IOInput, config.IOOutput} {
+		if name != "" && (strings.ContainsAny(name, `/\`) || name == "." || name == "..") {
+			return fmt.Errorf("-io-input and -io-output take a file name, not a path: %s", name)
+		}
+	}
+	if config.FileIO() && config.Docker != "" {
+		return fmt.Errorf("-io-input and -io-output cannot be combined with -docker")
 	}
 
-	// Get the home directory
-	homeDir := currentUser.HomeDir
+	if err := validateOrder(config.Order); err != nil {
+		return err
+	}
+
+	if err := validateCompiler(config); err != nil {
+		return err
+	}
+
+	switch config.Summary {
+	case SummaryTable, SummaryCompact, SummaryFailuresOnly:
+	default:
+		return fmt.Errorf("invalid summary style %q (use table, compact or failures-only)", config.Summary)
+	}
 
-	absolutePath := strings.Replace(config.CacheDir, "~", homeDir, 1)
-	config.CacheDir = absolutePath
+	if config.Test < 0 {
+		return fmt.Errorf("invalid test number %d", config.Test)
+	}
+
+	if config.Epsilon < 0 {
+		return fmt.Errorf("invalid epsilon %g", config.Epsilon)
+	}
+
+	if config.Parallel < 0 {
+		return fmt.Errorf("invalid parallelism %d (use 0 for auto)", config.Parallel)
+	}
 
-	// Clean and resolve the path
-	finalPath := filepath.Clean(absolutePath)
-	os.MkdirAll(finalPath, os.ModeDir)
+	if config.Monitor != "" {
+		if interval, err := time.ParseDuration(config.Monitor); err != nil || interval < time.Millisecond {
+			return fmt.Errorf("invalid -monitor interval %q (use e.g. 20ms)", config.Monitor)
+		}
+		if config.Docker != "" {
+			return fmt.Errorf("-monitor samples the solution on the host and cannot be combined with -docker")
+		}
+	}
+
+	if config.CachedResults && config.Test != 0 {
+		return fmt.Errorf("-cached-results replays full runs and cannot be combined with -test")
+	}
+	if config.Attach && config.Test == 0 {
+		return fmt.Errorf("-attach requires -test to select a single test")
+	}
+	if config.Stream && config.Test == 0 {
+		return fmt.Errorf("-stream requires -test to select a single test")
+	}
+	if config.Stream && config.Attach {
+		return fmt.Errorf("-stream and -attach cannot be combined")
+	}
+
+	return nil
 }
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	newFlags := func() (*flag.FlagSet, *string) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		return fs, fs.String("cache-dir", "", "")
+	}
+
+	fs, cacheDir := newFlags()
+	args, err := parseArgs(fs, []string{"show", "-cache-dir=/tmp/c", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(args, []string{"show", "3"}) || *cacheDir != "/tmp/c" {
+		t.Errorf("parseArgs() = %q with -cache-dir=%q", args, *cacheDir)
+	}
+
+	fs, _ = newFlags()
+	if _, err := parseArgs(fs, []string{"show", "-bogus"}); err == nil || errors.Is(err, flag.ErrHelp) {
+		t.Errorf("parseArgs() with an unknown flag = %v, want a flag error", err)
+	}
+	fs, _ = newFlags()
+	if _, err := parseArgs(fs, []string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("parseArgs(-h) = %v, want flag.ErrHelp", err)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is a problems.yaml file listing the solutions of a repository:
+//
+//	defaults:
+//	  timeout: 2s
+//	problems:
+//	  - id: 1068
+//	    file: introductory/1068.go
+//	  - id: 1640
+//	    file: sorting/1640.go
+//	    timeout: 3s
+//	    compiler: gccgo
+//	  - id: 2000
+//	    file: extra/2000.go
+//	    tests:
+//	      dir: extra/tests/{id}
+type Manifest struct {
+	Defaults ManifestSettings  `yaml:"defaults"`
+	Problems []ManifestProblem `yaml:"problems"`
+}
+
+// ManifestProblem is one entry of a manifest. File is relative to the manifest.
+type ManifestProblem struct {
+	ID               string `yaml:"id"`
+	File             string `yaml:"file"`
+	Language         string `yaml:"language"`
+	ManifestSettings `yaml:",inline"`
+}
+
+// ManifestSettings override command line flags for every problem (defaults)
+// or a single one; unset fields keep the flag's value
+type ManifestSettings struct {
+	Timeout          string   `yaml:"timeout"`
+	Compiler         string   `yaml:"compiler"`
+	Tags             string   `yaml:"tags"`
+	Optimize         *bool    `yaml:"optimize"`
+	Race             *bool    `yaml:"race"`
+	Numeric          *bool    `yaml:"numeric"`
+	Epsilon          *float64 `yaml:"epsilon"`
+	AllowNonZeroExit *bool    `yaml:"allow_nonzero_exit"`
+	Args             string   `yaml:"args"`
+
+	// File-based I/O (see Config.IOInput)
+	IOInput  string `yaml:"io_input"`
+	IOOutput string `yaml:"io_output"`
+
+	// Tests replaces the CSES download (see TestSourceSpec)
+	Tests *TestSourceSpec `yaml:"tests"`
+}
+
+// supportedLanguage is the only language a manifest entry can use
+const supportedLanguage = "go"
+
+// LoadManifest reads a manifest and resolves its entries into problem runs
+func LoadManifest(path string) ([]ProblemRun, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if len(manifest.Problems) == 0 {
+		return nil, fmt.Errorf("manifest %s lists no problems", path)
+	}
+
+	dir := filepath.Dir(path)
+	if err := manifest.Defaults.resolveTests(dir); err != nil {
+		return nil, fmt.Errorf("manifest defaults: %w", err)
+	}
+
+	var runs []ProblemRun
+	for i, problem := range manifest.Problems {
+		if problem.ID == "" || problem.File == "" {
+			return nil, fmt.Errorf("manifest entry %d needs both id and file", i+1)
+		}
+		if problem.Language != "" && problem.Language != supportedLanguage {
+			return nil, fmt.Errorf("problem %s: language %q is not supported (only %s)", problem.ID, problem.Language, supportedLanguage)
+		}
+
+		if err := problem.resolveTests(dir); err != nil {
+			return nil, fmt.Errorf("problem %s: %w", problem.ID, err)
+		}
+
+		file := problem.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+
+		settings := []ManifestSettings{manifest.Defaults, problem.ManifestSettings}
+		runs = append(runs, ProblemRun{ProblemID: problem.ID, FilePath: file, Settings: settings})
+	}
+
+	return runs, nil
+}
+
+// selectProblemRuns keeps the runs of the comma separated problem IDs, in the
+// manifest's order
+func selectProblemRuns(runs []ProblemRun, problems string) ([]ProblemRun, error) {
+	wanted := make(map[string]bool)
+	for _, id := range strings.Split(problems, ",") {
+		wanted[strings.TrimSpace(id)] = true
+	}
+
+	var selected []ProblemRun
+	for _, run := range runs {
+		if wanted[run.ProblemID] {
+			selected = append(selected, run)
+			delete(wanted, run.ProblemID)
+		}
+	}
+	for id := range wanted {
+		return nil, fmt.Errorf("problem %s is not in the manifest", id)
+	}
+	return selected, nil
+}
+
+// resolveTests validates the tests setting and makes a local test directory
+// relative to the manifest
+func (s *ManifestSettings) resolveTests(dir string) error {
+	if s.Tests == nil {
+		return nil
+	}
+	if err := s.Tests.validate(); err != nil {
+		return err
+	}
+	if s.Tests.Dir != "" && !filepath.IsAbs(s.Tests.Dir) {
+		s.Tests.Dir = filepath.Join(dir, s.Tests.Dir)
+	}
+	return nil
+}
+
+// apply copies the fields set in the manifest onto config
+func (s ManifestSettings) apply(config *Config) error {
+	if s.Timeout != "" {
+		if _, err := time.ParseDuration(s.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout %q", s.Timeout)
+		}
+		config.Timeout = s.Timeout
+	}
+	if s.Compiler != "" {
+		config.Compiler = s.Compiler
+	}
+	if s.Tags != "" {
+		config.Tags = s.Tags
+	}
+	if s.Optimize != nil {
+		config.Optimize = *s.Optimize
+	}
+	if s.Race != nil {
+		config.Race = *s.Race
+	}
+	if s.Numeric != nil {
+		config.Numeric = *s.Numeric
+	}
+	if s.Epsilon != nil {
+		config.Epsilon = *s.Epsilon
+	}
+	if s.AllowNonZeroExit != nil {
+		config.AllowNonZeroExit = *s.AllowNonZeroExit
+	}
+	if s.Args != "" {
+		config.Args = s.Args
+	}
+	if s.IOInput != "" {
+		config.IOInput = s.IOInput
+	}
+	if s.IOOutput != "" {
+		config.IOOutput = s.IOOutput
+	}
+	if s.Tests != nil {
+		config.Tests = s.Tests
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// systemMemory is not available on this platform, so parallelism is not memory-aware
+func systemMemory() (available, total int64, ok bool) {
+	return 0, 0, false
+}
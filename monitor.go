@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sparkBlocks are the bar heights of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparklineWidth caps a sparkline; longer timelines are condensed into it
+const sparklineWidth = 32
+
+// ResourceSample is the solution's memory and CPU use at one moment of a run
+type ResourceSample struct {
+	At  time.Duration // since the solution started
+	RSS int64
+	CPU time.Duration // CPU time used since the start
+}
+
+// processMonitor samples a running solution every interval (-monitor)
+type processMonitor struct {
+	stop    chan struct{}
+	done    chan struct{}
+	samples []ResourceSample
+}
+
+// startMonitor begins sampling pid, or returns nil when interval is 0
+func startMonitor(pid int, interval time.Duration) *processMonitor {
+	if interval <= 0 {
+		return nil
+	}
+	m := &processMonitor{stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(m.done)
+		start := time.Now()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if rss, cpu, ok := sampleProcess(pid); ok {
+				m.samples = append(m.samples, ResourceSample{At: time.Since(start), RSS: rss, CPU: cpu})
+			}
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return m
+}
+
+// Stop ends the sampling once the solution has exited and returns the samples
+func (m *processMonitor) Stop() []ResourceSample {
+	if m == nil {
+		return nil
+	}
+	close(m.stop)
+	<-m.done
+	return m.samples
+}
+
+// timelineSummary condenses a test's samples into its RSS and CPU sparklines
+type timelineSummary struct {
+	RSS, CPU string
+	PeakRSS  int64
+	AvgCPU   float64 // percent of one core over the whole run
+	Growing  bool    // the RSS was still climbing when the solution exited
+}
+
+// summarizeTimeline buckets the samples into at most sparklineWidth columns:
+// the highest RSS and the CPU share of each stretch of the run
+func summarizeTimeline(samples []ResourceSample) timelineSummary {
+	var summary timelineSummary
+	if len(samples) == 0 {
+		return summary
+	}
+	width := min(len(samples), sparklineWidth)
+	rss := make([]float64, width)
+	cpu := make([]float64, width)
+	for column := range width {
+		from, to := column*len(samples)/width, (column+1)*len(samples)/width
+		for _, sample := range samples[from:to] {
+			rss[column] = max(rss[column], float64(sample.RSS))
+		}
+		// CPU time used per wall clock time between the first sample before
+		// the column and its last one
+		before := samples[max(from-1, 0)]
+		last := samples[to-1]
+		if elapsed := last.At - before.At; elapsed > 0 {
+			cpu[column] = float64(last.CPU-before.CPU) / float64(elapsed) * 100
+		}
+		summary.PeakRSS = max(summary.PeakRSS, int64(rss[column]))
+	}
+
+	last := samples[len(samples)-1]
+	if last.At > 0 {
+		summary.AvgCPU = float64(last.CPU) / float64(last.At) * 100
+	}
+	peakCPU := 100.0
+	for _, share := range cpu {
+		peakCPU = max(peakCPU, share)
+	}
+	summary.RSS = sparkline(rss, float64(summary.PeakRSS))
+	summary.CPU = sparkline(cpu, peakCPU)
+
+	// Memory that keeps growing to the end is what turns into MLE once the
+	// hidden tests are bigger
+	summary.Growing = width >= 4 && rss[width-1] == float64(summary.PeakRSS) && rss[width-1] >= 1.5*rss[width/2-1]
+	return summary
+}
+
+// sparkline draws values as bars scaled to top
+func sparkline(values []float64, top float64) string {
+	var b strings.Builder
+	for _, value := range values {
+		level := 0
+		if top > 0 {
+			level = int(value / top * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[min(max(level, 0), len(sparkBlocks)-1)])
+	}
+	return b.String()
+}
+
+// displayTimelines prints the RSS and CPU sparkline of every test sampled by
+// -monitor and points out tests whose memory grew until they finished
+func displayTimelines(config *Config, results []TestResult) {
+	if config.Monitor == "" {
+		return
+	}
+	if !processSamplingSupported {
+		yellow.Println("⚠️  -monitor is only supported on Linux, no samples were taken")
+		return
+	}
+
+	fmt.Println("\n" + strings.Repeat("-", 40))
+	white.Printf("📈 RESOURCE TIMELINE (sampled every %s)\n", config.GetMonitorInterval())
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("%-6s %-*s %9s  %-*s %7s\n", "TEST", sparklineWidth, "RSS", "PEAK", sparklineWidth, "CPU", "AVG")
+
+	var growing, unsampled []int
+	for _, result := range results {
+		if len(result.Timeline) < 2 {
+			unsampled = append(unsampled, result.TestNumber)
+			continue
+		}
+		summary := summarizeTimeline(result.Timeline)
+		line := fmt.Sprintf("%-6d %-*s %9s  %-*s %6.0f%%", result.TestNumber, sparklineWidth, summary.RSS,
+			formatBytes(summary.PeakRSS), sparklineWidth, summary.CPU, summary.AvgCPU)
+		if summary.Growing {
+			growing = append(growing, result.TestNumber)
+			line += " ↗"
+		}
+		fmt.Println(line)
+	}
+
+	if len(unsampled) > 0 {
+		fmt.Printf("   Test(s) %s finished within one sample; a shorter -monitor interval catches them\n", formatTestNumbers(unsampled))
+	}
+	if len(growing) > 0 {
+		yellow.Printf("⚠️  Memory of test(s) %s was still growing when they finished; bigger hidden tests may exceed the memory limit\n",
+			formatTestNumbers(growing))
+	}
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// processSamplingSupported reports whether -monitor can sample the solution
+const processSamplingSupported = true
+
+// clockTicks is USER_HZ, the unit of the CPU times in /proc, which is 100 on
+// every architecture Linux runs Go on
+const clockTicks = 100
+
+// sampleProcess reads the resident set size and the CPU time used so far of
+// a running process from /proc/<pid>/stat
+func sampleProcess(pid int) (rss int64, cpu time.Duration, ok bool) {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return 0, 0, false
+	}
+
+	// The command name may contain spaces and parentheses; the fields after
+	// it start with the state, which is field 3 of proc(5)
+	stat := string(data)
+	fields := strings.Fields(stat[strings.LastIndexByte(stat, ')')+1:])
+	if len(fields) < 22 || fields[0] == "Z" {
+		return 0, 0, false
+	}
+	utime, _ := strconv.ParseInt(fields[11], 10, 64)
+	stime, _ := strconv.ParseInt(fields[12], 10, 64)
+	pages, _ := strconv.ParseInt(fields[21], 10, 64)
+
+	cpu = time.Duration(utime+stime) * time.Second / clockTicks
+	return pages * int64(os.Getpagesize()), cpu, true
+}
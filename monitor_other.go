@@ -0,0 +1,13 @@
+//go:build !linux
+
+package main
+
+import "time"
+
+// processSamplingSupported reports whether -monitor can sample the solution
+const processSamplingSupported = false
+
+// sampleProcess is not available on this platform; -monitor records nothing
+func sampleProcess(pid int) (rss int64, cpu time.Duration, ok bool) {
+	return 0, 0, false
+}
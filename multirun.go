@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ProblemRun pairs a problem with the solution tested against it
+type ProblemRun struct {
+	ProblemID string
+	FilePath  string
+
+	// Settings from a manifest, applied in order over the command line flags
+	Settings []ManifestSettings
+}
+
+// problemRunOutcome is the line a problem gets in the combined summary
+type problemRunOutcome struct {
+	run    ProblemRun
+	record *RunRecord // nil if the run failed before any test ran
+	err    error
+}
+
+// resolveProblemRuns pairs the comma separated problem IDs of -problem with
+// solutions: the entries of a comma separated -file list in the same order, or
+// the files matched by a -file glob whose name contains the problem ID
+func resolveProblemRuns(problems, files string) ([]ProblemRun, error) {
+	var ids []string
+	for _, id := range strings.Split(problems, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	if strings.Contains(files, ",") {
+		paths := strings.Split(files, ",")
+		if len(paths) != len(ids) {
+			return nil, fmt.Errorf("-file lists %d files for %d problems", len(paths), len(ids))
+		}
+		runs := make([]ProblemRun, len(ids))
+		for i, id := range ids {
+			runs[i] = ProblemRun{ProblemID: id, FilePath: strings.TrimSpace(paths[i])}
+		}
+		return runs, nil
+	}
+
+	matches, err := filepath.Glob(files)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -file pattern %q: %w", files, err)
+	}
+
+	byProblem := make(map[string][]string)
+	for _, match := range matches {
+		if id := inferProblemID(match); id != "" {
+			byProblem[id] = append(byProblem[id], match)
+		}
+	}
+
+	runs := make([]ProblemRun, 0, len(ids))
+	for _, id := range ids {
+		switch candidates := byProblem[id]; len(candidates) {
+		case 1:
+			runs = append(runs, ProblemRun{ProblemID: id, FilePath: candidates[0]})
+		case 0:
+			return nil, fmt.Errorf("no file matching %q is named after problem %s", files, id)
+		default:
+			return nil, fmt.Errorf("several files matching %q are named after problem %s: %s", files, id, strings.Join(candidates, ", "))
+		}
+	}
+	return runs, nil
+}
+
+// runProblems runs each problem in turn with the full per-problem output,
+// then prints a combined summary. Every run is validated before the first starts.
+func runProblems(config *Config, runs []ProblemRun) error {
+	configs := make([]*Config, len(runs))
+	for i, run := range runs {
+		runConfig := *config
+		runConfig.FilePath = run.FilePath
+		runConfig.ProblemID = run.ProblemID
+		for _, settings := range run.Settings {
+			if err := settings.apply(&runConfig); err != nil {
+				return withExitCode(ExitUsageError, fmt.Errorf("problem %s: %w", run.ProblemID, err))
+			}
+		}
+		if err := validateRunConfig(&runConfig); err != nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("problem %s: %w", run.ProblemID, err))
+		}
+		configs[i] = &runConfig
+	}
+
+	auth := NewCSESAuth(config)
+	var problemIDs []string
+	for i, run := range runs {
+		if configs[i].Tests == nil {
+			problemIDs = append(problemIDs, run.ProblemID)
+		}
+	}
+	prefetchTests(config, auth, problemIDs)
+
+	var outcomes []problemRunOutcome
+	for i, run := range runs {
+		fmt.Println("\n" + strings.Repeat("#", 60))
+		cyan.Printf("🚀 Problem %s (%d/%d): %s\n", run.ProblemID, i+1, len(runs), run.FilePath)
+
+		runner := NewTestRunner(configs[i], auth)
+		err := runner.Run()
+		if err != nil && !errors.Is(err, ErrTestsFailed) {
+			red.Printf("❌ Runner failed: %v\n", err)
+		}
+		outcomes = append(outcomes, problemRunOutcome{run: run, record: runner.lastRun, err: err})
+	}
+
+	return displayCombinedSummary(outcomes)
+}
+
+func displayCombinedSummary(outcomes []problemRunOutcome) error {
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	white.Printf("📊 COMBINED SUMMARY (%d problems)\n", len(outcomes))
+	fmt.Println(strings.Repeat("=", 60))
+
+	failed := 0
+	for _, outcome := range outcomes {
+		line := fmt.Sprintf("%-8s %-30s", outcome.run.ProblemID, outcome.run.FilePath)
+		switch {
+		case outcome.err == nil && outcome.record == nil:
+			green.Printf("%s ✅ passed\n", line)
+		case outcome.err == nil:
+			green.Printf("%s ✅ %d/%d passed\n", line, outcome.record.Passed, outcome.record.Total)
+		case outcome.record != nil:
+			failed++
+			red.Printf("%s ❌ %d/%d failed\n", line, outcome.record.Failed, outcome.record.Total)
+		default:
+			failed++
+			red.Printf("%s ❌ %v\n", line, outcome.err)
+		}
+	}
+
+	fmt.Println(strings.Repeat("=", 60))
+	if failed > 0 {
+		red.Printf("💥 %d of %d problems failed\n", failed, len(outcomes))
+		return ErrTestsFailed
+	}
+	green.Printf("🎉 All %d problems passed\n", len(outcomes))
+	return nil
+}
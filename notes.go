@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// handleNotes implements `notes edit <problem>` and `notes show <problem>`
+func handleNotes(config *Config, args []string) error {
+	if len(args) == 0 || len(args) > 2 || (args[0] != "edit" && args[0] != "show") {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: notes edit|show <problem>"))
+	}
+
+	problemID := config.ProblemID
+	if len(args) == 2 {
+		problemID = resolveAliases(config, args[1])
+	}
+	if _, err := strconv.Atoi(problemID); err != nil {
+		return withExitCode(ExitUsageError, fmt.Errorf("invalid problem ID %q", problemID))
+	}
+
+	if args[0] == "edit" {
+		return editNotes(config, problemID)
+	}
+
+	notes, err := os.ReadFile(config.GetNotesPath(problemID))
+	if os.IsNotExist(err) {
+		fmt.Printf("No notes for problem %s yet; write them with `%s notes edit %s`\n", problemID, AppName, problemID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read notes: %w", err)
+	}
+	fmt.Print(string(notes))
+	return nil
+}
+
+// editNotes opens the notes of a problem in $VISUAL or $EDITOR, starting
+// new notes from an outline
+func editNotes(config *Config, problemID string) error {
+	path := config.GetNotesPath(problemID)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		title := problemID
+		if name := cachedProblemName(config, problemID); name != "" {
+			title += " - " + name
+		}
+		outline := fmt.Sprintf("# %s\n\n## Approach\n\n## Complexity\n\n## Pitfalls\n", title)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create notes directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(outline), 0644); err != nil {
+			return fmt.Errorf("failed to create notes: %w", err)
+		}
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// The editor may come with arguments, e.g. EDITOR="code --wait"
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+
+	green.Printf("📝 Notes saved to %s\n", path)
+	return nil
+}
+
+// displayNotes prints the notes of the problem above the run output when
+// -show-notes is set
+func displayNotes(config *Config) {
+	if !config.ShowNotes {
+		return
+	}
+
+	notes, err := os.ReadFile(config.GetNotesPath(config.ProblemID))
+	if err != nil || len(strings.TrimSpace(string(notes))) == 0 {
+		return
+	}
+
+	cyan.Printf("📝 Notes for problem %s:\n", config.ProblemID)
+	for _, line := range strings.Split(strings.TrimRight(string(notes), "\n"), "\n") {
+		fmt.Println("   " + line)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// Notifier tells the user a run finished via the desktop and/or a webhook
+type Notifier struct {
+	config *Config
+	client *http.Client
+}
+
+func NewNotifier(config *Config) *Notifier {
+	return &Notifier{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NotifyRun sends a notification for a finished run of the tests
+func (n *Notifier) NotifyRun(elapsed time.Duration, results []TestResult, runErr error) {
+	var message string
+	if runErr != nil {
+		message = fmt.Sprintf("💥 Run failed after %s: %v", elapsed.Round(time.Millisecond), runErr)
+	} else {
+		passed := 0
+		for _, result := range results {
+			if result.Passed {
+				passed++
+			}
+		}
+
+		if passed == len(results) {
+			message = fmt.Sprintf("🎉 All %d tests passed in %s", len(results), elapsed.Round(time.Millisecond))
+		} else {
+			message = fmt.Sprintf("💥 %d/%d tests failed in %s", len(results)-passed, len(results), elapsed.Round(time.Millisecond))
+		}
+	}
+	n.notify(elapsed, message)
+}
+
+// NotifyStress sends a notification for a finished stress test: how many
+// random inputs passed and the first seed that failed
+func (n *Notifier) NotifyStress(elapsed time.Duration, passed int, failures []stressFailure, runErr error) {
+	var message string
+	switch {
+	case runErr != nil:
+		message = fmt.Sprintf("💥 Stress test failed after %s: %v", elapsed.Round(time.Millisecond), runErr)
+	case len(failures) == 0:
+		message = fmt.Sprintf("🎉 All %d random inputs passed in %s", passed, elapsed.Round(time.Millisecond))
+	default:
+		message = fmt.Sprintf("💥 %d/%d random inputs failed in %s, first at seed %d",
+			len(failures), passed+len(failures), elapsed.Round(time.Millisecond), failures[0].seed)
+	}
+	n.notify(elapsed, message)
+}
+
+// notify sends message if notifications are enabled and the run took at
+// least the configured minimum duration
+func (n *Notifier) notify(elapsed time.Duration, message string) {
+	if !n.config.Notify && n.config.NotifyWebhook == "" {
+		return
+	}
+	if elapsed < n.config.GetNotifyMinDuration() {
+		return
+	}
+
+	title := fmt.Sprintf("%s: problem %s", AppName, n.config.ProblemID)
+	if n.config.Notify {
+		if err := n.sendDesktop(title, message); err != nil {
+			yellow.Printf("⚠️  Failed to send desktop notification: %v\n", err)
+		}
+	}
+
+	if n.config.NotifyWebhook != "" {
+		if err := n.sendWebhook(title, message); err != nil {
+			yellow.Printf("⚠️  Failed to send webhook notification: %v\n", err)
+		}
+	}
+}
+
+func (n *Notifier) sendDesktop(title, message string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.Command("notify-send", "--app-name="+AppName, title, message)
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		cmd = exec.Command("osascript", "-e", script)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w %s", cmd.Path, err, bytes.TrimSpace(output))
+	}
+
+	return nil
+}
+
+// sendWebhook POSTs a Slack-compatible JSON payload ({"text": ...}) to the webhook URL
+func (n *Notifier) sendWebhook(title, message string) error {
+	payload, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", title, message),
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.config.NotifyWebhook, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// handleOpen opens the task page of a problem (or with -submissions, the
+// page of the user's submissions) in the system browser. The problem can be
+// given by ID or by (part of) its name.
+func handleOpen(config *Config, args []string, submissions bool) error {
+	query := strings.Join(args, " ")
+	if query == "" {
+		query = config.ProblemID
+	}
+	if query == "" {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: open [-submissions] <problem id or name>"))
+	}
+
+	problem, err := resolveProblem(config, query)
+	if err != nil {
+		return err
+	}
+
+	page := "task"
+	if submissions {
+		page = "view"
+	}
+	url := fmt.Sprintf("%s/problemset/%s/%s/", config.GetBaseURL(), page, problem.ID)
+
+	label := problem.ID
+	if problem.Name != "" {
+		label += " " + problem.Name
+	}
+	cyan.Printf("🌐 %s: %s\n", label, url)
+
+	if err := openBrowser(url); err != nil {
+		yellow.Printf("⚠️  Could not open a browser: %v\n", err)
+	}
+	return nil
+}
+
+// resolveProblem finds a problem by ID or alias, or by name in the problem list
+func resolveProblem(config *Config, query string) (ProblemInfo, error) {
+	query = resolveAliases(config, query)
+	if _, err := strconv.Atoi(query); err == nil {
+		// IDs need no lookup; the name is shown if the list is cached
+		return ProblemInfo{ID: query, Name: cachedProblemName(config, query)}, nil
+	}
+
+	index, err := LoadProblemsetIndex(config, NewCSESAuth(config))
+	if err != nil {
+		return ProblemInfo{}, withExitCode(ExitFetchError, err)
+	}
+
+	matches := index.Search(query)
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return ProblemInfo{}, withExitCode(ExitUsageError, fmt.Errorf("no problem is called %q", query))
+	}
+
+	names := make([]string, len(matches))
+	for i, problem := range matches {
+		names[i] = fmt.Sprintf("%s %s", problem.ID, problem.Name)
+	}
+	return ProblemInfo{}, withExitCode(ExitUsageError, fmt.Errorf("%q matches several problems: %s", query, strings.Join(names, ", ")))
+}
+
+// openBrowser opens url with the platform's default handler
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.Command("xdg-open", url)
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		return fmt.Errorf("opening a browser is not supported on %s", runtime.GOOS)
+	}
+
+	return cmd.Start()
+}
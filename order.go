@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Supported values for -order
+const (
+	OrderSequential   = "sequential"
+	OrderShuffle      = "shuffle"
+	OrderSlowestFirst = "slowest-first"
+	OrderFailedFirst  = "failed-first"
+)
+
+// validateOrder checks an -order value
+func validateOrder(order string) error {
+	switch order {
+	case OrderSequential, OrderShuffle, OrderSlowestFirst, OrderFailedFirst:
+		return nil
+	}
+	return fmt.Errorf("invalid order %q (use %s, %s, %s or %s)", order,
+		OrderSequential, OrderShuffle, OrderSlowestFirst, OrderFailedFirst)
+}
+
+// executionOrder returns the indices of testCases in the order they should be
+// scheduled. Results are still reported by test number; only which tests
+// start first changes. slowest-first and failed-first use the previous run of
+// this problem from the history, with unknown tests scheduled first.
+func (r *TestRunner) executionOrder(testCases []TestCase) []int {
+	order := make([]int, len(testCases))
+	for i := range order {
+		order[i] = i
+	}
+
+	switch r.config.Order {
+	case OrderShuffle:
+		seed := r.config.ShuffleSeed
+		rand.New(rand.NewSource(seed)).Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+		cyan.Printf("🔀 Shuffled test order (seed %d, reproduce with -shuffle-seed=%d)\n", seed, seed)
+
+	case OrderSlowestFirst, OrderFailedFirst:
+		previous := r.previousRunTests()
+		if previous == nil {
+			if r.config.Verbose {
+				yellow.Printf("⚠️  No previous run of problem %s, using sequential order\n", r.config.ProblemID)
+			}
+			return order
+		}
+
+		sort.SliceStable(order, func(a, b int) bool {
+			prevA, knownA := previous[testCases[order[a]].Number]
+			prevB, knownB := previous[testCases[order[b]].Number]
+			if knownA != knownB {
+				return !knownA
+			}
+			if r.config.Order == OrderFailedFirst {
+				return !prevA.Passed && prevB.Passed
+			}
+			return prevA.Duration > prevB.Duration
+		})
+	}
+
+	return order
+}
+
+// previousRunTests returns the tests of the latest recorded run of the problem, keyed by test number
+func (r *TestRunner) previousRunTests() map[int]TestRecord {
+	record, err := NewHistoryStore(r.config).Latest(func(record *RunRecord) bool {
+		return record.ProblemID == r.config.ProblemID
+	})
+	if err != nil || record == nil {
+		return nil
+	}
+
+	tests := make(map[int]TestRecord)
+	for _, test := range record.Tests {
+		tests[test.Number] = test
+	}
+	return tests
+}
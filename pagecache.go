@@ -0,0 +1,68 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// How long scraped pages are served from the page cache. Statistics move
+// with every submission; task pages and the problem list hardly change.
+const (
+	problemStatsTTL = 10 * time.Minute
+	problemsetTTL   = time.Hour
+	taskPageTTL     = 7 * 24 * time.Hour
+)
+
+// cachedPage is a scraped page stored in the page cache
+type cachedPage struct {
+	URL       string    `json:"url"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Body      string    `json:"body"`
+}
+
+// pageCachePath is where a page is cached. Pages show the logged-in user's
+// status, so the key includes the session file, which differs per account
+// and CSES instance.
+func (a *CSESAuth) pageCachePath(path string) string {
+	sum := sha256.Sum256([]byte(a.sessionFile + "\x00" + a.baseURL + path))
+	return filepath.Join(a.pageCacheDir, hex.EncodeToString(sum[:12])+".json")
+}
+
+// loadCachedPage returns the cached copy of a page if it is younger than ttl
+func (a *CSESAuth) loadCachedPage(path string, ttl time.Duration) (string, bool) {
+	if a.pageCacheDir == "" || a.refreshPages {
+		return "", false
+	}
+	data, err := os.ReadFile(a.pageCachePath(path))
+	if err != nil {
+		return "", false
+	}
+	var page cachedPage
+	if err := json.Unmarshal(data, &page); err != nil || time.Since(page.FetchedAt) > ttl {
+		return "", false
+	}
+	return page.Body, true
+}
+
+// fetchCachedPage serves a page from the page cache while it is younger than
+// ttl, and fetches and stores it otherwise
+func (a *CSESAuth) fetchCachedPage(path, what string, ttl time.Duration) (string, error) {
+	if body, ok := a.loadCachedPage(path, ttl); ok {
+		return body, nil
+	}
+
+	body, err := a.fetchPage(path, what)
+	if err != nil || a.pageCacheDir == "" {
+		return body, err
+	}
+
+	data, err := json.Marshal(cachedPage{URL: a.baseURL + path, FetchedAt: time.Now(), Body: body})
+	if err == nil && os.MkdirAll(a.pageCacheDir, 0755) == nil {
+		os.WriteFile(a.pageCachePath(path), data, 0600)
+	}
+	return body, nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// panicSourceContext is how many source lines are shown around a panicking line
+const panicSourceContext = 2
+
+// maxPanicFrames limits how many solution frames of a traceback are shown
+const maxPanicFrames = 3
+
+// frameLinePattern matches the file:line half of a traceback frame, e.g.
+// "	/home/me/1068.go:12 +0x1d"
+var frameLinePattern = regexp.MustCompile(`^\s+(\S+\.go):(\d+)(?:\s+\+0x[0-9a-f]+)?$`)
+
+// panicFrame is one frame of a Go traceback
+type panicFrame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// parsePanicFrames extracts the stack frames from Go panic output. Each frame
+// is a function line followed by an indented file:line line.
+func parsePanicFrames(stderr string) []panicFrame {
+	var frames []panicFrame
+	lines := strings.Split(stderr, "\n")
+	for i := 1; i < len(lines); i++ {
+		matches := frameLinePattern.FindStringSubmatch(lines[i])
+		if matches == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(matches[2])
+
+		function := strings.TrimSpace(lines[i-1])
+		if paren := strings.LastIndex(function, "("); paren > 0 {
+			function = function[:paren]
+		}
+
+		frames = append(frames, panicFrame{Function: function, File: matches[1], Line: line})
+	}
+	return frames
+}
+
+// solutionFrames keeps the distinct frames located in the solution file, as
+// built on the host or inside the -docker container
+func solutionFrames(frames []panicFrame, solutionPath string) []panicFrame {
+	abs, err := filepath.Abs(solutionPath)
+	if err != nil {
+		abs = solutionPath
+	}
+	inContainer := dockerWorkDir + "/" + filepath.Base(solutionPath)
+
+	seen := make(map[string]bool)
+	var result []panicFrame
+	for _, frame := range frames {
+		if filepath.Clean(frame.File) != abs && frame.File != inContainer {
+			continue
+		}
+		key := fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, frame)
+	}
+	return result
+}
+
+// displayPanicSource prints the solution source around the frames of a panic
+// traceback, so runtime errors can be located without re-running by hand
+func (r *TestRunner) displayPanicSource(stderr string) {
+	frames := solutionFrames(parsePanicFrames(stderr), r.config.FilePath)
+	if len(frames) == 0 {
+		return
+	}
+
+	source, err := os.ReadFile(r.config.FilePath)
+	if err != nil {
+		return
+	}
+	lines := strings.Split(string(source), "\n")
+
+	if len(frames) > maxPanicFrames {
+		frames = frames[:maxPanicFrames]
+	}
+
+	fmt.Println("   🧭 Panic location:")
+	for _, frame := range frames {
+		cyan.Printf("      %s:%d", filepath.Base(r.config.FilePath), frame.Line)
+		fmt.Printf(" in %s\n", frame.Function)
+
+		first := frame.Line - panicSourceContext
+		if first < 1 {
+			first = 1
+		}
+		last := frame.Line + panicSourceContext
+		if last > len(lines) {
+			last = len(lines)
+		}
+
+		for n := first; n <= last; n++ {
+			code := strings.ReplaceAll(lines[n-1], "\t", "    ")
+			if n == frame.Line {
+				red.Printf("      > %4d | %s\n", n, code)
+			} else {
+				fmt.Printf("        %4d | %s\n", n, code)
+			}
+		}
+	}
+}
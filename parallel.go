@@ -0,0 +1,121 @@
+package main
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	// memoryCheckInterval is how often free memory is checked while tests run
+	memoryCheckInterval = 250 * time.Millisecond
+
+	// lowMemoryFraction is the share of total memory below which parallelism is halved;
+	// it is restored one step at a time once twice as much is free again
+	lowMemoryFraction = 0.10
+)
+
+// concurrencyLimiter caps how many pool workers may run a test at once. The
+// limit can be lowered while tests are running.
+type concurrencyLimiter struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+}
+
+func newConcurrencyLimiter(limit int) *concurrencyLimiter {
+	l := &concurrencyLimiter{limit: limit}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *concurrencyLimiter) acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.active >= l.limit {
+		l.cond.Wait()
+	}
+	l.active++
+}
+
+func (l *concurrencyLimiter) release() {
+	l.mu.Lock()
+	l.active--
+	l.mu.Unlock()
+	l.cond.Broadcast()
+}
+
+func (l *concurrencyLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+func (l *concurrencyLimiter) setLimit(limit int) {
+	l.mu.Lock()
+	l.limit = limit
+	l.mu.Unlock()
+	l.cond.Broadcast()
+}
+
+// autoParallelism derives -parallel=0 from the CPU count, capped so that the
+// largest peak memory seen in the previous run of the problem fits in half of
+// the currently free memory
+func (r *TestRunner) autoParallelism() int {
+	workers := runtime.NumCPU()
+
+	var peak int64
+	for _, test := range r.previousRunTests() {
+		if test.MemoryUsage > peak {
+			peak = test.MemoryUsage
+		}
+	}
+
+	if available, _, ok := systemMemory(); ok && peak > 0 {
+		if fit := int(available / 2 / peak); fit < workers {
+			workers = fit
+		}
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
+
+// watchMemoryPressure lowers the limiter while the system is short on memory
+// and raises it back up to max when memory frees up, until done is closed
+func (r *TestRunner) watchMemoryPressure(done <-chan struct{}, limiter *concurrencyLimiter, max int) {
+	if _, _, ok := systemMemory(); !ok {
+		return
+	}
+
+	ticker := time.NewTicker(memoryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		available, total, ok := systemMemory()
+		if !ok {
+			return
+		}
+
+		limit := limiter.Limit()
+		switch {
+		case float64(available) < lowMemoryFraction*float64(total) && limit > 1:
+			limiter.setLimit(limit / 2)
+			yellow.Printf("⚠️  Low memory (%s free), reducing parallelism to %d\n", formatBytes(available), limit/2)
+		case float64(available) > 2*lowMemoryFraction*float64(total) && limit < max:
+			limiter.setLimit(limit + 1)
+			if r.config.Verbose {
+				cyan.Printf("📈 Memory recovered, parallelism back to %d\n", limit+1)
+			}
+		}
+	}
+}
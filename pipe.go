@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// handleExec compiles the solution and runs it once on stdin (or -input),
+// printing its raw output. Status messages go to stderr so the output can be piped.
+func handleExec(config *Config, inputPath string) error {
+	color.Output = redactingWriter{os.Stderr}
+
+	if err := validateSolutionFile(config); err != nil {
+		return withExitCode(ExitUsageError, err)
+	}
+
+	if err := validateCompiler(config); err != nil {
+		return withExitCode(ExitUsageError, err)
+	}
+
+	var input io.Reader = os.Stdin
+	if inputPath != "" {
+		file, err := os.Open(inputPath)
+		if err != nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("failed to open input file: %w", err))
+		}
+		defer file.Close()
+		input = file
+	}
+
+	compiler := NewGoCompiler(config)
+	if err := compiler.ValidateGo(); err != nil {
+		return withExitCode(ExitCompileError, fmt.Errorf("Go validation failed: %w", err))
+	}
+
+	if config.Verbose {
+		yellow.Println("🔨 Compiling Go solution...")
+	}
+	executablePath, err := compiler.Compile()
+	if err != nil {
+		var compileErr *CompileError
+		if errors.As(err, &compileErr) {
+			displayCompileError(config, compileErr)
+		}
+		return withExitCode(ExitCompileError, fmt.Errorf("compilation failed: %w", err))
+	}
+	defer os.Remove(executablePath)
+
+	if inputPath == "" && isTerminal(os.Stdin) {
+		cyan.Println("⌨️  Reading input from the terminal, finish with Ctrl-D")
+	}
+
+	var sandbox *DockerSandbox
+	if config.Docker != "" {
+		if sandbox, err = StartDockerSandbox(config); err != nil {
+			return err
+		}
+		defer sandbox.Stop()
+	}
+
+	cmd := solutionCommand(context.Background(), config, sandbox, executablePath, 0)
+	cmd.Stdin = input
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	startTime := time.Now()
+	err = cmd.Run()
+	duration := time.Since(startTime)
+
+	if err != nil {
+		return fmt.Errorf("solution failed after %.2fms: %w", duration.Seconds()*1000, err)
+	}
+
+	if config.Verbose {
+		green.Printf("✅ Finished in %.2fms\n", duration.Seconds()*1000)
+	}
+	return nil
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"sync"
+)
+
+// maxInFlightTestData caps the test data (input, expected and captured output)
+// held by tests running at the same time
+const maxInFlightTestData = 256 << 20
+
+// dataBudget is a weighted semaphore over bytes of test data. A test larger
+// than the whole budget still runs, but only on its own.
+type dataBudget struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	capacity  int64
+	available int64
+}
+
+func newDataBudget(capacity int64) *dataBudget {
+	b := &dataBudget{capacity: capacity, available: capacity}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// acquire blocks until n bytes are free and returns the amount to release
+func (b *dataBudget) acquire(n int64) int64 {
+	if n > b.capacity {
+		n = b.capacity
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.available < n {
+		b.cond.Wait()
+	}
+	b.available -= n
+	return n
+}
+
+func (b *dataBudget) release(n int64) {
+	b.mu.Lock()
+	b.available += n
+	b.mu.Unlock()
+	b.cond.Broadcast()
+}
+
+// testDataWeight estimates the memory a running test needs: its input and
+// expected output, and as much again for the captured stdout and its copies
+func testDataWeight(tc TestCase) int64 {
+	size := tc.DataSize()
+	if size <= 0 {
+		return 1
+	}
+	return 2 * size
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// penaltyPerRejection is added to a solved problem's time for every rejected
+// run before the accepted one, as in ICPC-style contests
+const penaltyPerRejection = 20 * time.Minute
+
+// VirtualContest is a timed set of problems started with `practice`. Runs of
+// those problems inside the window count as submissions.
+type VirtualContest struct {
+	Problems  []string      `json:"problems"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// Deadline is when the contest window closes
+func (v *VirtualContest) Deadline() time.Time {
+	return v.StartedAt.Add(v.Duration)
+}
+
+// Includes reports whether problemID is part of the contest
+func (v *VirtualContest) Includes(problemID string) bool {
+	for _, id := range v.Problems {
+		if id == problemID {
+			return true
+		}
+	}
+	return false
+}
+
+// handlePractice implements `practice -problems=... -duration=...` (start),
+// `practice` (standings) and `practice end`
+func handlePractice(config *Config, args []string, problems, duration string) error {
+	path := config.GetVirtualContestPath()
+
+	contest, err := loadVirtualContest(path)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 && problems != "" {
+		return startVirtualContest(path, contest, problems, duration)
+	}
+
+	if len(args) == 0 || args[0] == "status" {
+		if contest == nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("no contest is running (use practice -problems=1068,1083 -duration=2h)"))
+		}
+
+		return showContestResult(config, contest)
+	}
+
+	if args[0] == "end" {
+		if contest == nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("no contest is running"))
+		}
+
+		if err := showContestResult(config, contest); err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to end contest: %w", err)
+		}
+		green.Println("🏁 Contest ended")
+		return nil
+	}
+
+	return withExitCode(ExitUsageError, fmt.Errorf("unknown practice subcommand: %s", args[0]))
+}
+
+func startVirtualContest(path string, running *VirtualContest, problems, duration string) error {
+	if running != nil && time.Now().Before(running.Deadline()) {
+		return withExitCode(ExitUsageError, fmt.Errorf("a contest is already running until %s (end it with `practice end`)",
+			running.Deadline().Local().Format("15:04")))
+	}
+
+	length, err := time.ParseDuration(duration)
+	if err != nil || length <= 0 {
+		return withExitCode(ExitUsageError, fmt.Errorf("invalid duration %q", duration))
+	}
+
+	contest := &VirtualContest{StartedAt: time.Now(), Duration: length}
+	for _, id := range parseList(problems) {
+		if _, err := strconv.Atoi(id); err != nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("invalid problem ID %s", id))
+		}
+		if !contest.Includes(id) {
+			contest.Problems = append(contest.Problems, id)
+		}
+	}
+
+	if len(contest.Problems) == 0 {
+		return withExitCode(ExitUsageError, fmt.Errorf("-problems lists no problems"))
+	}
+
+	if err := saveVirtualContest(path, contest); err != nil {
+		return err
+	}
+
+	green.Printf("⏱️  Contest started: %d problem(s), ends at %s\n", len(contest.Problems), contest.Deadline().Format("15:04"))
+	cyan.Printf("   Problems: %v\n", contest.Problems)
+	fmt.Printf("   Every rejected run before an accepted one adds %d minutes of penalty\n", int(penaltyPerRejection.Minutes()))
+	return nil
+}
+
+func loadVirtualContest(path string) (*VirtualContest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contest: %w", err)
+	}
+
+	var contest VirtualContest
+	if err := json.Unmarshal(data, &contest); err != nil {
+		return nil, fmt.Errorf("failed to parse contest %s: %w", path, err)
+	}
+	return &contest, nil
+}
+
+func saveVirtualContest(path string, contest *VirtualContest) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(contest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal contest: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// showContestClock is printed before a run while a contest is active
+func showContestClock(config *Config) {
+	contest, err := loadVirtualContest(config.GetVirtualContestPath())
+	if err != nil || contest == nil {
+		return
+	}
+
+	switch remaining := time.Until(contest.Deadline()); {
+	case remaining <= 0:
+		yellow.Println("⏱️  The contest is over; this run is not counted (see `practice end`)")
+	case !contest.Includes(config.ProblemID):
+		yellow.Printf("⏱️  Problem %s is not part of the contest (%s left)\n", config.ProblemID, formatElapsed(remaining))
+	default:
+		cyan.Printf("⏱️  Contest: %s left\n", formatElapsed(remaining))
+	}
+}
+
+// showContestResult prints the standings: problems solved, then the penalty
+// (minutes to each accepted run plus penaltyPerRejection per rejected run)
+func showContestResult(config *Config, contest *VirtualContest) error {
+	records, err := NewHistoryStore(config).List()
+	if err != nil {
+		return err
+	}
+
+	var counted []*RunRecord
+	for _, record := range records {
+		if contest.Includes(record.ProblemID) && record.StartedAt.Before(contest.Deadline()) {
+			counted = append(counted, record)
+		}
+	}
+
+	scores := make(map[string]ProblemScore)
+	for _, score := range buildScoreboard(counted, contest.StartedAt) {
+		scores[score.ProblemID] = score
+	}
+
+	if remaining := time.Until(contest.Deadline()); remaining > 0 {
+		cyan.Printf("🏆 Virtual contest, %s of %s left\n\n", formatElapsed(remaining), contest.Duration)
+	} else {
+		cyan.Printf("🏆 Virtual contest result (%s, ended %s)\n\n", contest.Duration, contest.Deadline().Local().Format("2006-01-02 15:04"))
+	}
+
+	solved := 0
+	var penalty time.Duration
+	fmt.Printf("%-8s %-9s %-10s %-9s %s\n", "PROBLEM", "RESULT", "SOLVED AT", "REJECTED", "PENALTY")
+	for _, id := range contest.Problems {
+		score, attempted := scores[id]
+		if !attempted {
+			fmt.Printf("%-8s %-9s %-10s %-9s %s\n", id, "-", "-", "-", "-")
+			continue
+		}
+
+		if !score.Solved {
+			fmt.Printf("%-8s ", id)
+			red.Printf("%-9s", fmt.Sprintf("%d/%d", score.BestPassed, score.BestTotal))
+			fmt.Printf(" %-10s %-9d %s\n", "-", score.Runs, "-")
+			continue
+		}
+
+		solved++
+		elapsed := score.SolvedAt.Sub(contest.StartedAt)
+		problemPenalty := elapsed.Truncate(time.Minute) + time.Duration(score.FailedBeforeSolve)*penaltyPerRejection
+		penalty += problemPenalty
+
+		fmt.Printf("%-8s ", id)
+		green.Printf("%-9s", "AC")
+		fmt.Printf(" %-10s %-9d %d\n", "+"+formatElapsed(elapsed), score.FailedBeforeSolve, int(problemPenalty.Minutes()))
+	}
+
+	fmt.Println()
+	cyan.Printf("📊 Solved %d/%d, penalty %d\n", solved, len(contest.Problems), int(penalty.Minutes()))
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// limitsFile caches the limits of a problem's task page next to its tests
+const limitsFile = ".limits.json"
+
+// riskyMargin is the share of a limit above which an accepted run may not be
+// accepted on the judge, whose machines differ from the local one
+const riskyMargin = 0.8
+
+var (
+	taskTimeLimitPattern   = regexp.MustCompile(`Time limit:\s*(?:</b>)?\s*([0-9]+(?:\.[0-9]+)?)\s*s`)
+	taskMemoryLimitPattern = regexp.MustCompile(`Memory limit:\s*(?:</b>)?\s*([0-9]+)\s*MB`)
+)
+
+// ProblemLimits are the time and memory limits of a problem
+type ProblemLimits struct {
+	Time   time.Duration `json:"time_limit_ns"`
+	Memory int64         `json:"memory_limit_bytes"`
+
+	// Scraped is false when the task page was unavailable and CSES's usual
+	// limits are assumed
+	Scraped bool `json:"-"`
+}
+
+// defaultLimits are the limits of almost every CSES problem
+var defaultLimits = ProblemLimits{Time: time.Second, Memory: 512 << 20}
+
+// parseTaskLimits reads the constraints block of a task page
+func parseTaskLimits(page string) (ProblemLimits, bool) {
+	timeMatch := taskTimeLimitPattern.FindStringSubmatch(page)
+	memoryMatch := taskMemoryLimitPattern.FindStringSubmatch(page)
+	if timeMatch == nil || memoryMatch == nil {
+		return ProblemLimits{}, false
+	}
+
+	seconds, err := strconv.ParseFloat(timeMatch[1], 64)
+	if err != nil {
+		return ProblemLimits{}, false
+	}
+	megabytes, err := strconv.ParseInt(memoryMatch[1], 10, 64)
+	if err != nil {
+		return ProblemLimits{}, false
+	}
+	return ProblemLimits{Time: time.Duration(seconds * float64(time.Second)), Memory: megabytes << 20, Scraped: true}, true
+}
+
+// loadProblemLimits returns the limits of the problem's task page, cached with
+// its tests. When the page cannot be read the usual CSES limits are returned.
+func loadProblemLimits(config *Config, auth *CSESAuth, problemID string) ProblemLimits {
+	path := filepath.Join(config.CacheDir, problemID, limitsFile)
+	if data, err := os.ReadFile(path); err == nil {
+		var limits ProblemLimits
+		if json.Unmarshal(data, &limits) == nil && limits.Time > 0 {
+			limits.Scraped = true
+			return limits
+		}
+	}
+
+	page, err := auth.FetchTask(problemID)
+	if err != nil {
+		return defaultLimits
+	}
+	limits, ok := parseTaskLimits(page)
+	if !ok {
+		return defaultLimits
+	}
+
+	if data, err := json.MarshalIndent(limits, "", "  "); err == nil {
+		os.WriteFile(path, data, 0644)
+	}
+	return limits
+}
+
+// VerdictPrediction is the verdict CSES is likely to give the solution
+type VerdictPrediction struct {
+	Verdict Verdict
+	Reason  string
+	Risky   bool // accepted locally, but close to a limit
+}
+
+// predictVerdict combines the local results with the time and memory margins
+// to the problem's limits
+func predictVerdict(results []TestResult, limits ProblemLimits) VerdictPrediction {
+	var slowest time.Duration
+	var peakMemory int64
+	for _, result := range results {
+		if !result.Passed {
+			verdict := result.Verdict
+			if verdict == VerdictSanitizer {
+				// CSES runs no sanitizer; the memory error crashes there or goes unnoticed
+				verdict = VerdictRuntimeError
+			}
+			return VerdictPrediction{Verdict: verdict, Reason: fmt.Sprintf("test %d fails locally", result.TestNumber)}
+		}
+		slowest = max(slowest, result.Duration)
+		peakMemory = max(peakMemory, result.MemoryUsage)
+	}
+
+	timeShare := float64(slowest) / float64(limits.Time)
+	reason := fmt.Sprintf("slowest test %.2fms of %s (%.0f%%)", slowest.Seconds()*1000, limits.Time, timeShare*100)
+	memoryShare := 0.0
+	if peakMemory > 0 {
+		memoryShare = float64(peakMemory) / float64(limits.Memory)
+		reason += fmt.Sprintf(", peak memory %s of %s (%.0f%%)", formatBytes(peakMemory), formatBytes(limits.Memory), memoryShare*100)
+	}
+	if !limits.Scraped {
+		reason += ", assuming the usual CSES limits"
+	}
+
+	switch {
+	case timeShare > 1:
+		return VerdictPrediction{Verdict: VerdictTimeLimit, Reason: reason}
+	case memoryShare > 1:
+		return VerdictPrediction{Verdict: VerdictMemoryLimit, Reason: reason}
+	}
+	return VerdictPrediction{Verdict: VerdictAccepted, Reason: reason, Risky: timeShare > riskyMargin || memoryShare > riskyMargin}
+}
+
+// displayPrediction prints the likely verdict of a full run on CSES
+func (r *TestRunner) displayPrediction(results []TestResult) {
+	if r.config.Test > 0 || r.config.Tests != nil || isImportedProblem(r.config.ProblemID) {
+		return
+	}
+
+	prediction := predictVerdict(results, loadProblemLimits(r.config, r.auth, r.config.ProblemID))
+	r.prediction = &prediction
+
+	switch {
+	case prediction.Verdict != VerdictAccepted:
+		red.Printf("🔮 Likely verdict on CSES: %s (%s)\n", prediction.Verdict.Description(), prediction.Reason)
+	case prediction.Risky:
+		yellow.Printf("🔮 Likely verdict on CSES: %s, but close to a limit (%s)\n", prediction.Verdict.Description(), prediction.Reason)
+	default:
+		green.Printf("🔮 Likely verdict on CSES: %s (%s)\n", prediction.Verdict.Description(), prediction.Reason)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// prefetchWorkers is how many problems' tests are downloaded at once
+const prefetchWorkers = 4
+
+// prefetchTests downloads the tests of every problem that has none cached yet,
+// several at a time over the shared session, so the runs that follow start
+// from the cache. Failures are only reported; the run of that problem retries.
+func prefetchTests(config *Config, auth *CSESAuth, problemIDs []string) {
+	fetcher := NewTestCaseFetcher(config, auth)
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, id := range problemIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if cached, err := fetcher.loadCachedTestCases(filepath.Join(config.CacheDir, id)); err != nil || len(cached) == 0 {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) < 2 {
+		return // nothing to overlap
+	}
+
+	if err := auth.EnsureAuthenticated(); err != nil {
+		yellow.Printf("⚠️  Not downloading tests ahead of the runs: %v\n", err)
+		return
+	}
+
+	yellow.Printf("📥 Downloading the tests of %d problems (%d at a time)...\n", len(missing), min(prefetchWorkers, len(missing)))
+	bars := newMultiBar(missing)
+
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := range missing {
+			jobs <- i
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(prefetchWorkers, len(missing)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fetcher := NewTestCaseFetcher(config, auth)
+				fetcher.onDownload = func(read, total int64) { bars.update(i, read, total) }
+				_, err := fetcher.FetchTestCases(missing[i])
+				bars.finish(i, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// multiBar shows one download progress line per problem. On a terminal the
+// lines are redrawn in place; otherwise a line is printed as each one finishes.
+type multiBar struct {
+	mu          sync.Mutex
+	labels      []string
+	lines       []string
+	interactive bool
+}
+
+func newMultiBar(labels []string) *multiBar {
+	bars := &multiBar{labels: labels, lines: make([]string, len(labels))}
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		bars.interactive = true
+	}
+
+	for i := range labels {
+		bars.lines[i] = bars.format(i, "waiting")
+	}
+	if bars.interactive {
+		for _, line := range bars.lines {
+			fmt.Println(line)
+		}
+	}
+	return bars
+}
+
+func (b *multiBar) format(i int, status string) string {
+	return fmt.Sprintf("   %-8s %s", b.labels[i], status)
+}
+
+// update sets the progress of line i; total is -1 when the size is unknown
+func (b *multiBar) update(i int, read, total int64) {
+	status := formatBytes(read)
+	if total > 0 {
+		fraction := float64(read) / float64(total)
+		status = fmt.Sprintf("%s %3.0f%% %s", progressBar(fraction, 20), fraction*100, formatBytes(total))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lines[i] = b.format(i, status)
+	b.redraw()
+}
+
+// finish marks line i as done, or failed with err
+func (b *multiBar) finish(i int, err error) {
+	status := "✅ done"
+	if err != nil {
+		status = "❌ " + strings.SplitN(err.Error(), "\n", 2)[0]
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lines[i] = b.format(i, status)
+	if b.interactive {
+		b.redraw()
+	} else {
+		fmt.Println(b.lines[i])
+	}
+}
+
+// redraw moves the cursor back over the lines and prints them again
+func (b *multiBar) redraw() {
+	if !b.interactive {
+		return
+	}
+	fmt.Printf("\033[%dA", len(b.lines))
+	for _, line := range b.lines {
+		fmt.Printf("\033[2K%s\n", line)
+	}
+}
+
+// progressBar renders fraction (0 to 1) as a bar of width characters
+func progressBar(fraction float64, width int) string {
+	filled := min(max(int(fraction*float64(width)), 0), width)
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", width-filled) + "]"
+}
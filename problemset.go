@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// problemsetMaxAge is how long the cached problem list is used before it is refetched
+const problemsetMaxAge = 24 * time.Hour
+
+var (
+	categoryPattern = regexp.MustCompile(`<h2>([^<]+)</h2>`)
+	taskPattern     = regexp.MustCompile(`<li class="task"><a href="/problemset/task/(\d+)/?">([^<]*)</a>` +
+		`(?:<span class="detail">(\d+) / (\d+)</span>)?(?:<span class="task-score icon ?(\w*)"></span>)?`)
+)
+
+// ProblemInfo is one task of the CSES problem list
+type ProblemInfo struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Category  string `json:"category"`
+	Solvers   int    `json:"solvers"`
+	Attempts  int    `json:"attempts"`
+	Solved    bool   `json:"solved,omitempty"`
+	Attempted bool   `json:"attempted,omitempty"`
+}
+
+// ProblemsetIndex is the cached problem list, in the order CSES shows it
+type ProblemsetIndex struct {
+	FetchedAt time.Time     `json:"fetched_at"`
+	Problems  []ProblemInfo `json:"problems"`
+}
+
+// parseProblemset extracts the tasks of the problem list page, each under the
+// nearest preceding category heading
+func parseProblemset(page string) []ProblemInfo {
+	headings := categoryPattern.FindAllStringSubmatchIndex(page, -1)
+
+	var problems []ProblemInfo
+	for _, match := range taskPattern.FindAllStringSubmatchIndex(page, -1) {
+		category := ""
+		for _, heading := range headings {
+			if heading[0] > match[0] {
+				break
+			}
+			category = html.UnescapeString(page[heading[2]:heading[3]])
+		}
+
+		group := func(i int) string {
+			if match[2*i] < 0 {
+				return ""
+			}
+			return page[match[2*i]:match[2*i+1]]
+		}
+
+		solvers, _ := strconv.Atoi(group(3))
+		attempts, _ := strconv.Atoi(group(4))
+		problems = append(problems, ProblemInfo{
+			ID:        group(1),
+			Name:      html.UnescapeString(group(2)),
+			Category:  category,
+			Solvers:   solvers,
+			Attempts:  attempts,
+			Solved:    group(5) == "full",
+			Attempted: group(5) != "",
+		})
+	}
+
+	return problems
+}
+
+// LoadProblemsetIndex returns the cached problem list, refetching it (with the
+// user's solve status when logged in) once it is older than problemsetMaxAge
+func LoadProblemsetIndex(config *Config, auth *CSESAuth) (*ProblemsetIndex, error) {
+	path := config.GetProblemsetPath()
+
+	var cached *ProblemsetIndex
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		cached = &ProblemsetIndex{}
+		if err := json.Unmarshal(data, cached); err != nil {
+			cached = nil
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to read problemset index: %w", err)
+	}
+
+	if cached != nil && time.Since(cached.FetchedAt) < problemsetMaxAge {
+		return cached, nil
+	}
+
+	if err := auth.EnsureAuthenticated(); err != nil {
+		yellow.Printf("⚠️  Not logged in (%v); solve status comes from local history only\n", err)
+	}
+
+	page, err := auth.FetchProblemset()
+	if err != nil {
+		if cached != nil {
+			yellow.Printf("⚠️  Using the problem list from %s: %v\n", cached.FetchedAt.Local().Format("2006-01-02"), err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	index := &ProblemsetIndex{FetchedAt: time.Now(), Problems: parseProblemset(page)}
+	if len(index.Problems) == 0 {
+		return nil, fmt.Errorf("no problems found on the problemset page")
+	}
+
+	if data, err := json.MarshalIndent(index, "", "  "); err == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			os.WriteFile(path, data, 0644)
+		}
+	}
+
+	return index, nil
+}
+
+// Find returns the problem with the given ID
+func (p *ProblemsetIndex) Find(id string) (ProblemInfo, bool) {
+	for _, problem := range p.Problems {
+		if problem.ID == id {
+			return problem, true
+		}
+	}
+	return ProblemInfo{}, false
+}
+
+// Search returns the problems whose name is query, ignoring case, or if there
+// is none, those whose name contains it
+func (p *ProblemsetIndex) Search(query string) []ProblemInfo {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	var exact, partial []ProblemInfo
+	for _, problem := range p.Problems {
+		name := strings.ToLower(problem.Name)
+		switch {
+		case name == query:
+			exact = append(exact, problem)
+		case strings.Contains(name, query):
+			partial = append(partial, problem)
+		}
+	}
+
+	if len(exact) > 0 {
+		return exact
+	}
+	return partial
+}
+
+// Categories returns the category names in page order
+func (p *ProblemsetIndex) Categories() []string {
+	var categories []string
+	seen := make(map[string]bool)
+	for _, problem := range p.Problems {
+		if !seen[problem.Category] {
+			seen[problem.Category] = true
+			categories = append(categories, problem.Category)
+		}
+	}
+	return categories
+}
+
+// matchCategory finds the category whose name contains query, ignoring case
+func (p *ProblemsetIndex) matchCategory(query string) (string, error) {
+	var matches []string
+	for _, category := range p.Categories() {
+		if strings.Contains(strings.ToLower(category), strings.ToLower(query)) {
+			matches = append(matches, category)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return "", fmt.Errorf("no category matches %q (categories: %s)", query, strings.Join(p.Categories(), ", "))
+	}
+	return "", fmt.Errorf("%q matches several categories: %s", query, strings.Join(matches, ", "))
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	statsSectionPattern = regexp.MustCompile(`(?s)<h[1-4][^>]*>([^<]+)</h[1-4]>\s*<table[^>]*>(.*?)</table>`)
+	statsRowPattern     = regexp.MustCompile(`(?s)<tr[^>]*>(.*?)</tr>`)
+	statsCellPattern    = regexp.MustCompile(`(?s)<td[^>]*>(.*?)</td>`)
+	statsTagPattern     = regexp.MustCompile(`<[^>]+>`)
+	statsTimePattern    = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?) ?s$`)
+)
+
+// ProblemStats is what the statistics page of a problem shows
+type ProblemStats struct {
+	General [][2]string    // label and value rows, in page order
+	Fastest []FastSolution // fastest solutions, fastest first
+}
+
+// FastSolution is one row of the fastest solutions table
+type FastSolution struct {
+	User     string
+	Time     float64 // seconds
+	Language string
+}
+
+// parseProblemStats extracts the tables of a problem's statistics page. Rows
+// of the "fastest" table are recognized by their time cell ("0.01 s"), the
+// other cells being the rank, the user and the language.
+func parseProblemStats(page string) ProblemStats {
+	var stats ProblemStats
+	for _, section := range statsSectionPattern.FindAllStringSubmatch(page, -1) {
+		heading := strings.ToLower(section[1])
+		for _, row := range statsRowPattern.FindAllStringSubmatch(section[2], -1) {
+			var cells []string
+			for _, cell := range statsCellPattern.FindAllStringSubmatch(row[1], -1) {
+				cells = append(cells, strings.TrimSpace(html.UnescapeString(statsTagPattern.ReplaceAllString(cell[1], ""))))
+			}
+
+			switch {
+			case strings.Contains(heading, "fastest"):
+				if solution, ok := parseFastSolution(cells); ok {
+					stats.Fastest = append(stats.Fastest, solution)
+				}
+			case !strings.Contains(heading, "shortest") && len(cells) == 2:
+				stats.General = append(stats.General, [2]string{strings.TrimSuffix(cells[0], ":"), cells[1]})
+			}
+		}
+	}
+
+	sort.SliceStable(stats.Fastest, func(i, j int) bool { return stats.Fastest[i].Time < stats.Fastest[j].Time })
+	return stats
+}
+
+func parseFastSolution(cells []string) (FastSolution, bool) {
+	var solution FastSolution
+	found := false
+	var others []string
+	for _, cell := range cells {
+		if matches := statsTimePattern.FindStringSubmatch(cell); matches != nil && !found {
+			solution.Time, _ = strconv.ParseFloat(matches[1], 64)
+			found = true
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimSuffix(cell, ".")); err == nil {
+			continue // rank
+		}
+		others = append(others, cell)
+	}
+	if len(others) > 0 {
+		solution.User = others[0]
+	}
+	if len(others) > 1 {
+		solution.Language = others[len(others)-1]
+	}
+	return solution, found
+}
+
+// showProblemStats implements `stats -problem=<id>`: the CSES statistics of a
+// problem, with the slowest test of the latest local all-pass run ranked
+// against the fastest solutions
+func showProblemStats(config *Config, problemID string) error {
+	page, err := NewCSESAuth(config).FetchProblemStats(problemID)
+	if err != nil {
+		return withExitCode(ExitFetchError, err)
+	}
+	stats := parseProblemStats(page)
+
+	problem := lookupProblem(config, problemID)
+	title := problem.ID
+	if problem.Name != "" {
+		title += " " + problem.Name
+	}
+	cyan.Printf("📈 Statistics of %s\n", title)
+	for _, row := range stats.General {
+		fmt.Printf("   %-24s %s\n", row[0]+":", row[1])
+	}
+	if problem.Attempts > 0 {
+		fmt.Printf("   %-24s %.1f%% (%d of %d users who tried solved it)\n", "Acceptance rate:",
+			float64(problem.Solvers)/float64(problem.Attempts)*100, problem.Solvers, problem.Attempts)
+	}
+
+	if len(stats.Fastest) == 0 {
+		yellow.Println("⚠️  The page lists no fastest solutions")
+		return nil
+	}
+
+	fmt.Printf("\n%-4s %-20s %8s  %s\n", "#", "USER", "TIME", "LANGUAGE")
+	for i, solution := range stats.Fastest {
+		if i == 10 {
+			fmt.Printf("     ... %d more\n", len(stats.Fastest)-i)
+			break
+		}
+		fmt.Printf("%-4d %-20s %7.2fs  %s\n", i+1, solution.User, solution.Time, solution.Language)
+	}
+
+	store := NewHistoryStore(config)
+	records, err := store.List()
+	if err != nil {
+		return err
+	}
+	var latest *RunRecord
+	for _, record := range records {
+		if record.ProblemID == problemID && record.AllPassed() {
+			latest = record
+		}
+	}
+	fmt.Println()
+	if latest == nil {
+		yellow.Printf("⚠️  No all-pass run of problem %s to compare with yet\n", problemID)
+		return nil
+	}
+	if latest, err = store.Load(latest.ID); err != nil {
+		return err
+	}
+
+	// CSES reports the time of the slowest test, so compare like with like
+	var slowest float64
+	for _, test := range latest.Tests {
+		slowest = max(slowest, test.Duration/1000)
+	}
+	rank := sort.Search(len(stats.Fastest), func(i int) bool { return stats.Fastest[i].Time > slowest }) + 1
+	fastest := stats.Fastest[0].Time
+
+	message := fmt.Sprintf("⏱️  Your slowest test took %.2fs locally (run of %s)", slowest, latest.StartedAt.Local().Format("2006-01-02 15:04"))
+	switch {
+	case rank <= len(stats.Fastest):
+		green.Printf("%s: that would rank #%d of the %d listed\n", message, rank, len(stats.Fastest))
+	default:
+		yellow.Printf("%s: slower than all %d listed\n", message, len(stats.Fastest))
+	}
+	if fastest > 0 {
+		fmt.Printf("   %.1f× the fastest solution (%.2fs)\n", slowest/fastest, fastest)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// The race detector slows a solution down about this much, so its runs get
+// this many times the time limit
+const raceSlowdown = 10
+
+// raceAccessPattern matches the first lines of an access in a race report:
+// "Read at 0x... by goroutine 8:" and its innermost frame "  main.add()"
+var raceAccessPattern = regexp.MustCompile(`(?m)^(Previous )?(read|write|Read|Write) at 0x[0-9a-f]+ by (?:main )?goroutine(?: \d+)?:\n\s+(\S+?)(?:\(.*\))?\n\s+(\S+):(\d+)`)
+
+// RaceReport is what the race pass found in one test
+type RaceReport struct {
+	TestNumber int
+	Races      []string // one line per race, the two conflicting accesses
+	Err        string   // set when the race build failed for another reason
+}
+
+// raceConfig is the configuration of the race pass: the race build, one run
+// per test and a time limit scaled to the detector's overhead
+func raceConfig(config *Config) *Config {
+	race := *config
+	race.Race, race.Checks = true, ""
+	race.Repeat, race.Warmup, race.GCStats = 1, false, false
+	race.Timeout = (config.GetTimeout() * raceSlowdown).String()
+	return &race
+}
+
+// withoutRace is the configuration the tests are timed with under -race: an
+// ordinary build, as the race build's timings say nothing about the judge's
+func (c *Config) withoutRace() *Config {
+	timed := *c
+	timed.Race = false
+	return &timed
+}
+
+// raceSubset picks the tests of the race pass, the -race-tests smallest, in
+// test order
+func raceSubset(config *Config, testCases []TestCase) []TestCase {
+	if config.RaceTests <= 0 || config.RaceTests >= len(testCases) {
+		return testCases
+	}
+	size := func(tc TestCase) int64 {
+		if tc.Size > 0 || tc.lazy {
+			return tc.Size
+		}
+		return int64(len(tc.Input))
+	}
+	subset := slices.Clone(testCases)
+	slices.SortStableFunc(subset, func(a, b TestCase) int { return cmp.Compare(size(a), size(b)) })
+	subset = subset[:config.RaceTests]
+	sortTestCases(subset)
+	return subset
+}
+
+// parseRaces describes each data race the race detector reported in stderr
+func parseRaces(stderr string) []string {
+	var races []string
+	for _, report := range strings.Split(stderr, "WARNING: DATA RACE")[1:] {
+		var accesses []string
+		for _, match := range raceAccessPattern.FindAllStringSubmatch(report, 2) {
+			access := strings.ToLower(match[2])
+			if match[1] != "" {
+				access = "previous " + access
+			}
+			accesses = append(accesses, fmt.Sprintf("%s in %s (%s:%s)", access, match[3], filepath.Base(match[4]), match[5]))
+		}
+		if len(accesses) == 0 {
+			accesses = []string{"unknown accesses"}
+		}
+		races = append(races, strings.Join(accesses, " vs "))
+	}
+	return races
+}
+
+// runRacePass builds the solution with the race detector and runs a subset of
+// the tests with it. The reports are kept apart from the test results, so the
+// verdicts and timings stay those of the ordinary build.
+func (r *TestRunner) runRacePass(ctx context.Context, testCases []TestCase) ([]RaceReport, error) {
+	config := raceConfig(r.config)
+	subset := raceSubset(config, testCases)
+	numbers := make([]int, len(subset))
+	for i, tc := range subset {
+		numbers[i] = tc.Number
+	}
+
+	yellow.Printf("🏁 Building with the race detector for test(s) %s...\n", formatTestNumbers(numbers))
+	executablePath, err := NewGoCompiler(config).Compile()
+	if err != nil {
+		return nil, fmt.Errorf("race build failed: %w", err)
+	}
+	defer os.Remove(executablePath)
+
+	executor := NewTestExecutor(config)
+	executor.sandbox = r.executor.sandbox
+	var reports []RaceReport
+	for _, testCase := range subset {
+		testCtx, cancel := context.WithTimeout(ctx, config.GetTimeout())
+		result := executor.Execute(testCtx, executablePath, testCase)
+		cancel()
+
+		report := RaceReport{TestNumber: testCase.Number, Races: parseRaces(result.Stderr)}
+		if len(report.Races) == 0 && !result.Passed && result.Verdict != VerdictWrongAnswer {
+			report.Err = result.Error
+		}
+		reports = append(reports, report)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return reports, nil
+}
+
+// displayRaces prints the findings of the race pass after the test summary.
+// Races found in several tests are listed once.
+func displayRaces(reports []RaceReport) {
+	if reports == nil {
+		return
+	}
+	fmt.Println("\n" + strings.Repeat("-", 40))
+	white.Printf("🏁 RACE DETECTOR (%dx time limit, not counted in the verdicts)\n", raceSlowdown)
+	fmt.Println(strings.Repeat("-", 40))
+
+	var clean, racy []int
+	var races []string
+	foundIn := make(map[string][]int)
+	for _, report := range reports {
+		switch {
+		case len(report.Races) > 0:
+			racy = append(racy, report.TestNumber)
+			for _, race := range report.Races {
+				if _, seen := foundIn[race]; !seen {
+					races = append(races, race)
+				}
+				if tests := foundIn[race]; len(tests) == 0 || tests[len(tests)-1] != report.TestNumber {
+					foundIn[race] = append(tests, report.TestNumber)
+				}
+			}
+		case report.Err != "":
+			yellow.Printf("⚠️  Test %d did not finish with the race detector: %s\n", report.TestNumber, report.Err)
+		default:
+			clean = append(clean, report.TestNumber)
+		}
+	}
+
+	if len(clean) > 0 {
+		green.Printf("✅ No data races in test(s) %s\n", formatTestNumbers(clean))
+	}
+	if len(racy) > 0 {
+		red.Printf("❌ %d data race(s) in test(s) %s:\n", len(races), formatTestNumbers(racy))
+		for _, race := range races {
+			fmt.Printf("   • %s [test(s) %s]\n", race, formatTestNumbers(foundIn[race]))
+		}
+	}
+}
+
+// raceFailure turns races found by the race pass into the run's error
+func raceFailure(reports []RaceReport) error {
+	var racy []int
+	for _, report := range reports {
+		if len(report.Races) > 0 {
+			racy = append(racy, report.TestNumber)
+		}
+	}
+	if len(racy) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: data races in test(s) %s", ErrTestsFailed, formatTestNumbers(racy))
+}
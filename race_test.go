@@ -0,0 +1,56 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+const raceStderr = `==================
+WARNING: DATA RACE
+Read at 0x0000005a56d8 by goroutine 7:
+  main.add()
+      /tmp/sol/main.go:7 +0x74
+  main.main.func1()
+      /tmp/sol/main.go:13 +0x12
+
+Previous write at 0x0000005a56d8 by goroutine 8:
+  main.add()
+      /tmp/sol/main.go:7 +0x8c
+  main.main.func1()
+      /tmp/sol/main.go:13 +0x12
+
+Goroutine 7 (running) created at:
+  main.main()
+      /tmp/sol/main.go:13 +0x56
+==================
+==================
+WARNING: DATA RACE
+Write at 0x00c000012345 by main goroutine:
+  main.main()
+      /tmp/sol/main.go:20 +0x1f0
+
+Previous read at 0x00c000012345 by goroutine 9:
+  main.main.func2()
+      /tmp/sol/main.go:17 +0x3c
+==================
+Found 2 data race(s)
+`
+
+func TestParseRaces(t *testing.T) {
+	want := []string{
+		"read in main.add (main.go:7) vs previous write in main.add (main.go:7)",
+		"write in main.main (main.go:20) vs previous read in main.main.func2 (main.go:17)",
+	}
+	if got := parseRaces(raceStderr); !slices.Equal(got, want) {
+		t.Errorf("parseRaces() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestParseRacesNone(t *testing.T) {
+	if got := parseRaces("panic: boom\n"); got != nil {
+		t.Errorf("parseRaces() = %q, want nil", got)
+	}
+	if got := parseRaces("WARNING: DATA RACE\ngarbled\n"); !slices.Equal(got, []string{"unknown accesses"}) {
+		t.Errorf("parseRaces() = %q, want unknown accesses", got)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// executeRepeated runs a test r.config.Repeat times and folds the runs into a
+// single result. A test only passes if every run passed; mixed verdicts are
+// reported as nondeterministic. With -warmup the test is first run once
+// untimed so page cache and binary loading don't skew the measured runs.
+func (r *TestRunner) executeRepeated(ctx context.Context, executablePath string, testCase TestCase) TestResult {
+	repeat := r.config.Repeat
+	if repeat < 1 {
+		repeat = 1
+	}
+
+	// Read the test data once rather than on every run
+	if loaded, err := testCase.Load(); err == nil {
+		testCase = loaded
+	}
+
+	if r.config.Warmup {
+		warmCtx, cancel := context.WithTimeout(ctx, r.config.GetTimeout())
+		warm := r.executor.Execute(warmCtx, executablePath, testCase)
+		cancel()
+
+		// A test that cannot finish in time won't be faster when measured
+		if warm.Verdict == VerdictTimeLimit {
+			return warm
+		}
+	}
+
+	var combined TestResult
+	var durations []time.Duration
+	passedRuns := 0
+
+	for i := 0; i < repeat; i++ {
+		if i > 0 && ctx.Err() != nil {
+			break
+		}
+
+		testCtx, cancel := context.WithTimeout(ctx, r.config.GetTimeout())
+		result := r.executor.Execute(testCtx, executablePath, testCase)
+		cancel()
+
+		durations = append(durations, result.Duration)
+		if result.Passed {
+			passedRuns++
+		}
+
+		// Keep the first failing run so its output can be inspected
+		if i == 0 || (combined.Passed && !result.Passed) {
+			combined = result
+		}
+	}
+
+	if repeat == 1 {
+		return combined
+	}
+
+	combined.Runs = len(durations)
+	combined.PassedRuns = passedRuns
+	combined.Duration, combined.StdDev = meanAndStdDev(durations)
+	combined.MinDuration, combined.MaxDuration = durations[0], durations[0]
+	for _, d := range durations {
+		if d < combined.MinDuration {
+			combined.MinDuration = d
+		}
+		if d > combined.MaxDuration {
+			combined.MaxDuration = d
+		}
+	}
+
+	combined.Passed = passedRuns == combined.Runs
+	if passedRuns > 0 && passedRuns < combined.Runs {
+		combined.Error = fmt.Sprintf("Nondeterministic: passed %d/%d runs (first failure: %s)", passedRuns, combined.Runs, combined.Error)
+	}
+
+	return combined
+}
+
+func meanAndStdDev(durations []time.Duration) (time.Duration, time.Duration) {
+	if len(durations) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, d := range durations {
+		sum += float64(d)
+	}
+	mean := sum / float64(len(durations))
+
+	var variance float64
+	for _, d := range durations {
+		variance += (float64(d) - mean) * (float64(d) - mean)
+	}
+	variance /= float64(len(durations))
+
+	return time.Duration(mean), time.Duration(math.Sqrt(variance))
+}
+
+// IsFlaky reports whether a repeated test produced different verdicts
+func (t TestResult) IsFlaky() bool {
+	return t.Runs > 1 && t.PassedRuns > 0 && t.PassedRuns < t.Runs
+}
+
+// displayRepeatSummary prints verdict consistency and timing variance for -repeat runs
+func (r *TestRunner) displayRepeatSummary(results []TestResult) {
+	if r.config.Repeat <= 1 {
+		return
+	}
+
+	fmt.Println("\n" + strings.Repeat("-", 40))
+	white.Printf("🔁 REPEAT SUMMARY (%d runs per test)\n", r.config.Repeat)
+	fmt.Println(strings.Repeat("-", 40))
+
+	var flaky []TestResult
+	worst := -1
+	worstCV := 0.0
+	for i, result := range results {
+		if result.IsFlaky() {
+			flaky = append(flaky, result)
+		}
+		if result.Duration > 0 {
+			if cv := float64(result.StdDev) / float64(result.Duration); cv > worstCV {
+				worst, worstCV = i, cv
+			}
+		}
+	}
+
+	if len(flaky) == 0 {
+		green.Println("✅ Verdicts were consistent across all runs")
+	} else {
+		red.Printf("⚠️  %d nondeterministic test(s) — check map iteration order, uninitialized reads or data races:\n", len(flaky))
+		for _, result := range flaky {
+			red.Printf("   Test %d: passed %d/%d runs\n", result.TestNumber, result.PassedRuns, result.Runs)
+		}
+	}
+
+	for _, result := range results {
+		if result.Runs <= 1 {
+			continue
+		}
+		if r.config.Verbose || result.IsFlaky() {
+			fmt.Printf("   Test %-3d mean %8.2fms  min %8.2fms  max %8.2fms  σ %6.2fms\n", result.TestNumber,
+				result.Duration.Seconds()*1000, result.MinDuration.Seconds()*1000,
+				result.MaxDuration.Seconds()*1000, result.StdDev.Seconds()*1000)
+		}
+	}
+
+	if worst >= 0 {
+		cyan.Printf("📈 Highest timing variance: test %d (σ %.2fms, %.0f%% of mean)\n",
+			results[worst].TestNumber, results[worst].StdDev.Seconds()*1000, worstCV*100)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxMarkdownDiffLines caps the diff shown per failed test in Markdown reports
+const maxMarkdownDiffLines = 40
+
+type reportRow struct {
+	Test          TestRecord
+	TimePercent   float64
+	MemoryPercent float64
+	LimitPercent  float64
+}
+
+// WriteHTMLReport renders a standalone HTML report of a run to path
+func WriteHTMLReport(config *Config, record *RunRecord, path string) error {
+	templates, err := parseWebTemplates()
+	if err != nil {
+		return err
+	}
+
+	var slowest float64
+	var largest int64
+	for _, test := range record.Tests {
+		if test.Duration > slowest {
+			slowest = test.Duration
+		}
+		if test.MemoryUsage > largest {
+			largest = test.MemoryUsage
+		}
+	}
+
+	limit := config.GetTimeout()
+	var rows []reportRow
+	for _, test := range record.Tests {
+		row := reportRow{Test: test}
+		if slowest > 0 {
+			row.TimePercent = test.Duration / slowest * 100
+		}
+		if largest > 0 {
+			row.MemoryPercent = float64(test.MemoryUsage) / float64(largest) * 100
+		}
+		if limit > 0 {
+			row.LimitPercent = test.Duration / (limit.Seconds() * 1000) * 100
+		}
+		rows = append(rows, row)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer file.Close()
+
+	err = templates.ExecuteTemplate(file, "report.html", map[string]interface{}{
+		"Run":        record,
+		"Rows":       rows,
+		"Limit":      limit,
+		"AppName":    AppName,
+		"AppVersion": AppVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	return nil
+}
+
+// WriteMarkdownReport renders a GitHub-flavored Markdown summary of a run to path
+func WriteMarkdownReport(config *Config, record *RunRecord, path string) error {
+	if err := os.WriteFile(path, []byte(renderMarkdownReport(config, record)), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return nil
+}
+
+// renderMarkdownReport is the Markdown summary written by -report-md
+func renderMarkdownReport(config *Config, record *RunRecord) string {
+	var b strings.Builder
+	limit := config.GetTimeout()
+
+	fmt.Fprintf(&b, "## CSES %s — ", record.ProblemID)
+	if record.AllPassed() {
+		fmt.Fprintf(&b, "✅ all %d tests passed\n\n", record.Total)
+	} else {
+		fmt.Fprintf(&b, "❌ %d/%d tests failed\n\n", record.Failed, record.Total)
+	}
+
+	fmt.Fprintf(&b, "- Solution: `%s`", record.FilePath)
+	if record.SourceHash != "" {
+		fmt.Fprintf(&b, " (`%s`)", shortHash(record.SourceHash))
+	}
+	fmt.Fprintf(&b, "\n- Run: %s, %.2fms total, time limit %s\n\n", record.StartedAt.Local().Format("2006-01-02 15:04:05"), record.Duration, limit)
+
+	b.WriteString("| Test | Verdict | Time | Memory | % of limit |\n")
+	b.WriteString("|-----:|:-------:|-----:|-------:|-----------:|\n")
+	for _, test := range record.Tests {
+		percent := 0.0
+		if limit > 0 {
+			percent = test.Duration / (limit.Seconds() * 1000) * 100
+		}
+		verdict := string(test.Verdict)
+		if !test.Passed {
+			verdict = "**" + verdict + "**"
+		}
+		fmt.Fprintf(&b, "| %d | %s | %.2fms | %s | %.1f%% |\n", test.Number, verdict, test.Duration, formatBytes(test.MemoryUsage), percent)
+	}
+
+	for _, test := range record.Tests {
+		if test.Passed {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n### Test %d — %s\n\n", test.Number, test.Verdict.Description())
+		if test.Error != "" {
+			fmt.Fprintf(&b, "```\n%s\n```\n\n", strings.TrimSpace(test.Error))
+		}
+		if test.ExpectedOutput == "" && test.ActualOutput == "" {
+			continue
+		}
+
+		b.WriteString("```diff\n")
+		shown := 0
+		for _, line := range lineDiff(test.ExpectedOutput, test.ActualOutput) {
+			if line.Kind == "same" {
+				continue
+			}
+			if shown == maxMarkdownDiffLines {
+				b.WriteString("@@ more differences omitted @@\n")
+				break
+			}
+			fmt.Fprintf(&b, "@@ line %d @@\n", line.Number)
+			expected, actual := line.Expected, line.Actual
+			if config.ShowWhitespace {
+				expected, actual = visualizeWhitespace(expected), visualizeWhitespace(actual)
+			}
+			if line.Kind != "extra" {
+				fmt.Fprintf(&b, "-%s\n", expected)
+			}
+			if line.Kind != "missing" {
+				fmt.Fprintf(&b, "+%s\n", actual)
+			}
+			shown++
+		}
+		b.WriteString("```\n")
+	}
+
+	return b.String()
+}
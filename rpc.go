@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcRunParams are the parameters accepted by the "run" method. Anything left
+// empty falls back to the value given on the command line.
+type rpcRunParams struct {
+	File     string `json:"file"`
+	Problem  string `json:"problem"`
+	Timeout  string `json:"timeout,omitempty"`
+	Parallel int    `json:"parallel,omitempty"`
+}
+
+type rpcCancelParams struct {
+	ID json.RawMessage `json:"id"`
+}
+
+type rpcProgress struct {
+	RunID     json.RawMessage `json:"runId"`
+	Completed int             `json:"completed"`
+	Total     int             `json:"total"`
+}
+
+type rpcTestResult struct {
+	RunID      json.RawMessage `json:"runId"`
+	Test       int             `json:"test"`
+	Passed     bool            `json:"passed"`
+	Error      string          `json:"error,omitempty"`
+	DurationMs float64         `json:"durationMs"`
+	ExitCode   int             `json:"exitCode"`
+	InputFile  string          `json:"inputFile"`
+}
+
+type rpcRunSummary struct {
+	Total  int `json:"total"`
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+}
+
+// RPCServer speaks newline-delimited JSON-RPC 2.0 over stdin/stdout so editor
+// plugins can drive test runs and receive per-test notifications.
+type RPCServer struct {
+	config *Config
+	auth   *CSESAuth
+	out    io.Writer
+
+	writeMu sync.Mutex
+	runsMu  sync.Mutex
+	runs    map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewRPCServer creates a server whose runs inherit defaults from config
+func NewRPCServer(config *Config, out io.Writer) *RPCServer {
+	return &RPCServer{
+		config: config,
+		auth:   NewCSESAuth(config),
+		out:    out,
+		runs:   make(map[string]context.CancelFunc),
+	}
+}
+
+// ServeStdio runs the JSON-RPC loop on the process stdio. All human-readable
+// output is redirected to stderr so stdout only ever carries protocol messages.
+func ServeStdio(config *Config) error {
+	color.Output = redactingWriter{os.Stderr}
+	return NewRPCServer(config, os.Stdout).Serve(os.Stdin)
+}
+
+// Serve reads requests from in until EOF or a "shutdown" request
+func (s *RPCServer) Serve(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	defer s.wg.Wait()
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.replyError(nil, rpcParseError, fmt.Sprintf("invalid JSON: %v", err))
+			continue
+		}
+
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			s.replyError(req.ID, rpcInvalidRequest, "expected a JSON-RPC 2.0 request")
+			continue
+		}
+
+		if req.Method == "shutdown" {
+			s.cancelAll()
+			s.wg.Wait()
+			s.reply(req.ID, map[string]bool{"ok": true})
+			return nil
+		}
+
+		s.handle(req)
+	}
+
+	s.cancelAll()
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+	return nil
+}
+
+func (s *RPCServer) handle(req rpcRequest) {
+	switch req.Method {
+	case "run":
+		s.handleRun(req)
+	case "cancel":
+		s.handleCancel(req)
+	case "version":
+		s.reply(req.ID, map[string]string{"name": AppName, "version": AppVersion})
+	default:
+		s.replyError(req.ID, rpcMethodNotFound, fmt.Sprintf("unknown method: %s", req.Method))
+	}
+}
+
+func (s *RPCServer) handleRun(req rpcRequest) {
+	if len(req.ID) == 0 {
+		s.replyError(nil, rpcInvalidRequest, "run requires a request id")
+		return
+	}
+
+	var params rpcRunParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		s.replyError(req.ID, rpcInvalidParams, fmt.Sprintf("invalid run params: %v", err))
+		return
+	}
+
+	config := *s.config
+	config.FilePath = params.File
+	config.ProblemID = params.Problem
+	if params.Timeout != "" {
+		config.Timeout = params.Timeout
+	}
+	if params.Parallel > 0 {
+		config.Parallel = params.Parallel
+	}
+
+	if err := validateRunConfig(&config); err != nil {
+		s.replyError(req.ID, rpcInvalidParams, err.Error())
+		return
+	}
+
+	key := string(req.ID)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.runsMu.Lock()
+	if _, exists := s.runs[key]; exists {
+		s.runsMu.Unlock()
+		cancel()
+		s.replyError(req.ID, rpcInvalidRequest, fmt.Sprintf("run %s is already in progress", key))
+		return
+	}
+	s.runs[key] = cancel
+	s.runsMu.Unlock()
+
+	runner := NewTestRunner(&config, s.auth)
+	runner.onProgress = func(completed, total int) {
+		s.notify("run/progress", rpcProgress{RunID: req.ID, Completed: completed, Total: total})
+	}
+	runner.onResult = func(result TestResult) {
+		s.notify("run/testResult", rpcTestResult{
+			RunID:      req.ID,
+			Test:       result.TestNumber,
+			Passed:     result.Passed,
+			Error:      result.Error,
+			DurationMs: result.Duration.Seconds() * 1000,
+			ExitCode:   result.ExitCode,
+			InputFile:  result.InputFile,
+		})
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.runsMu.Lock()
+			delete(s.runs, key)
+			s.runsMu.Unlock()
+			cancel()
+		}()
+
+		results, err := runner.Execute(ctx)
+		if err != nil {
+			s.replyError(req.ID, rpcServerError, err.Error())
+			return
+		}
+
+		summary := rpcRunSummary{Total: len(results)}
+		for _, result := range results {
+			if result.Passed {
+				summary.Passed++
+			} else {
+				summary.Failed++
+			}
+		}
+		s.reply(req.ID, summary)
+	}()
+}
+
+func (s *RPCServer) handleCancel(req rpcRequest) {
+	var params rpcCancelParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || len(params.ID) == 0 {
+		s.replyError(req.ID, rpcInvalidParams, "cancel requires the id of a run request")
+		return
+	}
+
+	s.runsMu.Lock()
+	cancel, exists := s.runs[string(params.ID)]
+	s.runsMu.Unlock()
+
+	if exists {
+		cancel()
+	}
+	s.reply(req.ID, map[string]bool{"cancelled": exists})
+}
+
+func (s *RPCServer) cancelAll() {
+	s.runsMu.Lock()
+	defer s.runsMu.Unlock()
+	for _, cancel := range s.runs {
+		cancel()
+	}
+}
+
+func (s *RPCServer) reply(id json.RawMessage, result interface{}) {
+	if len(id) == 0 {
+		return // notifications never get a response
+	}
+	s.write(rpcResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *RPCServer) replyError(id json.RawMessage, code int, message string) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	s.write(rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}})
+}
+
+func (s *RPCServer) notify(method string, params interface{}) {
+	s.write(rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *RPCServer) write(message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode RPC message: %v\n", err)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.out.Write(append(data, '\n'))
+}
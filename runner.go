@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -12,127 +15,448 @@ import (
 type TestRunner struct {
 	config   *Config
 	compiler *GoCompiler
-	fetcher  *TestCaseFetcher
+	fetcher  TestSource
 	executor *TestExecutor
 	auth     *CSESAuth
+
+	// lastRun is the record of the most recent Execute, used for reports
+	lastRun *RunRecord
+
+	// hooks are the lifecycle hooks loaded by Execute
+	hooks *LifecycleHooks
+
+	// races are the findings of the -race pass of the last Execute
+	races []RaceReport
+
+	// How long the last build took and how large its binary was
+	buildTime  time.Duration
+	binarySize int64
+
+	// prediction is the likely CSES verdict of the last full run, if shown
+	prediction *VerdictPrediction
+
+	// Optional observers used by non-terminal frontends (e.g. --stdio)
+	onProgress func(completed, total int)
+	onResult   func(result TestResult)
 }
 
-func NewTestRunner(config *Config) *TestRunner {
+// NewTestRunner creates a runner; auth is shared with its fetcher and may be
+// shared across runners so the session is only loaded and verified once.
+func NewTestRunner(config *Config, auth *CSESAuth) *TestRunner {
 	return &TestRunner{
 		config:   config,
 		compiler: NewGoCompiler(config),
-		fetcher:  NewTestCaseFetcher(config),
+		fetcher:  newTestSource(config, auth),
 		executor: NewTestExecutor(config),
-		auth:     NewCSESAuth(config),
+		auth:     auth,
 	}
 }
 
 func (r *TestRunner) Run() error {
+	displayNotes(r.config)
+
+	startTime := time.Now()
+	results, err := r.Execute(context.Background())
+	if err != nil {
+		var compileErr *CompileError
+		if errors.As(err, &compileErr) {
+			displayCompileError(r.config, compileErr)
+		}
+		NewNotifier(r.config).NotifyRun(time.Since(startTime), nil, err)
+		r.fireRunHooks(nil, err)
+		return err
+	}
+
+	if len(results) > 0 {
+		r.dumpFailedOutputs(results)
+		r.displayResults(results)
+		r.displayPrediction(results)
+		displayIOHint(r.config, results)
+		r.saveFailedArtifacts(results)
+		r.writeReports()
+		NewNotifier(r.config).NotifyRun(time.Since(startTime), results, nil)
+		r.fireRunHooks(results, nil)
+	}
+
+	for _, result := range results {
+		if !result.Passed {
+			return ErrTestsFailed
+		}
+	}
+	if err := raceFailure(r.races); err != nil {
+		return err
+	}
+
+	r.commitOnPass()
+	return nil
+}
+
+// saveFailedArtifacts stores failing tests on disk when -save-failed is set
+func (r *TestRunner) saveFailedArtifacts(results []TestResult) {
+	if !r.config.SaveFailed {
+		return
+	}
+
+	dir, saved, err := SaveFailedArtifacts(r.config, results)
+	if err != nil {
+		yellow.Printf("⚠️  Failed to save failing tests: %v\n", err)
+		return
+	}
+
+	if saved > 0 {
+		cyan.Printf("💾 Saved %d failing test(s) to %s\n", saved, filepath.Join(dir, "<test>"))
+	}
+}
+
+// dumpFailedOutputs writes full failing outputs when -dump-output-dir is set.
+// It runs before the results are shown so each failed test can point to its file.
+func (r *TestRunner) dumpFailedOutputs(results []TestResult) {
+	if r.config.DumpOutputDir == "" {
+		return
+	}
+
+	if _, err := DumpFailedOutputs(r.config, results); err != nil {
+		yellow.Printf("⚠️  Failed to dump outputs: %v\n", err)
+		r.config.DumpOutputDir = ""
+	}
+}
+
+// writeReports exports the last run in the formats requested on the command line
+func (r *TestRunner) writeReports() {
+	if r.lastRun == nil {
+		return
+	}
+
+	if r.config.ReportHTML != "" {
+		if err := WriteHTMLReport(r.config, r.lastRun, r.config.ReportHTML); err != nil {
+			yellow.Printf("⚠️  Failed to write HTML report: %v\n", err)
+		} else {
+			cyan.Printf("📄 HTML report written to %s\n", r.config.ReportHTML)
+		}
+	}
+
+	if r.config.ReportMarkdown != "" {
+		if err := WriteMarkdownReport(r.config, r.lastRun, r.config.ReportMarkdown); err != nil {
+			yellow.Printf("⚠️  Failed to write Markdown report: %v\n", err)
+		} else {
+			cyan.Printf("📄 Markdown report written to %s\n", r.config.ReportMarkdown)
+		}
+	}
+}
+
+// Execute prepares the solution and test cases and runs all tests, returning
+// the raw results without printing a summary. Cancelling ctx stops scheduling
+// further tests and kills the ones in flight.
+func (r *TestRunner) Execute(ctx context.Context) ([]TestResult, error) {
+	startedAt := time.Now()
+
 	// Create cache directory
 	if err := os.MkdirAll(r.config.CacheDir, 0755); err != nil {
-		return fmt.Errorf("failed to create cache directory: %w", err)
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	hooks, err := LoadLifecycleHooks(r.config)
+	if err != nil {
+		return nil, withExitCode(ExitUsageError, err)
 	}
+	r.hooks = hooks
 
-	// Ensure authentication
-	if err := r.auth.EnsureAuthenticated(); err != nil {
-		return fmt.Errorf("authentication failed: %w", err)
+	if err := r.authenticate(); err != nil {
+		return nil, err
 	}
 
 	// Validate Go installation
 	if err := r.compiler.ValidateGo(); err != nil {
-		return fmt.Errorf("Go validation failed: %w", err)
+		return nil, withExitCode(ExitCompileError, fmt.Errorf("Go validation failed: %w", err))
 	}
 
-	// Check Go code syntax
+	// Parse and type-check the solution before fetching tests
 	if err := r.compiler.ValidateSyntax(); err != nil {
-		return fmt.Errorf("syntax validation failed: %w", err)
+		return nil, withExitCode(ExitCompileError, fmt.Errorf("validation failed: %w", err))
 	}
 
 	// Fetch test cases
-	yellow.Println("📥 Fetching test cases from CSES...")
+	yellow.Printf("📥 Fetching test cases from %s...\n", r.fetcher.Name())
 	testCases, err := r.fetcher.FetchTestCases(r.config.ProblemID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch test cases: %w", err)
+		return nil, withExitCode(ExitFetchError, fmt.Errorf("failed to fetch test cases: %w", err))
 	}
 
 	if len(testCases) == 0 {
 		yellow.Println("⚠️  No test cases found for this problem")
-		return nil
+		return nil, nil
 	}
 
 	green.Printf("✅ Found %d test cases\n", len(testCases))
 
-	// Compile solution
+	// Full runs are recorded with a hash of their tests, so a run of an
+	// unchanged source on unchanged tests can be replayed
+	var testsHash string
+	if r.config.Test == 0 {
+		if testsHash, err = hashTestSet(testCases); err != nil {
+			return nil, withExitCode(ExitFetchError, err)
+		}
+		if cached := findCachedRun(r.config, testsHash); cached != nil {
+			when := cached.StartedAt.Local().Format("2006-01-02 15:04")
+			if r.config.CachedResults {
+				cyan.Printf("♻️  Source and tests unchanged since the run of %s, replaying its results\n", when)
+				r.lastRun = cached
+				return r.replayRun(cached, testCases), nil
+			}
+			cyan.Printf("💡 Source and tests unchanged since the run of %s; -cached-results replays it instantly\n", when)
+		}
+	}
+
+	if err := r.hooks.Fire(HookContext{Event: EventPreCompile, ProblemID: r.config.ProblemID, FilePath: r.config.FilePath}); err != nil {
+		return nil, err
+	}
+
+	// Compile solution. With -race the tests are timed with an ordinary
+	// build and the race detector gets a pass of its own afterwards.
+	compiler := r.compiler
+	if r.config.Race {
+		compiler = NewGoCompiler(r.config.withoutRace())
+	}
 	yellow.Println("🔨 Compiling Go solution...")
-	executablePath, err := r.compiler.Compile()
+	buildStart := time.Now()
+	executablePath, err := compiler.Compile()
 	if err != nil {
-		return fmt.Errorf("compilation failed: %w", err)
+		return nil, withExitCode(ExitCompileError, fmt.Errorf("compilation failed: %w", err))
 	}
 	defer os.Remove(executablePath) // Clean up
 
-	green.Println("✅ Compilation successful")
+	r.buildTime = time.Since(buildStart)
+	if info, err := os.Stat(executablePath); err == nil {
+		r.binarySize = info.Size()
+	}
+	green.Printf("✅ Compilation successful (%.2fs, %s binary)\n", r.buildTime.Seconds(), formatBytes(r.binarySize))
+	checkBinaryGrowth(r.config, r.binarySize)
+
+	if r.config.Docker != "" {
+		sandbox, err := StartDockerSandbox(r.config)
+		if err != nil {
+			return nil, err
+		}
+		defer sandbox.Stop()
+		r.executor.sandbox = sandbox
+	}
+
+	if r.config.Test > 0 {
+		if testCases, err = selectTest(testCases, r.config.Test); err != nil {
+			return nil, err
+		}
+
+		if r.config.Attach {
+			testCase, err := testCases[0].Load()
+			if err != nil {
+				return nil, err
+			}
+			return nil, r.executor.Attach(ctx, executablePath, testCase)
+		}
+	}
 
 	// Run tests
-	results := r.runTests(executablePath, testCases)
+	var results []TestResult
+	if r.config.Test > 0 {
+		yellow.Printf("🧪 Running test %d...\n", r.config.Test)
+		if r.config.Stream {
+			cyan.Println("📡 Streaming the solution's output")
+			fmt.Println(strings.Repeat("-", 60))
+		}
+		results = []TestResult{r.executeRepeated(ctx, executablePath, testCases[0])}
+		if r.config.Stream {
+			fmt.Println(strings.Repeat("-", 60))
+		}
+	} else {
+		results = r.runTests(ctx, executablePath, testCases)
+	}
+	if err := ctx.Err(); err != nil {
+		return results, fmt.Errorf("run cancelled: %w", err)
+	}
+
+	if r.config.GOGCSweep {
+		r.runGOGCSweep(ctx, executablePath, testCases, results)
+	}
+
+	r.races = nil
+	if r.config.Race {
+		if r.races, err = r.runRacePass(ctx, testCases); err != nil {
+			yellow.Printf("⚠️  Skipping the race detector pass: %v\n", err)
+		}
+	}
+
+	// Record full runs for history and the dashboard; single-test debugging
+	// runs would make a partial run look like a complete verdict
+	r.lastRun = NewRunRecord(r.config, startedAt, results)
+	r.lastRun.TestsHash = testsHash
+	r.lastRun.BuildTime = r.buildTime.Seconds() * 1000
+	r.lastRun.BinarySize = r.binarySize
+	if r.config.Test == 0 {
+		if err := NewHistoryStore(r.config).Save(r.lastRun); err != nil {
+			yellow.Printf("⚠️  Failed to record run history: %v\n", err)
+		}
+	}
+
+	return results, nil
+}
+
+// selectTest returns the test with the given number, for -test
+func selectTest(testCases []TestCase, number int) ([]TestCase, error) {
+	selected := slices.IndexFunc(testCases, func(tc TestCase) bool { return tc.Number == number })
+	if selected == -1 {
+		numbers := make([]int, len(testCases))
+		for i, tc := range testCases {
+			numbers[i] = tc.Number
+		}
+		return nil, withExitCode(ExitUsageError, fmt.Errorf("test %d does not exist (problem has tests %s)", number, formatTestNumbers(numbers)))
+	}
+	return testCases[selected : selected+1], nil
+}
 
-	// Display results
-	r.displayResults(results)
+// authenticate logs in to CSES if the tests come from there; tests from
+// another source need no login. While CSES is down the cached tests will do.
+func (r *TestRunner) authenticate() error {
+	if _, fromCSES := r.fetcher.(*TestCaseFetcher); !fromCSES || isImportedProblem(r.config.ProblemID) {
+		return nil
+	}
+	err := r.auth.EnsureAuthenticated()
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrUnavailable) {
+		return withExitCode(ExitFetchError, fmt.Errorf("authentication failed: %w", err))
+	}
 
+	cached, _ := NewTestCaseFetcher(r.config, nil).loadCachedTestCases(filepath.Join(r.config.CacheDir, r.config.ProblemID))
+	if len(cached) == 0 {
+		return withExitCode(ExitFetchError, fmt.Errorf("%w, and no tests of problem %s are cached", unavailableCause(err), r.config.ProblemID))
+	}
+	yellow.Printf("⚠️  %v; running on the cached tests\n", unavailableCause(err))
 	return nil
 }
 
-func (r *TestRunner) runTests(executablePath string, testCases []TestCase) []TestResult {
+// runPooledTest runs one test on a pool worker, holding its share of the data
+// budget while the test data is in memory
+func (r *TestRunner) runPooledTest(ctx context.Context, executablePath string, tc TestCase, index int, budget *dataBudget, results []TestResult, progressChan chan<- int) {
+	if ctx.Err() != nil {
+		results[index] = TestResult{TestNumber: tc.Number, Verdict: VerdictSkipped, Error: "cancelled"}
+		return
+	}
+
+	reserved := budget.acquire(testDataWeight(tc))
+	result := r.executeRepeated(ctx, executablePath, tc)
+	budget.release(reserved)
+
+	// Passing outputs are never shown again; dropping them keeps finished tests cheap
+	if result.Passed {
+		result.ExpectedOutput, result.ActualOutput, result.Stderr = "", "", ""
+	}
+	results[index] = result
+
+	if r.onResult != nil {
+		r.onResult(result)
+	}
+
+	if r.config.Verbose {
+		// One write per test through the color writers, so lines of parallel
+		// tests do not interleave and -stdio keeps them off stdout
+		var stats string
+		if input, err := readInputStats(result.InputFile); err == nil {
+			stats = fmt.Sprintf("\n   📊 Input: %s", input)
+		}
+		if result.Passed {
+			green.Printf("✅ Test %d passed (%.2fms)%s\n", result.TestNumber, result.Duration.Seconds()*1000, stats)
+		} else {
+			red.Printf("❌ Test %d failed: %s (%.2fms)%s\n", result.TestNumber, result.Error, result.Duration.Seconds()*1000, stats)
+		}
+	}
+
+	progressChan <- 1
+}
+
+func (r *TestRunner) runTests(ctx context.Context, executablePath string, testCases []TestCase) []TestResult {
 	results := make([]TestResult, len(testCases))
 
-	// Create a semaphore to limit parallel execution
-	semaphore := make(chan struct{}, r.config.Parallel)
-	var wg sync.WaitGroup
+	workers := r.config.Parallel
+	parallelLabel := fmt.Sprintf("%d", workers)
+	switch {
+	case r.config.Docker != "":
+		// The container has one CPU and one memory budget, which parallel
+		// tests would share and exceed together
+		workers = 1
+		parallelLabel = "1, one container"
+		if r.config.Parallel > 1 {
+			yellow.Println("⚠️  Tests run one at a time in the -docker container, -parallel is ignored")
+		}
+	case r.config.IsolateTiming:
+		// One test at a time so nothing competes with the measured run
+		workers = 1
+		parallelLabel = "1, isolated timing"
+		if !cpuPinningSupported {
+			yellow.Println("⚠️  CPU pinning is only supported on Linux, tests run sequentially without it")
+		}
+	case workers <= 0:
+		workers = r.autoParallelism()
+		parallelLabel = fmt.Sprintf("%d, auto", workers)
+	}
+	budget := newDataBudget(maxInFlightTestData)
+	limiter := newConcurrencyLimiter(workers)
 
-	yellow.Printf("🧪 Running %d test cases (parallel: %d)...\n", len(testCases), r.config.Parallel)
+	if r.config.Repeat > 1 {
+		yellow.Printf("🧪 Running %d test cases %d times each (parallel: %s)...\n", len(testCases), r.config.Repeat, parallelLabel)
+	} else {
+		yellow.Printf("🧪 Running %d test cases (parallel: %s)...\n", len(testCases), parallelLabel)
+	}
 
 	startTime := time.Now()
 	progressChan := make(chan int, len(testCases))
+	progressDone := make(chan struct{})
 
 	// Progress reporter
 	go func() {
+		defer close(progressDone)
 		completed := 0
 		for range progressChan {
 			completed++
+			if r.onProgress != nil {
+				r.onProgress(completed, len(testCases))
+			}
 			if r.config.Verbose {
 				cyan.Printf("📊 Progress: %d/%d test cases completed\n", completed, len(testCases))
 			}
 		}
 	}()
 
-	for i, testCase := range testCases {
-		wg.Add(1)
-		go func(index int, tc TestCase) {
-			defer wg.Done()
-
-			// Acquire semaphore
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-
-			ctx, cancel := context.WithTimeout(context.Background(), r.config.GetTimeout())
-			defer cancel()
+	// A fixed pool of workers takes tests in the chosen order. Test data is
+	// only loaded once a worker has reserved room for it in the budget.
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for _, i := range r.executionOrder(testCases) {
+			jobs <- i
+		}
+	}()
 
-			result := r.executor.Execute(ctx, executablePath, tc, index+1)
-			results[index] = result
+	watchDone := make(chan struct{})
+	go r.watchMemoryPressure(watchDone, limiter, workers)
 
-			if r.config.Verbose {
-				if result.Passed {
-					green.Printf("✅ Test %d passed (%.2fms)\n", index+1, result.Duration.Seconds()*1000)
-				} else {
-					red.Printf("❌ Test %d failed: %s (%.2fms)\n", index+1, result.Error, result.Duration.Seconds()*1000)
-				}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				limiter.acquire()
+				r.runPooledTest(ctx, executablePath, testCases[index], index, budget, results, progressChan)
+				limiter.release()
 			}
-
-			progressChan <- 1
-		}(i, testCase)
+		}()
 	}
 
 	wg.Wait()
+	close(watchDone)
 	close(progressChan)
+	<-progressDone
 
 	totalTime := time.Since(startTime)
 	cyan.Printf("⏱️  Total execution time: %.2fs\n", totalTime.Seconds())
@@ -160,6 +484,15 @@ func (r *TestRunner) displayResults(results []TestResult) {
 	white.Printf("📊 TEST RESULTS SUMMARY\n")
 	fmt.Println(strings.Repeat("=", 60))
 
+	switch r.config.Summary {
+	case SummaryTable:
+		r.displayResultsTable(results)
+	case SummaryFailuresOnly:
+		if len(failedTests) > 0 {
+			r.displayResultsTable(failedTests)
+		}
+	}
+
 	if passed > 0 {
 		green.Printf("✅ PASSED: %d/%d tests\n", passed, len(results))
 	}
@@ -169,6 +502,11 @@ func (r *TestRunner) displayResults(results []TestResult) {
 
 	cyan.Printf("⏱️  Average execution time: %.2fms\n", totalTime.Seconds()*1000/float64(len(results)))
 
+	r.displayRepeatSummary(results)
+	displayHeavyAllocations(results)
+	displayStackOverflows(r.config, results)
+	displayTimelines(r.config, results)
+
 	if len(failedTests) > 0 {
 		fmt.Println("\n" + strings.Repeat("-", 40))
 		red.Printf("❌ FAILED TEST CASES:\n")
@@ -179,6 +517,8 @@ func (r *TestRunner) displayResults(results []TestResult) {
 		}
 	}
 
+	displayRaces(r.races)
+
 	// Overall result
 	fmt.Println("\n" + strings.Repeat("=", 60))
 	if failed == 0 {
@@ -189,26 +529,87 @@ func (r *TestRunner) displayResults(results []TestResult) {
 	fmt.Println(strings.Repeat("=", 60))
 }
 
+// displayResultsTable prints one aligned row per test
+func (r *TestRunner) displayResultsTable(results []TestResult) {
+	limit := r.config.GetTimeout()
+
+	header := fmt.Sprintf("%-6s %-8s %12s %10s %9s", "TEST", "VERDICT", "TIME", "MEMORY", "% LIMIT")
+	width := 49
+	if r.config.GCStats {
+		header += fmt.Sprintf(" %5s %10s %9s", "GC", "PAUSE", "ALLOC")
+		width += 27
+	}
+	fmt.Println(header)
+	fmt.Println(strings.Repeat("-", width))
+
+	for _, result := range results {
+		percent := 0.0
+		if limit > 0 {
+			percent = float64(result.Duration) / float64(limit) * 100
+		}
+
+		line := fmt.Sprintf("%-6d %-8s %10.2fms %10s %8.1f%%", result.TestNumber, result.Verdict,
+			result.Duration.Seconds()*1000, formatBytes(result.MemoryUsage), percent)
+		if gc := result.GC; gc != nil {
+			line += fmt.Sprintf(" %5d %8.2fms %9s", gc.Cycles, gc.Pause.Seconds()*1000, formatBytes(gc.Allocated))
+		}
+
+		switch {
+		case !result.Passed:
+			red.Println(line)
+		case percent >= 80 || (result.GC != nil && result.GC.Heavy(result.Duration)):
+			yellow.Println(line)
+		default:
+			fmt.Println(line)
+		}
+	}
+
+	fmt.Println(strings.Repeat("-", width))
+}
+
 func (r *TestRunner) displayFailedTest(result TestResult) {
 	fmt.Printf("\n📍 Test Case %d:\n", result.TestNumber)
 	fmt.Printf("   📁 Input file: %s\n", result.InputFile)
+	if stats, err := readInputStats(result.InputFile); err == nil {
+		cyan.Printf("   📊 Input: %s\n", stats)
+	}
 	fmt.Printf("   📁 Expected file: %s\n", result.ExpectedFile)
 	fmt.Printf("   ⏱️  Duration: %.2fms\n", result.Duration.Seconds()*1000)
+	if result.GC != nil {
+		fmt.Printf("   🗑️  GC: %s\n", result.GC)
+	}
+	fmt.Printf("   ❌ Verdict: %s\n", result.Verdict.Description())
 	fmt.Printf("   ❌ Error: %s\n", result.Error)
 
+	if result.Verdict == VerdictRuntimeError {
+		r.displayPanicSource(result.Stderr)
+	}
+
+	if result.Verdict == VerdictWrongAnswer {
+		for _, mismatch := range shapeMismatch(result.ExpectedOutput, result.ActualOutput) {
+			yellow.Printf("   📐 Shape: %s\n", mismatch)
+		}
+	}
+
+	if r.config.DumpOutputDir != "" && result.Verdict != VerdictSkipped {
+		fmt.Printf("   📄 Full output (%s): %s\n", formatBytes(int64(len(result.ActualOutput))), dumpedOutputPath(r.config, result.TestNumber))
+	}
+
 	if r.config.ShowDiff && result.ActualOutput != "" {
 		fmt.Printf("   📤 Expected output (truncated to %d chars):\n", r.config.MaxOutput)
-		expectedOutput := result.ExpectedOutput
-		if len(expectedOutput) > r.config.MaxOutput {
-			expectedOutput = expectedOutput[:r.config.MaxOutput] + "..."
-		}
+		expectedOutput := r.displayOutput(truncateOutput(result.ExpectedOutput, r.config.MaxOutput))
 		green.Printf("   %s\n", strings.ReplaceAll(expectedOutput, "\n", "\n   "))
 
 		fmt.Printf("   📥 Actual output (truncated to %d chars):\n", r.config.MaxOutput)
-		actualOutput := result.ActualOutput
-		if len(actualOutput) > r.config.MaxOutput {
-			actualOutput = actualOutput[:r.config.MaxOutput] + "..."
-		}
+		actualOutput := r.displayOutput(truncateOutput(result.ActualOutput, r.config.MaxOutput))
 		red.Printf("   %s\n", strings.ReplaceAll(actualOutput, "\n", "\n   "))
 	}
 }
+
+// displayOutput applies -show-whitespace to output shown in a diff
+func (r *TestRunner) displayOutput(output string) string {
+	if r.config.ShowWhitespace {
+		return visualizeWhitespace(output)
+	}
+	return output
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package main
+
+import (
+	"os"
+)
+
+// peakMemoryUsage is not available on this platform
+func peakMemoryUsage(state *os.ProcessState) int64 {
+	return 0
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// peakMemoryUsage returns the peak resident set size of a finished process in bytes
+func peakMemoryUsage(state *os.ProcessState) int64 {
+	if state == nil {
+		return 0
+	}
+
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+
+	// macOS reports ru_maxrss in bytes, everything else in kilobytes
+	if runtime.GOOS == "darwin" {
+		return int64(rusage.Maxrss)
+	}
+	return int64(rusage.Maxrss) * 1024
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// Problem IDs of imported samples: cf-1352A, atcoder-abc300_a
+	importedIDPattern = regexp.MustCompile(`^(cf|atcoder)-[A-Za-z0-9_]+$`)
+
+	codeforcesURLPattern = regexp.MustCompile(`/(?:problemset/problem|contest|gym)/([0-9]+)/(?:problem/)?([A-Za-z][0-9]?)/?$`)
+	codeforcesPrePattern = regexp.MustCompile(`(?s)<div class="(input|output)">.*?<pre[^>]*>(.*?)</pre>`)
+	atcoderURLPattern    = regexp.MustCompile(`/contests/[^/]+/tasks/([A-Za-z0-9_]+)/?$`)
+	atcoderPrePattern    = regexp.MustCompile(`(?s)<h3>Sample (Input|Output) ([0-9]+)\s*</h3>\s*<pre[^>]*>(.*?)</pre>`)
+	htmlLineBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</div>`)
+)
+
+// isImportedProblem reports whether id names samples imported from another judge
+func isImportedProblem(id string) bool {
+	return importedIDPattern.MatchString(id)
+}
+
+// handleImportSamples implements `tests import-samples -url=<problem-url>`:
+// the sample tests of a Codeforces or AtCoder problem are stored in the test
+// cache like CSES tests, under an ID such as cf-1352A, so the usual run,
+// diff and stress machinery works on them
+func handleImportSamples(config *Config, pageURL string) error {
+	if pageURL == "" {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: tests import-samples -url=<problem-url>"))
+	}
+	parsed, err := url.Parse(pageURL)
+	if err != nil || parsed.Host == "" {
+		return withExitCode(ExitUsageError, fmt.Errorf("invalid URL %q", pageURL))
+	}
+
+	var problemID string
+	var parse func(page string) []TestCase
+	switch host := strings.TrimPrefix(parsed.Host, "www."); {
+	case host == "codeforces.com":
+		matches := codeforcesURLPattern.FindStringSubmatch(parsed.Path)
+		if matches == nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("%s is not a Codeforces problem page", pageURL))
+		}
+		problemID, parse = "cf-"+matches[1]+strings.ToUpper(matches[2]), parseCodeforcesSamples
+	case host == "atcoder.jp":
+		matches := atcoderURLPattern.FindStringSubmatch(parsed.Path)
+		if matches == nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("%s is not an AtCoder task page", pageURL))
+		}
+		problemID, parse = "atcoder-"+matches[1], parseAtCoderSamples
+	default:
+		return withExitCode(ExitUsageError, fmt.Errorf("samples can be imported from codeforces.com and atcoder.jp, not %s", parsed.Host))
+	}
+
+	yellow.Printf("🔍 Fetching %s...\n", pageURL)
+	page, err := fetchProblemPage(pageURL)
+	if err != nil {
+		return withExitCode(ExitFetchError, err)
+	}
+	testCases := parse(page)
+	if len(testCases) == 0 {
+		return withExitCode(ExitFetchError, fmt.Errorf("no sample tests found on %s", pageURL))
+	}
+
+	// Replace earlier imports rather than overwrite: cached files may be hard links
+	cacheDir := filepath.Join(config.CacheDir, problemID)
+	os.RemoveAll(cacheDir)
+	if err := NewTestCaseFetcher(config, nil).cacheTestCases(cacheDir, testCases); err != nil {
+		return fmt.Errorf("failed to cache samples: %w", err)
+	}
+
+	green.Printf("✅ Imported %d sample test(s) as problem %s\n", len(testCases), problemID)
+	fmt.Printf("   Run them with: %s -file=solution.go -problem=%s\n", AppName, problemID)
+	return nil
+}
+
+// fetchProblemPage downloads a problem page of another judge
+func fetchProblemPage(pageURL string) (string, error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/137.0.0.0 Safari/537.36")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch %s: status %d", pageURL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", pageURL, err)
+	}
+	return string(body), nil
+}
+
+// parseCodeforcesSamples pairs the input and output blocks of the
+// sample-test sections, in page order
+func parseCodeforcesSamples(page string) []TestCase {
+	var testCases []TestCase
+	var input string
+	haveInput := false
+	for _, block := range codeforcesPrePattern.FindAllStringSubmatch(page, -1) {
+		text := samplePreText(block[2])
+		if block[1] == "input" {
+			input, haveInput = text, true
+			continue
+		}
+		if haveInput {
+			testCases = append(testCases, TestCase{Input: input, Expected: text, Number: len(testCases) + 1})
+			haveInput = false
+		}
+	}
+	return testCases
+}
+
+// parseAtCoderSamples reads the numbered "Sample Input/Output" blocks of the
+// English statement (the Japanese one uses other headings)
+func parseAtCoderSamples(page string) []TestCase {
+	inputs := make(map[string]string)
+	outputs := make(map[string]string)
+	var order []string
+	for _, block := range atcoderPrePattern.FindAllStringSubmatch(page, -1) {
+		kind, number, text := block[1], block[2], samplePreText(block[3])
+		if kind == "Output" {
+			outputs[number] = text
+			continue
+		}
+		if _, seen := inputs[number]; !seen {
+			order = append(order, number)
+		}
+		inputs[number] = text
+	}
+
+	var testCases []TestCase
+	for _, number := range order {
+		if output, ok := outputs[number]; ok {
+			testCases = append(testCases, TestCase{Input: inputs[number], Expected: output, Number: len(testCases) + 1})
+		}
+	}
+	return testCases
+}
+
+// samplePreText turns the HTML of a <pre> block into its text, with line
+// breaks written as <br> or one <div> per line and a final newline
+func samplePreText(block string) string {
+	text := htmlLineBreakPattern.ReplaceAllString(block, "\n")
+	text = html.UnescapeString(statsTagPattern.ReplaceAllString(text, ""))
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.TrimLeft(strings.TrimRight(text, "\n"), "\n") + "\n"
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Sanitizers -checks can build the solution with. They instrument C code of
+// cgo solutions and unsafe memory accesses, so out of bounds reads and writes
+// that CSES may quietly survive fail locally.
+const (
+	CheckASan = "asan"
+	CheckMSan = "msan"
+)
+
+// sanitizerSummaryPattern matches the last line of a sanitizer report, e.g.
+// "SUMMARY: AddressSanitizer: heap-buffer-overflow /src/sol.go:5 in oob"
+var sanitizerSummaryPattern = regexp.MustCompile(`(?m)^SUMMARY: ((?:Address|Memory|Leak)Sanitizer: .+)$`)
+
+// validateChecks rejects a -checks value the build cannot honor
+func validateChecks(config *Config) error {
+	switch config.Checks {
+	case "":
+		return nil
+	case CheckASan, CheckMSan:
+	default:
+		return fmt.Errorf("invalid -checks %q (use %s or %s)", config.Checks, CheckASan, CheckMSan)
+	}
+	if config.GetCompiler() != CompilerGC || config.Docker != "" {
+		return fmt.Errorf("-checks=%s is only supported by the %s compiler on the host, without -docker", config.Checks, CompilerGC)
+	}
+	return nil
+}
+
+// parseSanitizerReport returns the summary of the first sanitizer report in
+// the solution's stderr, or "" if there is none
+func parseSanitizerReport(stderr string) string {
+	match := sanitizerSummaryPattern.FindStringSubmatch(stderr)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(match[1])
+}
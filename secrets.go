@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redactedSecret replaces a secret in everything the runner prints
+const redactedSecret = "[redacted]"
+
+// minSecretLength keeps short values from blanking out unrelated text
+const minSecretLength = 8
+
+// secretPatterns catch session tokens that were never registered, e.g. in a
+// cookie header quoted by an error
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(PHPSESSID=)[^;\s"&]+`),
+	regexp.MustCompile(`(csrf_token["']?\s*[:=]\s*["']?)[^"'\s&<]+`),
+}
+
+// secrets are the session tokens seen by this process. Every colored message
+// goes through a redactingWriter, so none of them is printed unless
+// -reveal-secrets asks for it.
+var secrets struct {
+	mu     sync.Mutex
+	values []string
+	reveal bool
+}
+
+// registerSecret makes value be redacted from all later output
+func registerSecret(value string) {
+	if len(value) < minSecretLength {
+		return
+	}
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+	for _, known := range secrets.values {
+		if known == value {
+			return
+		}
+	}
+	secrets.values = append(secrets.values, value)
+}
+
+// revealSecrets turns redaction off, for debugging authentication
+func revealSecrets() {
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+	secrets.reveal = true
+}
+
+// maskSecret is how a secret the runner prints on purpose is shown: in full
+// with -reveal-secrets, otherwise not at all
+func maskSecret(value string) string {
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+	if secrets.reveal {
+		return value
+	}
+	return redactedSecret
+}
+
+// redactSecrets masks the registered secrets and anything that looks like a
+// session token in text
+func redactSecrets(text string) string {
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+	if secrets.reveal {
+		return text
+	}
+
+	for _, value := range secrets.values {
+		text = strings.ReplaceAll(text, value, redactedSecret)
+	}
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllString(text, "${1}"+redactedSecret)
+	}
+	return text
+}
+
+// redactingWriter redacts secrets from everything written through it. The
+// color printers write each message in one call, so a secret is never split.
+type redactingWriter struct {
+	w io.Writer
+}
+
+func (r redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, redactSecrets(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
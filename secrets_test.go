@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	registerSecret("0123456789abcdef")
+	t.Cleanup(func() { secrets.reveal = false })
+
+	text := "token 0123456789abcdef, Cookie: PHPSESSID=fedcba9876543210; csrf_token=aaaabbbbccccdddd"
+	want := "token [redacted], Cookie: PHPSESSID=[redacted]; csrf_token=[redacted]"
+	if got := redactSecrets(text); got != want {
+		t.Errorf("redactSecrets() = %q, want %q", got, want)
+	}
+
+	revealSecrets()
+	if got := redactSecrets(text); got != text {
+		t.Errorf("redactSecrets() with -reveal-secrets = %q, want %q", got, text)
+	}
+}
+
+func TestLoginOutputHidesTokens(t *testing.T) {
+	_, config := startFakeCSES(t)
+
+	var output bytes.Buffer
+	stdout, noColor := color.Output, color.NoColor
+	color.Output, color.NoColor = redactingWriter{&output}, true
+	t.Cleanup(func() { color.Output, color.NoColor = stdout, noColor })
+
+	csrfToken, phpSessionID, err := NewCSESAuth(config).FetchLoginPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), "Extracted CSRF token") {
+		t.Fatalf("no login output captured: %q", output.String())
+	}
+	// Not even a prefix of either token may be printed
+	for _, token := range []string{csrfToken, phpSessionID} {
+		if strings.Contains(output.String(), token[:6]) {
+			t.Errorf("login output %q shows token %q", output.String(), token)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// PracticeSession marks the start of a practice session; the runs themselves
+// are read back from the history
+type PracticeSession struct {
+	StartedAt time.Time `json:"started_at"`
+}
+
+// ProblemScore is one row of the scoreboard
+type ProblemScore struct {
+	ProblemID  string
+	Runs       int
+	BestPassed int
+	BestTotal  int
+	Solved     bool
+	FirstRun   time.Time
+	LastRun    time.Time // end of the latest run
+	SolvedAt   time.Time // start of the first accepted run
+
+	// FailedBeforeSolve counts the rejected runs before SolvedAt
+	FailedBeforeSolve int
+}
+
+// TimeSpent is the span from the first run of the problem to its last (or to
+// the accepted run once solved)
+func (p ProblemScore) TimeSpent() time.Duration {
+	if p.Solved {
+		return p.SolvedAt.Sub(p.FirstRun)
+	}
+	return p.LastRun.Sub(p.FirstRun)
+}
+
+// handleSession implements `session start`, `session` (status) and `session end`
+func handleSession(config *Config, args []string) error {
+	path := config.GetPracticeSessionPath()
+
+	if len(args) == 0 || args[0] == "status" {
+		session, err := loadPracticeSession(path)
+		if err != nil {
+			return err
+		}
+		if session == nil {
+			now := time.Now()
+			midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+			return showScoreboard(config, "Today", midnight)
+		}
+		return showScoreboard(config, "Session", session.StartedAt)
+	}
+
+	switch args[0] {
+	case "start":
+		session, err := loadPracticeSession(path)
+		if err != nil {
+			return err
+		}
+		if session != nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("a session is already running since %s (end it with `session end`)",
+				session.StartedAt.Local().Format("15:04")))
+		}
+
+		session = &PracticeSession{StartedAt: time.Now()}
+		if err := savePracticeSession(path, session); err != nil {
+			return err
+		}
+		green.Printf("⏱️  Session started at %s; runs from now on count towards its scoreboard\n", session.StartedAt.Format("15:04"))
+		return nil
+	case "end":
+		session, err := loadPracticeSession(path)
+		if err != nil {
+			return err
+		}
+		if session == nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("no session is running (start one with `session start`)"))
+		}
+
+		if err := showScoreboard(config, "Session", session.StartedAt); err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to end session: %w", err)
+		}
+		green.Println("🏁 Session ended")
+		return nil
+	}
+
+	return withExitCode(ExitUsageError, fmt.Errorf("unknown session subcommand: %s", args[0]))
+}
+
+// loadPracticeSession returns the running session, or nil if there is none
+func loadPracticeSession(path string) (*PracticeSession, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var session PracticeSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse session %s: %w", path, err)
+	}
+	return &session, nil
+}
+
+func savePracticeSession(path string, session *PracticeSession) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// buildScoreboard summarises the runs started at or after since, one row per
+// problem in the order they were first attempted
+func buildScoreboard(records []*RunRecord, since time.Time) []ProblemScore {
+	scores := make(map[string]*ProblemScore)
+	for _, record := range records {
+		if record.StartedAt.Before(since) {
+			continue
+		}
+
+		score, exists := scores[record.ProblemID]
+		if !exists {
+			score = &ProblemScore{ProblemID: record.ProblemID, FirstRun: record.StartedAt}
+			scores[record.ProblemID] = score
+		}
+
+		score.Runs++
+		if record.StartedAt.Before(score.FirstRun) {
+			score.FirstRun = record.StartedAt
+		}
+		if end := record.StartedAt.Add(time.Duration(record.Duration * float64(time.Millisecond))); end.After(score.LastRun) {
+			score.LastRun = end
+		}
+		if record.Passed > score.BestPassed || score.BestTotal == 0 {
+			score.BestPassed, score.BestTotal = record.Passed, record.Total
+		}
+		if record.AllPassed() && (!score.Solved || record.StartedAt.Before(score.SolvedAt)) {
+			score.Solved = true
+			score.SolvedAt = record.StartedAt
+			score.BestPassed, score.BestTotal = record.Passed, record.Total
+		}
+	}
+
+	for _, record := range records {
+		score := scores[record.ProblemID]
+		if score != nil && score.Solved && !record.AllPassed() &&
+			!record.StartedAt.Before(since) && record.StartedAt.Before(score.SolvedAt) {
+			score.FailedBeforeSolve++
+		}
+	}
+
+	var result []ProblemScore
+	for _, score := range scores {
+		result = append(result, *score)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FirstRun.Before(result[j].FirstRun)
+	})
+	return result
+}
+
+func showScoreboard(config *Config, title string, since time.Time) error {
+	records, err := NewHistoryStore(config).List()
+	if err != nil {
+		return err
+	}
+
+	scores := buildScoreboard(records, since)
+	elapsed := time.Since(since).Round(time.Second)
+	cyan.Printf("🏆 %s scoreboard (since %s, %s elapsed)\n\n", title, since.Local().Format("15:04"), elapsed)
+
+	if len(scores) == 0 {
+		yellow.Println("⚠️  No runs yet")
+		return nil
+	}
+
+	solved, runs := 0, 0
+	fmt.Printf("%-8s %-5s %-9s %-10s %s\n", "PROBLEM", "RUNS", "BEST", "SOLVED AT", "TIME SPENT")
+	for _, score := range scores {
+		runs += score.Runs
+
+		fmt.Printf("%-8s %-5d ", score.ProblemID, score.Runs)
+		best := fmt.Sprintf("%d/%d", score.BestPassed, score.BestTotal)
+		solvedAt := "-"
+		if score.Solved {
+			solved++
+			green.Printf("%-9s", best)
+			solvedAt = "+" + formatElapsed(score.SolvedAt.Sub(since))
+		} else {
+			red.Printf("%-9s", best)
+		}
+		fmt.Printf(" %-10s %s\n", solvedAt, formatElapsed(score.TimeSpent()))
+	}
+
+	fmt.Println()
+	cyan.Printf("📊 Solved %d/%d problem(s) in %d run(s)\n", solved, len(scores), runs)
+	return nil
+}
+
+// formatElapsed renders a duration as h:mm:ss
+func formatElapsed(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
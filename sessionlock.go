@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// sessionLockWait bounds how long we wait for another process to finish logging in
+	sessionLockWait = 2 * time.Minute
+
+	// staleSessionLock is the age after which a lock left behind by a crashed process is broken
+	staleSessionLock = 5 * time.Minute
+)
+
+// lockSession takes the cross-process lock guarding the session file, so that
+// concurrent runs (e.g. a git hook and an editor) do not log in at the same
+// time and overwrite each other's session. The returned func releases it.
+func lockSession(sessionFile string) (func(), error) {
+	if sessionFile == "" {
+		return func() {}, nil
+	}
+
+	path := sessionFile + ".lock"
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	deadline := time.Now().Add(sessionLockWait)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			fmt.Fprintf(file, "%d\n", os.Getpid())
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create session lock: %w", err)
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleSessionLock {
+			os.Remove(path)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for session lock %s (remove it if no other run is active)", path)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultGitHubAPI is used unless GITHUB_API_URL points elsewhere (e.g. GitHub Enterprise)
+const defaultGitHubAPI = "https://api.github.com"
+
+// gistRequest is the body of POST /gists
+type gistRequest struct {
+	Description string              `json:"description"`
+	Public      bool                `json:"public"`
+	Files       map[string]gistFile `json:"files"`
+}
+
+type gistFile struct {
+	Content string `json:"content"`
+}
+
+// handleShare uploads the solution of the latest run of -file (or -problem)
+// and that run's results to a secret GitHub Gist, authenticated with
+// GITHUB_TOKEN, and prints its URL
+func handleShare(config *Config) error {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return withExitCode(ExitUsageError, fmt.Errorf("GITHUB_TOKEN must be set to a token with the gist scope"))
+	}
+
+	record, err := latestRun(config)
+	if err != nil {
+		return err
+	}
+
+	source, err := os.ReadFile(record.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read solution: %w", err)
+	}
+	if hash, err := hashFile(record.FilePath); err == nil && hash != record.SourceHash {
+		yellow.Printf("⚠️  %s changed since the run on %s; the results may not match the code\n",
+			record.FilePath, record.StartedAt.Local().Format("2006-01-02 15:04"))
+	}
+
+	// Only the file name is shared, not where it lives on this machine
+	shared := *record
+	shared.FilePath = filepath.Base(record.FilePath)
+
+	description := fmt.Sprintf("CSES %s: %d/%d tests passed", record.ProblemID, record.Passed, record.Total)
+	request := gistRequest{
+		Description: description,
+		Files: map[string]gistFile{
+			shared.FilePath: {Content: string(source)},
+			"RESULTS.md":    {Content: renderMarkdownReport(config, &shared)},
+		},
+	}
+
+	url, err := createGist(token, request)
+	if err != nil {
+		return err
+	}
+
+	green.Printf("🔗 Shared %s (%s): %s\n", shared.FilePath, description, url)
+	return nil
+}
+
+// latestRun returns the most recent run of -file, or of -problem when no file is given
+func latestRun(config *Config) (*RunRecord, error) {
+	if config.FilePath == "" && config.ProblemID == "" {
+		return nil, withExitCode(ExitUsageError, fmt.Errorf("usage: share -file=solution.go [-problem=<id>]"))
+	}
+
+	file := ""
+	if config.FilePath != "" {
+		var err error
+		if file, err = filepath.Abs(config.FilePath); err != nil {
+			return nil, err
+		}
+	}
+
+	record, err := NewHistoryStore(config).Latest(func(record *RunRecord) bool {
+		return (file == "" || record.FilePath == file) && (config.ProblemID == "" || record.ProblemID == config.ProblemID)
+	})
+	if err != nil || record != nil {
+		return record, err
+	}
+	return nil, withExitCode(ExitUsageError, fmt.Errorf("no recorded run of this solution; run its tests first"))
+}
+
+// githubAPI is the base URL of the GitHub REST API
+func githubAPI() string {
+	if api := os.Getenv("GITHUB_API_URL"); api != "" {
+		return strings.TrimSuffix(api, "/")
+	}
+	return defaultGitHubAPI
+}
+
+func createGist(token string, request gistRequest) (string, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, githubAPI()+"/gists", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create gist: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read gist response: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(data, &apiError)
+		return "", fmt.Errorf("GitHub returned status %d: %s", resp.StatusCode, apiError.Message)
+	}
+
+	var gist struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(data, &gist); err != nil || gist.HTMLURL == "" {
+		return "", fmt.Errorf("unexpected gist response")
+	}
+	return gist.HTMLURL, nil
+}
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package main
+
+import (
+	"os"
+)
+
+// terminationSignal is not available on this platform
+func terminationSignal(state *os.ProcessState) string {
+	return ""
+}
+
+// signalFromExitCode is not available on this platform
+func signalFromExitCode(code int) string {
+	return ""
+}
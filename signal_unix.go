@@ -0,0 +1,40 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminationSignal returns the name of the signal that killed the process, or ""
+func terminationSignal(state *os.ProcessState) string {
+	if state == nil {
+		return ""
+	}
+
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return ""
+	}
+
+	return signalName(status.Signal())
+}
+
+// signalFromExitCode decodes the 128+n exit status used by shells and
+// `docker exec` for a process killed by signal n
+func signalFromExitCode(code int) string {
+	if code <= 128 || code >= 128+65 {
+		return ""
+	}
+	return signalName(syscall.Signal(code - 128))
+}
+
+func signalName(sig syscall.Signal) string {
+	if name := unix.SignalName(sig); name != "" {
+		return name
+	}
+	return sig.String()
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// stackLimitFile is the file added to -max-stack builds; it only exists in
+// the build's overlay, never next to the solution
+const stackLimitFile = "zz_cses_max_stack.go"
+
+var (
+	stackLimitPattern = regexp.MustCompile(`goroutine stack exceeds (\d+)-byte limit`)
+	stackFramePattern = regexp.MustCompile(`^\s+\S+\.go:\d+(?:\s+\+0x[0-9a-f]+)?\s+fp=0x([0-9a-f]+)\s+sp=0x([0-9a-f]+)`)
+)
+
+// StackOverflow describes a run that died of "fatal error: stack overflow"
+type StackOverflow struct {
+	Limit     int64  // bytes
+	Function  string // the function repeated most in the traceback, usually the recursive one
+	FrameSize int64  // bytes of stack per call of Function, 0 if unknown
+}
+
+// Depth estimates how many calls of Function filled the stack
+func (s *StackOverflow) Depth() int64 {
+	if s.FrameSize == 0 {
+		return 0
+	}
+	return s.Limit / s.FrameSize
+}
+
+func (s *StackOverflow) String() string {
+	message := fmt.Sprintf("stack overflow: the %s stack limit was exceeded", formatBytes(s.Limit))
+	if s.Function != "" {
+		message += " in " + s.Function
+	}
+	if depth := s.Depth(); depth > 0 {
+		message += fmt.Sprintf(", about %d calls deep at %d bytes per call", depth, s.FrameSize)
+	}
+	return message
+}
+
+// parseStackOverflow recognizes a stack overflow in the solution's stderr. The
+// traceback shows the frame and stack pointers of each call, so the size of a
+// recursive call's frame, and from it the recursion depth, can be told.
+func parseStackOverflow(stderr string) *StackOverflow {
+	if !strings.Contains(stderr, "fatal error: stack overflow") {
+		return nil
+	}
+	overflow := &StackOverflow{}
+	if match := stackLimitPattern.FindStringSubmatch(stderr); match != nil {
+		overflow.Limit, _ = strconv.ParseInt(match[1], 10, 64)
+	}
+
+	calls := make(map[string]int)
+	frameSizes := make(map[string]int64)
+	lines := strings.Split(stderr, "\n")
+	for i := 1; i < len(lines); i++ {
+		match := stackFramePattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		function := strings.TrimSpace(lines[i-1])
+		if paren := strings.LastIndex(function, "("); paren > 0 {
+			function = function[:paren]
+		}
+		if strings.HasPrefix(function, "runtime.") {
+			continue
+		}
+		calls[function]++
+		fp, _ := strconv.ParseUint(match[1], 16, 64)
+		sp, _ := strconv.ParseUint(match[2], 16, 64)
+		if fp > sp {
+			frameSizes[function] = int64(fp - sp)
+		}
+	}
+	for function, count := range calls {
+		if overflow.Function == "" || count > calls[overflow.Function] {
+			overflow.Function = function
+		}
+	}
+	overflow.FrameSize = frameSizes[overflow.Function]
+	return overflow
+}
+
+// addStackLimit makes a build command compile in a file that sets the maximum
+// goroutine stack size to -max-stack, so recursion that only just fits the
+// default 1GB stack fails like on a judge with a smaller stack. The returned
+// function removes the overlay after the build.
+func (c *GoCompiler) addStackLimit(cmd *exec.Cmd) (func(), error) {
+	limit, err := parseByteSize(c.config.MaxStack)
+	if err != nil {
+		return nil, err
+	}
+	solution, err := filepath.Abs(c.config.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "cses-max-stack-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stack limit overlay: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	source := fmt.Sprintf("package main\n\nimport \"runtime/debug\"\n\nfunc init() { debug.SetMaxStack(%d) }\n", limit)
+	virtual := filepath.Join(filepath.Dir(solution), stackLimitFile)
+	overlay, err := json.Marshal(map[string]map[string]string{"Replace": {virtual: filepath.Join(dir, stackLimitFile)}})
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, stackLimitFile), []byte(source), 0644)
+	}
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, "overlay.json"), overlay, 0644)
+	}
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("failed to create stack limit overlay: %w", err)
+	}
+
+	// go build -overlay=... <args>, naming the file too when files are built
+	// rather than the package
+	args := append([]string{cmd.Args[0], cmd.Args[1], "-overlay=" + filepath.Join(dir, "overlay.json")}, cmd.Args[2:]...)
+	if target := args[len(args)-1]; strings.HasSuffix(target, ".go") {
+		args = append(args, filepath.Join(filepath.Dir(target), stackLimitFile))
+	}
+	cmd.Args = args
+	return cleanup, nil
+}
+
+// displayStackOverflows lists the tests that overflowed the stack, apart from
+// other runtime errors, with the deepest recursion seen
+func displayStackOverflows(config *Config, results []TestResult) {
+	var tests []int
+	var deepest *StackOverflow
+	for _, result := range results {
+		if result.StackOverflow == nil {
+			continue
+		}
+		tests = append(tests, result.TestNumber)
+		if deepest == nil || result.StackOverflow.Depth() > deepest.Depth() {
+			deepest = result.StackOverflow
+		}
+	}
+	if len(tests) == 0 {
+		return
+	}
+
+	limit := "the default stack limit"
+	if config.MaxStack != "" {
+		limit = "-max-stack=" + config.MaxStack
+	}
+	red.Printf("🧱 Stack overflow in test(s) %s with %s\n", formatTestNumbers(tests), limit)
+	if deepest.Function != "" && deepest.Depth() > 0 {
+		fmt.Printf("   Deepest recursion: %s, about %d calls of %d bytes; an explicit stack or smaller frames avoid it\n",
+			deepest.Function, deepest.Depth(), deepest.FrameSize)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import "testing"
+
+const stackOverflowStderr = `runtime: goroutine stack exceeds 1048576-byte limit
+runtime: sp=0x1d8d33260350 stack=[0x1d8d33260000, 0x1d8d33360000]
+fatal error: stack overflow
+
+runtime stack:
+runtime.throw({0x4856fc?, 0x200000008?})
+	/usr/local/go/src/runtime/panic.go:1243 +0x48 fp=0x7ffd72b8aad8 sp=0x7ffd72b8aaa8 pc=0x476c88
+runtime.newstack()
+	/usr/local/go/src/runtime/stack.go:1207 +0x5dd fp=0x7ffd72b8ac08 sp=0x7ffd72b8aad8 pc=0x45c8dd
+runtime.morestack()
+	/usr/local/go/src/runtime/asm_amd64.s:650 +0x7b fp=0x7ffd72b8ac10 sp=0x7ffd72b8ac08 pc=0x47a6db
+
+goroutine 1 gp=0x1d8d330c81e0 m=0 mp=0x53fe60 [running]:
+main.rec(0x35d5?)
+	/tmp/sol/main.go:5 +0xde fp=0x1d8d33260360 sp=0x1d8d33260358 pc=0x48331e
+main.rec(...)
+	/tmp/sol/main.go:8
+main.rec(0x0?)
+	/tmp/sol/main.go:8 +0xa5 fp=0x1d8d332603f8 sp=0x1d8d33260360 pc=0x4832e5
+main.rec(...)
+	/tmp/sol/main.go:8
+main.rec(0x0?)
+	/tmp/sol/main.go:8 +0xa5 fp=0x1d8d33260490 sp=0x1d8d332603f8 pc=0x4832e5
+main.main()
+	/tmp/sol/main.go:13 +0x25 fp=0x1d8d33360f50 sp=0x1d8d33360f28 pc=0x48336c
+`
+
+func TestParseStackOverflow(t *testing.T) {
+	overflow := parseStackOverflow(stackOverflowStderr)
+	if overflow == nil {
+		t.Fatal("stack overflow not recognized")
+	}
+	if overflow.Limit != 1048576 {
+		t.Errorf("Limit = %d, want 1048576", overflow.Limit)
+	}
+	if overflow.Function != "main.rec" {
+		t.Errorf("Function = %q, want main.rec", overflow.Function)
+	}
+	if overflow.FrameSize != 0x98 {
+		t.Errorf("FrameSize = %d, want %d", overflow.FrameSize, 0x98)
+	}
+	if depth := overflow.Depth(); depth != 1048576/0x98 {
+		t.Errorf("Depth() = %d, want %d", depth, 1048576/0x98)
+	}
+}
+
+func TestParseStackOverflowOtherErrors(t *testing.T) {
+	for _, stderr := range []string{
+		"",
+		"panic: runtime error: index out of range [5] with length 3\n\ngoroutine 1 [running]:\nmain.main()\n\t/tmp/sol/main.go:7 +0x1d\n",
+		"fatal error: all goroutines are asleep - deadlock!\n",
+	} {
+		if overflow := parseStackOverflow(stderr); overflow != nil {
+			t.Errorf("parseStackOverflow(%q) = %+v, want nil", stderr, overflow)
+		}
+	}
+}
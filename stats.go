@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// statsBarWidth is the width of a full bar in the stats charts
+const statsBarWidth = 30
+
+// handleStats implements `stats`: solved problems per category, failing runs
+// before all tests passed, and how close accepted solutions came to the time
+// limit. With -problem it shows the CSES statistics of that problem instead.
+func handleStats(config *Config, args []string) error {
+	if len(args) > 0 {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: stats [-problem=<id>]"))
+	}
+	if config.ProblemID != "" {
+		return showProblemStats(config, config.ProblemID)
+	}
+
+	store := NewHistoryStore(config)
+	records, err := store.List()
+	if err != nil {
+		return err
+	}
+	scores := buildScoreboard(records, time.Time{})
+	if len(scores) == 0 {
+		yellow.Println("⚠️  No runs recorded yet")
+		return nil
+	}
+
+	index, err := LoadProblemsetIndex(config, NewCSESAuth(config))
+	if err != nil {
+		yellow.Printf("⚠️  Problem list unavailable (%v); problems are not grouped by category\n", err)
+		index = &ProblemsetIndex{}
+	}
+
+	showCategoryChart(index, scores)
+	showAttemptStats(scores)
+	showMarginChart(config, store, records, scores)
+	return nil
+}
+
+// showCategoryChart draws solved/total per category, in problem list order
+func showCategoryChart(index *ProblemsetIndex, scores []ProblemScore) {
+	solved := make(map[string]int)
+	total := make(map[string]int)
+	for _, problem := range index.Problems {
+		total[problem.Category]++
+	}
+
+	categories := index.Categories()
+	for _, score := range scores {
+		if !score.Solved {
+			continue
+		}
+		category := "Uncategorized"
+		if problem, ok := index.Find(score.ProblemID); ok {
+			category = problem.Category
+		}
+		if solved[category] == 0 && total[category] == 0 {
+			categories = append(categories, category)
+		}
+		solved[category]++
+	}
+
+	cyan.Println("📚 Solved per category")
+	width := 0
+	for _, category := range categories {
+		width = max(width, len(category))
+	}
+	for _, category := range categories {
+		fraction := 1.0
+		if total[category] > 0 {
+			fraction = float64(solved[category]) / float64(total[category])
+		}
+		count := fmt.Sprintf("%d", solved[category])
+		if total[category] > 0 {
+			count += fmt.Sprintf("/%d", total[category])
+		}
+		fmt.Printf("  %-*s ", width, category)
+		green.Print(bar(fraction))
+		fmt.Printf(" %s\n", count)
+	}
+	fmt.Println()
+}
+
+// showAttemptStats reports how many failing runs came before all tests passed
+func showAttemptStats(scores []ProblemScore) {
+	solved, failed := 0, 0
+	for _, score := range scores {
+		if score.Solved {
+			solved++
+			failed += score.FailedBeforeSolve
+		}
+	}
+
+	cyan.Println("🎯 Attempts")
+	fmt.Printf("  Solved %d of %d problem(s) tried\n", solved, len(scores))
+	if solved > 0 {
+		fmt.Printf("  %.1f failing run(s) on average before all tests passed\n", float64(failed)/float64(solved))
+	}
+	fmt.Println()
+}
+
+// showMarginChart draws how much of the time limit the slowest test of each
+// problem's first all-pass run used
+func showMarginChart(config *Config, store *HistoryStore, records []*RunRecord, scores []ProblemScore) {
+	buckets := []struct {
+		label string
+		upTo  float64
+		count int
+	}{
+		{label: "< 10%", upTo: 0.10},
+		{label: "10-25%", upTo: 0.25},
+		{label: "25-50%", upTo: 0.50},
+		{label: "50-75%", upTo: 0.75},
+		{label: "75-100%", upTo: 1.00},
+	}
+
+	var used []float64
+	for _, score := range scores {
+		summary := firstPassingRun(records, score)
+		if summary == nil {
+			continue
+		}
+		record, err := store.Load(summary.ID)
+		if err != nil {
+			continue
+		}
+
+		limit := record.TimeLimit
+		if limit <= 0 {
+			limit = config.GetTimeout().Seconds() * 1000
+		}
+		var slowest float64
+		for _, test := range record.Tests {
+			slowest = max(slowest, test.Duration)
+		}
+		used = append(used, slowest/limit)
+	}
+	if len(used) == 0 {
+		return
+	}
+
+	var sum float64
+	for _, fraction := range used {
+		sum += fraction
+		for i := range buckets {
+			if fraction < buckets[i].upTo || i == len(buckets)-1 {
+				buckets[i].count++
+				break
+			}
+		}
+	}
+
+	cyan.Println("⏱️  Slowest test of accepted runs, as a share of the time limit")
+	for _, bucket := range buckets {
+		fmt.Printf("  %-7s ", bucket.label)
+		color := green
+		if bucket.upTo > 0.5 {
+			color = yellow
+		}
+		color.Print(bar(float64(bucket.count) / float64(len(used))))
+		fmt.Printf(" %d\n", bucket.count)
+	}
+	average := sum / float64(len(used))
+	fmt.Printf("  Average: %.1f%% of the limit (%.1f%% margin)\n", average*100, (1-average)*100)
+}
+
+// firstPassingRun returns the run in which the problem first passed every test
+func firstPassingRun(records []*RunRecord, score ProblemScore) *RunRecord {
+	if !score.Solved {
+		return nil
+	}
+	for _, record := range records {
+		if record.ProblemID == score.ProblemID && record.AllPassed() && record.StartedAt.Equal(score.SolvedAt) {
+			return record
+		}
+	}
+	return nil
+}
+
+// bar renders fraction (0 to 1) of a statsBarWidth wide bar
+func bar(fraction float64) string {
+	filled := int(fraction*statsBarWidth + 0.5)
+	filled = min(max(filled, 0), statsBarWidth)
+	return strings.Repeat("█", filled) + strings.Repeat("░", statsBarWidth-filled)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// handleStatus implements `status [problem]`: for every problem in the
+// history, how long it took from its first run to its first all-pass run
+func handleStatus(config *Config, args []string) error {
+	if len(args) > 1 {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: status [problem]"))
+	}
+	problemID := config.ProblemID
+	if len(args) == 1 {
+		problemID = resolveAliases(config, args[0])
+	}
+
+	records, err := NewHistoryStore(config).List()
+	if err != nil {
+		return err
+	}
+
+	var scores []ProblemScore
+	for _, score := range buildScoreboard(records, time.Time{}) {
+		if problemID == "" || score.ProblemID == problemID {
+			scores = append(scores, score)
+		}
+	}
+	if len(scores) == 0 {
+		yellow.Println("⚠️  No runs recorded yet")
+		return nil
+	}
+
+	var solved int
+	var total time.Duration
+	fmt.Printf("%-8s %-5s %-9s %-16s %s\n", "PROBLEM", "RUNS", "BEST", "FIRST RUN", "TIME TO SOLVE")
+	for _, score := range scores {
+		fmt.Printf("%-8s %-5d ", score.ProblemID, score.Runs)
+		best := fmt.Sprintf("%d/%d", score.BestPassed, score.BestTotal)
+		if score.Solved {
+			green.Printf("%-9s", best)
+		} else {
+			red.Printf("%-9s", best)
+		}
+		fmt.Printf(" %-16s ", score.FirstRun.Local().Format("2006-01-02 15:04"))
+		if score.Solved {
+			solved++
+			total += score.TimeSpent()
+			green.Println(formatElapsed(score.TimeSpent()))
+		} else {
+			yellow.Printf("%s so far\n", formatElapsed(score.TimeSpent()))
+		}
+	}
+
+	fmt.Println()
+	if solved > 0 {
+		cyan.Printf("⏱️  Solved %d/%d problem(s), %s on average from the first run to all tests passing\n",
+			solved, len(scores), formatElapsed(total/time.Duration(solved)))
+	} else {
+		cyan.Printf("⏱️  Solved 0/%d problem(s)\n", len(scores))
+	}
+	return nil
+}
+
+// showTimeToSolve prints the time from the first run of a problem to its
+// first all-pass run, below the problem's history
+func showTimeToSolve(records []*RunRecord, problemID string) {
+	for _, score := range buildScoreboard(records, time.Time{}) {
+		if score.ProblemID != problemID {
+			continue
+		}
+
+		fmt.Println()
+		if score.Solved {
+			cyan.Printf("⏱️  Time to solve: %s (first run %s, all tests passed %s, %d failing run(s) before)\n",
+				formatElapsed(score.TimeSpent()), score.FirstRun.Local().Format("2006-01-02 15:04"),
+				score.SolvedAt.Local().Format("2006-01-02 15:04"), score.FailedBeforeSolve)
+		} else {
+			cyan.Printf("⏱️  Not solved yet after %s of runs (first run %s)\n",
+				formatElapsed(score.TimeSpent()), score.FirstRun.Local().Format("2006-01-02 15:04"))
+		}
+	}
+}
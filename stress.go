@@ -0,0 +1,367 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// bruteTimeoutFactor gives the brute force solution this many times -timeout,
+// since it is expected to be slow
+const bruteTimeoutFactor = 10
+
+// StressOptions are the flags of the stress command
+type StressOptions struct {
+	Brute      string // trusted (slow) solution whose output is taken as correct
+	Generator  string // program printing a random input for the seed in its first argument
+	Spec       string // -gen-spec, used instead of a generator program
+	Iterations int
+	SeedRange  string // -seed-range, e.g. 1..10000: try every seed instead of stopping at the first failure
+}
+
+// maxListedSeeds is how many failing seeds a -seed-range run lists
+const maxListedSeeds = 20
+
+// stressRun holds what every seed of a stress test is checked with
+type stressRun struct {
+	config    *Config
+	solution  *stressProgram
+	brute     *stressProgram
+	generator *stressProgram // nil when inputs come from spec
+	spec      *GenSpec
+}
+
+// stressFailure is a seed on which the solution disagreed with the brute force
+type stressFailure struct {
+	seed                    int
+	input, expected, actual string
+	err                     error // the solution's runtime error, if any
+}
+
+// stressProgram is a compiled solution, brute force or generator
+type stressProgram struct {
+	path     string
+	executor *TestExecutor
+}
+
+// handleStress compares the solution against a brute force solution on random
+// inputs from a generator program or a -gen-spec. Each seed always produces the
+// same input, so a failure can be reproduced by its seed.
+func handleStress(config *Config, options StressOptions) error {
+	if err := validateStressOptions(config, options); err != nil {
+		return withExitCode(ExitUsageError, err)
+	}
+
+	var spec *GenSpec
+	if options.Spec != "" {
+		var err error
+		if spec, err = ParseGenSpec(options.Spec); err != nil {
+			return withExitCode(ExitUsageError, fmt.Errorf("invalid -gen-spec: %w", err))
+		}
+	}
+
+	if err := NewGoCompiler(config).ValidateGo(); err != nil {
+		return withExitCode(ExitCompileError, fmt.Errorf("Go validation failed: %w", err))
+	}
+
+	solution, err := compileStressProgram(config, config.FilePath, config.GetTimeout())
+	if err != nil {
+		return err
+	}
+	defer os.Remove(solution.path)
+
+	brute, err := compileStressProgram(config, options.Brute, bruteTimeoutFactor*config.GetTimeout())
+	if err != nil {
+		return err
+	}
+	defer os.Remove(brute.path)
+
+	var generator *stressProgram
+	if options.Generator != "" {
+		if generator, err = compileStressProgram(config, options.Generator, config.GetTimeout()); err != nil {
+			return err
+		}
+		defer os.Remove(generator.path)
+	}
+
+	first, last := 1, options.Iterations
+	if options.SeedRange != "" {
+		first, last, _ = parseSeedRange(options.SeedRange)
+	}
+	workers := config.Parallel
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	run := &stressRun{config: config, solution: solution, brute: brute, generator: generator, spec: spec}
+	yellow.Printf("🧪 Stress testing %s against %s on seeds %d..%d (parallel: %d)...\n", config.FilePath, options.Brute, first, last, workers)
+	startTime := time.Now()
+
+	// Without -seed-range the first failure stops the run
+	passed, failures, err := run.checkSeeds(first, last, workers, options.SeedRange == "")
+	NewNotifier(config).NotifyStress(time.Since(startTime), passed, failures, err)
+	if err != nil {
+		return err
+	}
+
+	if len(failures) == 0 {
+		green.Printf("✅ All %d random inputs passed (%.2fs)\n", last-first+1, time.Since(startTime).Seconds())
+		return nil
+	}
+
+	if options.SeedRange != "" {
+		seeds := make([]string, 0, maxListedSeeds)
+		for _, failure := range failures {
+			if len(seeds) == maxListedSeeds {
+				seeds = append(seeds, "...")
+				break
+			}
+			seeds = append(seeds, strconv.Itoa(failure.seed))
+		}
+		red.Printf("❌ %d of %d seeds failed: %s\n", len(failures), last-first+1, strings.Join(seeds, ", "))
+	}
+	reportStressFailure(config, failures[0])
+	for _, failure := range failures[1:] {
+		saveStressInput(config, failure)
+	}
+	return ErrTestsFailed
+}
+
+// checkSeeds checks every seed in [first, last] on a pool of workers and
+// returns how many passed and the failures ordered by seed. With stopEarly,
+// workers stop taking seeds once one has failed.
+func (s *stressRun) checkSeeds(first, last, workers int, stopEarly bool) (int, []stressFailure, error) {
+	seeds := make(chan int)
+	var (
+		mu       sync.Mutex
+		failures []stressFailure
+		firstErr error
+		passed   atomic.Int64
+		stopped  atomic.Bool
+		wg       sync.WaitGroup
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for seed := range seeds {
+				if stopped.Load() {
+					continue
+				}
+				failure, err := s.check(seed)
+				if err == nil && failure == nil {
+					passed.Add(1)
+					if s.config.Verbose {
+						green.Printf("✅ Seed %d passed\n", seed)
+					}
+					continue
+				}
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				if failure != nil {
+					failures = append(failures, *failure)
+				}
+				mu.Unlock()
+				if err != nil || stopEarly {
+					stopped.Store(true)
+				}
+			}
+		}()
+	}
+
+	for seed := first; seed <= last && !stopped.Load(); seed++ {
+		seeds <- seed
+	}
+	close(seeds)
+	wg.Wait()
+
+	if firstErr != nil {
+		return int(passed.Load()), nil, firstErr
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].seed < failures[j].seed })
+	return int(passed.Load()), failures, nil
+}
+
+// check runs one seed and returns a failure if the solution disagreed with
+// the brute force; an error means the seed could not be checked at all
+func (s *stressRun) check(seed int) (*stressFailure, error) {
+	var input string
+	var err error
+	if s.generator != nil {
+		input, err = s.generator.generate(uint64(seed))
+	} else {
+		input, err = s.spec.Generate(uint64(seed))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate input for seed %d: %w", seed, err)
+	}
+
+	expected, err := s.brute.run(input)
+	if err != nil {
+		return nil, fmt.Errorf("brute force failed on seed %d: %w", seed, err)
+	}
+
+	actual, runErr := s.solution.run(input)
+	if runErr == nil && s.solution.executor.compareOutputs(actual, expected) {
+		return nil, nil
+	}
+	return &stressFailure{seed: seed, input: input, expected: expected, actual: actual, err: runErr}, nil
+}
+
+// parseSeedRange parses a -seed-range such as 1..10000
+func parseSeedRange(seedRange string) (int, int, error) {
+	lo, hi, ok := strings.Cut(seedRange, "..")
+	first, errFirst := strconv.Atoi(lo)
+	last, errLast := strconv.Atoi(hi)
+	if !ok || errFirst != nil || errLast != nil || first < 0 || first > last {
+		return 0, 0, fmt.Errorf("invalid seed range %q (e.g. 1..10000)", seedRange)
+	}
+	return first, last, nil
+}
+
+func validateStressOptions(config *Config, options StressOptions) error {
+	if err := validateSolutionFile(config); err != nil {
+		return err
+	}
+	if options.Brute == "" {
+		return fmt.Errorf("the -brute flag is required")
+	}
+	bruteConfig := *config
+	bruteConfig.FilePath = options.Brute
+	if err := validateSolutionFile(&bruteConfig); err != nil {
+		return fmt.Errorf("-brute: %w", err)
+	}
+
+	switch {
+	case options.Generator == "" && options.Spec == "":
+		return fmt.Errorf("either -gen or -gen-spec is required")
+	case options.Generator != "" && options.Spec != "":
+		return fmt.Errorf("-gen and -gen-spec cannot be combined")
+	}
+	if options.SeedRange != "" {
+		if _, _, err := parseSeedRange(options.SeedRange); err != nil {
+			return err
+		}
+	} else if options.Iterations < 1 {
+		return fmt.Errorf("invalid iteration count %d", options.Iterations)
+	}
+
+	if config.Docker != "" {
+		return fmt.Errorf("stress does not support -docker")
+	}
+	if err := validateCompiler(config); err != nil {
+		return err
+	}
+	if _, err := time.ParseDuration(config.Timeout); err != nil {
+		return fmt.Errorf("invalid timeout %q", config.Timeout)
+	}
+	return nil
+}
+
+// compileStressProgram builds one of the programs taking part in a stress test,
+// which then runs with the given time limit
+func compileStressProgram(config *Config, path string, limit time.Duration) (*stressProgram, error) {
+	programConfig := *config
+	programConfig.FilePath = path
+	programConfig.Timeout = limit.String()
+
+	if config.Verbose {
+		yellow.Printf("🔨 Compiling %s...\n", path)
+	}
+	executablePath, err := NewGoCompiler(&programConfig).Compile()
+	if err != nil {
+		var compileErr *CompileError
+		if errors.As(err, &compileErr) {
+			displayCompileError(&programConfig, compileErr)
+		}
+		return nil, withExitCode(ExitCompileError, fmt.Errorf("compilation of %s failed: %w", path, err))
+	}
+
+	return &stressProgram{path: executablePath, executor: NewTestExecutor(&programConfig)}, nil
+}
+
+func (p *stressProgram) run(input string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.executor.config.GetTimeout())
+	defer cancel()
+
+	output, err := p.executor.runGoProgram(ctx, p.path, input)
+	return output.Stdout, err
+}
+
+// generate runs the generator program with the seed as its only argument
+func (p *stressProgram) generate(seed uint64) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.executor.config.GetTimeout())
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.path, strconv.FormatUint(seed, 10))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("generator timed out (%s)", p.executor.config.GetTimeout())
+		}
+		return "", fmt.Errorf("generator failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// reportStressFailure shows the failing input and saves it
+func reportStressFailure(config *Config, failure stressFailure) {
+	if failure.err != nil {
+		red.Printf("❌ Seed %d: %v\n", failure.seed, failure.err)
+	} else {
+		red.Printf("❌ Seed %d: output differs from the brute force solution\n", failure.seed)
+	}
+
+	fmt.Printf("   📥 Input (truncated to %d chars):\n", config.MaxOutput)
+	fmt.Printf("   %s\n", indentStressOutput(failure.input, config.MaxOutput))
+	if failure.err == nil {
+		for _, mismatch := range shapeMismatch(failure.expected, failure.actual) {
+			yellow.Printf("   📐 Shape: %s\n", mismatch)
+		}
+		fmt.Printf("   📤 Expected (brute force):\n")
+		green.Printf("   %s\n", indentStressOutput(failure.expected, config.MaxOutput))
+		fmt.Printf("   📥 Actual:\n")
+		red.Printf("   %s\n", indentStressOutput(failure.actual, config.MaxOutput))
+	}
+
+	if inputPath, ok := saveStressInput(config, failure); ok {
+		cyan.Printf("💾 Saved input to %s (re-run with `%s exec -file=%s -input=%s`)\n", inputPath, AppName, config.FilePath, inputPath)
+	}
+}
+
+func indentStressOutput(output string, max int) string {
+	return strings.ReplaceAll(truncateOutput(strings.TrimRight(output, "\n"), max), "\n", "\n   ")
+}
+
+// saveStressInput writes the input and the brute force output of a failing
+// seed to -failed-dir/stress/seed-<n>.in and .out
+func saveStressInput(config *Config, failure stressFailure) (string, bool) {
+	dir := filepath.Join(config.FailedDir, "stress")
+	inputPath := filepath.Join(dir, fmt.Sprintf("seed-%d.in", failure.seed))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", false
+	}
+	if err := os.WriteFile(inputPath, []byte(failure.input), 0644); err != nil {
+		return "", false
+	}
+	os.WriteFile(filepath.Join(dir, fmt.Sprintf("seed-%d.out", failure.seed)), []byte(failure.expected), 0644)
+	return inputPath, true
+}
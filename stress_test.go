@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestParseSeedRange(t *testing.T) {
+	tests := []struct {
+		in          string
+		first, last int
+		wantErr     bool
+	}{
+		{"1..10000", 1, 10000, false},
+		{"0..0", 0, 0, false},
+		{"5..5", 5, 5, false},
+		{"10..1", 0, 0, true},
+		{"-1..5", 0, 0, true},
+		{"1-10", 0, 0, true},
+		{"1..", 0, 0, true},
+		{"..10", 0, 0, true},
+		{"a..b", 0, 0, true},
+		{"", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			first, last, err := parseSeedRange(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSeedRange(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if first != tt.first || last != tt.last {
+				t.Errorf("parseSeedRange(%q) = %d, %d, want %d, %d", tt.in, first, last, tt.first, tt.last)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultSubmitLanguage is the language solutions are submitted in unless
+// -submit-lang chooses another
+const defaultSubmitLanguage = "Go"
+
+// maxSubmitSize is the largest source file CSES accepts
+const maxSubmitSize = 128 << 10
+
+// How often and how long submit waits for the judge
+const (
+	submitPollInterval = time.Second
+	submitPollTimeout  = 2 * time.Minute
+)
+
+var (
+	resultStatusPattern  = regexp.MustCompile(`id="status"[^>]*>([^<]*)<`)
+	resultVerdictPattern = regexp.MustCompile(`(?s)<td>Result:</td>\s*<td>(.*?)</td>`)
+	submitSelectPattern  = regexp.MustCompile(`(?s)<select[^>]*name="(lang|option)"[^>]*>(.*?)</select>`)
+	submitOptionPattern  = regexp.MustCompile(`(?s)<option([^>]*)>(.*?)</option>`)
+	optionValuePattern   = regexp.MustCompile(`value="([^"]*)"`)
+)
+
+// SubmitOptions are the flags of the submit command
+type SubmitOptions struct {
+	IfPass           bool // run the tests first and only submit if they pass
+	Force            bool // submit even if the predicted verdict is TLE or MLE
+	RequireLocalPass bool // only submit a source whose full local run passed
+	DryRun           bool // check and show the submission without sending it
+
+	// Language is the -submit-lang choice, a language of the submit form
+	// optionally followed by its variant: Go, C++:C++17, Python3:PyPy3
+	Language string
+}
+
+// SubmitLanguages are the choices of a problem's submit form: the languages
+// and the variants (compiler versions, interpreters) some of them come in
+type SubmitLanguages struct {
+	Languages []string
+	Options   []string
+}
+
+// parseSubmitLanguages reads the language and variant selects of a submit form
+func parseSubmitLanguages(page string) SubmitLanguages {
+	var languages SubmitLanguages
+	for _, selectMatch := range submitSelectPattern.FindAllStringSubmatch(page, -1) {
+		var values []string
+		for _, option := range submitOptionPattern.FindAllStringSubmatch(selectMatch[2], -1) {
+			value := strings.TrimSpace(html.UnescapeString(option[2]))
+			if matches := optionValuePattern.FindStringSubmatch(option[1]); matches != nil {
+				value = html.UnescapeString(matches[1])
+			}
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+		if selectMatch[1] == "lang" {
+			languages.Languages = values
+		} else {
+			languages.Options = values
+		}
+	}
+	return languages
+}
+
+// resolve matches a -submit-lang choice, ignoring case, against the form's
+// choices and returns the language and variant as the form spells them
+func (l SubmitLanguages) resolve(choice string) (string, string, error) {
+	name, variant, _ := strings.Cut(choice, ":")
+	language := matchChoice(l.Languages, strings.TrimSpace(name))
+	if language == "" {
+		return "", "", fmt.Errorf("CSES offers no language %q (available: %s)", name, strings.Join(l.Languages, ", "))
+	}
+	if variant = strings.TrimSpace(variant); variant == "" {
+		return language, "", nil
+	}
+	option := matchChoice(l.Options, variant)
+	if option == "" {
+		return "", "", fmt.Errorf("CSES offers no variant %q (available: %s)", variant, strings.Join(l.Options, ", "))
+	}
+	return language, option, nil
+}
+
+func matchChoice(choices []string, name string) string {
+	for _, choice := range choices {
+		if strings.EqualFold(choice, name) {
+			return choice
+		}
+	}
+	return ""
+}
+
+// handleSubmit implements `submit -file=<solution> -problem=<id>`: the
+// solution is sent to CSES and the judge's verdict awaited. With -if-pass the
+// local tests run first, and a failing run or a predicted TLE/MLE (unless
+// -force) stops the submission. -require-local-pass only insists on a passing
+// run, and reuses the recorded one if the source has not changed since.
+// -submit-lang picks another language of the form than Go, and -dry-run shows
+// what would be sent, after the same checks, without sending it.
+func handleSubmit(config *Config, options SubmitOptions) error {
+	if err := validateRunConfig(config); err != nil {
+		return withExitCode(ExitUsageError, err)
+	}
+	if isImportedProblem(config.ProblemID) {
+		return withExitCode(ExitUsageError, fmt.Errorf("problem %s was imported from another judge and cannot be submitted to CSES", config.ProblemID))
+	}
+
+	auth := NewCSESAuth(config)
+	if err := auth.EnsureAuthenticated(); err != nil {
+		return withExitCode(ExitFetchError, fmt.Errorf("authentication failed: %w", err))
+	}
+
+	if options.IfPass {
+		runner := NewTestRunner(config, auth)
+		if err := runner.Run(); err != nil {
+			if errors.Is(err, ErrTestsFailed) {
+				return fmt.Errorf("not submitting: the solution fails locally")
+			}
+			return err
+		}
+
+		prediction := runner.prediction
+		if prediction != nil && (prediction.Verdict == VerdictTimeLimit || prediction.Verdict == VerdictMemoryLimit) {
+			if !options.Force {
+				return fmt.Errorf("not submitting: the likely verdict is %s (%s); -force submits anyway", prediction.Verdict.Description(), prediction.Reason)
+			}
+			yellow.Printf("⚠️  Submitting despite the likely verdict %s (-force)\n", prediction.Verdict.Description())
+		}
+		fmt.Println()
+	} else if options.RequireLocalPass {
+		if err := requireLocalPass(config, auth); err != nil {
+			return err
+		}
+	}
+
+	source, err := os.ReadFile(config.FilePath)
+	if err != nil {
+		return withExitCode(ExitUsageError, fmt.Errorf("failed to read solution: %w", err))
+	}
+
+	choice := options.Language
+	if choice == "" {
+		choice = defaultSubmitLanguage
+	}
+	form, err := auth.FetchSubmitForm(config.ProblemID)
+	if err != nil {
+		return withExitCode(ExitFetchError, err)
+	}
+	languages := parseSubmitLanguages(form)
+	if len(languages.Languages) == 0 {
+		return withExitCode(ExitFetchError, fmt.Errorf("the submit form of problem %s lists no languages", config.ProblemID))
+	}
+	language, option, err := languages.resolve(choice)
+	if err != nil {
+		return withExitCode(ExitUsageError, err)
+	}
+	described := language
+	if option != "" {
+		described += " (" + option + ")"
+	}
+	if len(source) > maxSubmitSize {
+		return withExitCode(ExitUsageError, fmt.Errorf("%s is %s, more than the %s CSES accepts", config.FilePath, formatBytes(int64(len(source))), formatBytes(maxSubmitSize)))
+	}
+
+	if options.DryRun {
+		sum := sha256.Sum256(source)
+		cyan.Println("📝 Dry run, nothing is sent:")
+		fmt.Printf("   Problem:  %s\n", config.ProblemID)
+		fmt.Printf("   File:     %s\n", filepath.Base(config.FilePath))
+		fmt.Printf("   Language: %s\n", described)
+		fmt.Printf("   SHA-256:  %s\n", hex.EncodeToString(sum[:]))
+		fmt.Printf("   Size:     %s (limit %s)\n", formatBytes(int64(len(source))), formatBytes(maxSubmitSize))
+		green.Println("✅ The submission would be accepted for judging")
+		return nil
+	}
+
+	yellow.Printf("📤 Submitting %s to problem %s as %s...\n", config.FilePath, config.ProblemID, described)
+	resultURL, err := auth.SubmitSolution(config.ProblemID, filepath.Base(config.FilePath), source, language, option)
+	if errors.Is(err, ErrSessionExpired) {
+		yellow.Println("🔐 Session expired, re-authenticating...")
+		if err := auth.Login(); err != nil {
+			return withExitCode(ExitFetchError, fmt.Errorf("re-authentication failed: %w", err))
+		}
+		resultURL, err = auth.SubmitSolution(config.ProblemID, filepath.Base(config.FilePath), source, language, option)
+	}
+	if err != nil {
+		return withExitCode(ExitFetchError, err)
+	}
+	cyan.Printf("🔗 %s\n", resultURL)
+
+	verdict, err := awaitVerdict(auth, resultURL)
+	if err != nil {
+		return withExitCode(ExitFetchError, err)
+	}
+
+	if strings.EqualFold(verdict, "ACCEPTED") {
+		green.Printf("🎉 CSES verdict: %s\n", verdict)
+		return nil
+	}
+	red.Printf("💥 CSES verdict: %s\n", verdict)
+	replayJudgeTest(config, auth, resultURL)
+	return ErrTestsFailed
+}
+
+// requireLocalPass checks that the solution passes every local test, from the
+// history when a full run of this source on the same tests, compiler, options
+// and time limit is recorded, or by running it
+func requireLocalPass(config *Config, auth *CSESAuth) error {
+	runner := NewTestRunner(config, auth)
+	testCases, err := runner.fetcher.FetchTestCases(config.ProblemID)
+	if err != nil {
+		return withExitCode(ExitFetchError, fmt.Errorf("failed to fetch test cases: %w", err))
+	}
+	testsHash, err := hashTestSet(testCases)
+	if err != nil {
+		return withExitCode(ExitFetchError, err)
+	}
+
+	if latest := findCachedRun(config, testsHash); latest != nil {
+		when := latest.StartedAt.Local().Format("2006-01-02 15:04")
+		if !latest.AllPassed() {
+			return fmt.Errorf("not submitting: this source failed %d/%d local tests (run of %s)", latest.Failed, latest.Total, when)
+		}
+		green.Printf("✅ This source passed all %d local tests (run of %s)\n", latest.Total, when)
+		return nil
+	}
+
+	if err := runner.Run(); err != nil {
+		if errors.Is(err, ErrTestsFailed) {
+			return fmt.Errorf("not submitting: the solution fails locally")
+		}
+		return err
+	}
+	fmt.Println()
+	return nil
+}
+
+// awaitVerdict polls a submission's result page until the judge is done
+func awaitVerdict(auth *CSESAuth, resultURL string) (string, error) {
+	deadline := time.Now().Add(submitPollTimeout)
+	for {
+		page, err := auth.FetchSubmissionResult(resultURL)
+		if err != nil {
+			return "", err
+		}
+
+		status := ""
+		if matches := resultStatusPattern.FindStringSubmatch(page); matches != nil {
+			status = strings.TrimSpace(matches[1])
+		}
+		if strings.EqualFold(status, "READY") || strings.EqualFold(status, "COMPILE ERROR") {
+			if matches := resultVerdictPattern.FindStringSubmatch(page); matches != nil {
+				if verdict := strings.TrimSpace(statsTagPattern.ReplaceAllString(matches[1], "")); verdict != "" {
+					return verdict, nil
+				}
+			}
+			return status, nil
+		}
+
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("no verdict after %s (last status %q); see %s", submitPollTimeout, status, resultURL)
+		}
+		if status != "" {
+			fmt.Printf("   ⏳ %s\n", strings.ToLower(status))
+		}
+		time.Sleep(submitPollInterval)
+	}
+}
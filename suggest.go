@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxAlternatives is how many other candidates suggest lists after its pick
+const maxAlternatives = 3
+
+// handleSuggest picks an unsolved problem to practice. CSES orders its
+// categories roughly by difficulty, so the pick is from the first category
+// with unsolved problems (or the one named in args), preferring problems
+// already attempted and then those most users have solved.
+func handleSuggest(config *Config, args []string) error {
+	if len(args) > 1 {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: suggest [category]"))
+	}
+
+	index, err := LoadProblemsetIndex(config, NewCSESAuth(config))
+	if err != nil {
+		return withExitCode(ExitFetchError, err)
+	}
+
+	solvedLocally := make(map[string]bool)
+	if records, err := NewHistoryStore(config).List(); err == nil {
+		for _, record := range records {
+			if record.AllPassed() {
+				solvedLocally[record.ProblemID] = true
+			}
+		}
+	}
+
+	category := ""
+	if len(args) == 1 {
+		if category, err = index.matchCategory(args[0]); err != nil {
+			return withExitCode(ExitUsageError, err)
+		}
+	}
+
+	var candidates []ProblemInfo
+	solved := make(map[string]int)
+	for _, problem := range index.Problems {
+		if problem.Solved || solvedLocally[problem.ID] {
+			solved[problem.Category]++
+			continue
+		}
+		if category == "" || problem.Category == category {
+			candidates = append(candidates, problem)
+		}
+	}
+
+	if len(candidates) == 0 {
+		if category != "" {
+			green.Printf("🎉 Every problem in %s is solved\n", category)
+		} else {
+			green.Println("🎉 Every problem is solved")
+		}
+		return nil
+	}
+
+	// Stay in the first unfinished category
+	category = candidates[0].Category
+	var pool []ProblemInfo
+	for _, problem := range candidates {
+		if problem.Category == category {
+			pool = append(pool, problem)
+		}
+	}
+
+	sort.SliceStable(pool, func(i, j int) bool {
+		if pool[i].Attempted != pool[j].Attempted {
+			return pool[i].Attempted
+		}
+		return pool[i].Solvers > pool[j].Solvers
+	})
+
+	total := len(pool) + solved[category]
+	pick := pool[0]
+
+	cyan.Printf("💡 Try %s — %s\n", pick.ID, pick.Name)
+	fmt.Printf("   Category: %s (%d/%d solved)\n", category, solved[category], total)
+	fmt.Printf("   %s\n", suggestionReason(pick))
+	fmt.Printf("   %s -file=%s.go -problem=%s\n", AppName, pick.ID, pick.ID)
+
+	if len(pool) > 1 {
+		var others []string
+		for _, problem := range pool[1:] {
+			if len(others) == maxAlternatives {
+				break
+			}
+			others = append(others, fmt.Sprintf("%s %s", problem.ID, problem.Name))
+		}
+		fmt.Printf("\n   Also unsolved here: %s\n", strings.Join(others, ", "))
+	}
+
+	return nil
+}
+
+func suggestionReason(problem ProblemInfo) string {
+	if problem.Attempted {
+		return "You attempted it before but have not solved it yet"
+	}
+	if problem.Attempts > 0 {
+		return fmt.Sprintf("Solved by %d of %d users who tried it (%.0f%%)",
+			problem.Solvers, problem.Attempts, 100*float64(problem.Solvers)/float64(problem.Attempts))
+	}
+	return "The next unsolved problem in this category"
+}
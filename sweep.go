@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// gogcSweepValues are the GOGC settings tried by -gogc-sweep
+var gogcSweepValues = []string{"50", "100", "200", "400", "800", "off"}
+
+// gogcSweepRepeats is how many times each setting is measured; the fastest run counts
+const gogcSweepRepeats = 3
+
+type sweepResult struct {
+	GOGC     string
+	Best     time.Duration
+	Passed   bool
+	Failures int
+}
+
+// runGOGCSweep re-runs the slowest passing test under several GOGC values and reports
+// which one is fastest for this solution
+func (r *TestRunner) runGOGCSweep(ctx context.Context, executablePath string, testCases []TestCase, results []TestResult) {
+	// Only a passing test gives meaningful timings
+	slowest := -1
+	for i, result := range results {
+		if result.Passed && (slowest == -1 || result.Duration > results[slowest].Duration) {
+			slowest = i
+		}
+	}
+	if slowest == -1 {
+		yellow.Println("⚠️  Skipping GOGC sweep: no passing test to benchmark")
+		return
+	}
+
+	testCase, err := testCases[slowest].Load()
+	if err != nil {
+		yellow.Printf("⚠️  Skipping GOGC sweep: %v\n", err)
+		return
+	}
+	yellow.Printf("🧹 Sweeping GOGC on the slowest passing test (%d, %.2fms)...\n", results[slowest].TestNumber, results[slowest].Duration.Seconds()*1000)
+
+	var sweep []sweepResult
+	for _, gogc := range gogcSweepValues {
+		config := *r.config
+		config.SolutionGOGC = gogc
+		executor := NewTestExecutor(&config)
+		executor.sandbox = r.executor.sandbox
+
+		entry := sweepResult{GOGC: gogc, Passed: true}
+		for i := 0; i < gogcSweepRepeats; i++ {
+			if ctx.Err() != nil {
+				return
+			}
+
+			testCtx, cancel := context.WithTimeout(ctx, r.config.GetTimeout())
+			result := executor.Execute(testCtx, executablePath, testCase)
+			cancel()
+
+			if !result.Passed {
+				entry.Passed = false
+				entry.Failures++
+				continue
+			}
+			if entry.Best == 0 || result.Duration < entry.Best {
+				entry.Best = result.Duration
+			}
+		}
+
+		sweep = append(sweep, entry)
+	}
+
+	r.displaySweep(sweep)
+}
+
+func (r *TestRunner) displaySweep(sweep []sweepResult) {
+	ranked := make([]sweepResult, 0, len(sweep))
+	for _, entry := range sweep {
+		if entry.Best > 0 {
+			ranked = append(ranked, entry)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].Best < ranked[j].Best })
+
+	fmt.Println("\n" + strings.Repeat("-", 40))
+	white.Printf("🧹 GOGC SWEEP (best of %d)\n", gogcSweepRepeats)
+	fmt.Println(strings.Repeat("-", 40))
+
+	for _, entry := range sweep {
+		line := fmt.Sprintf("   GOGC=%-5s", entry.GOGC)
+		if entry.Best > 0 {
+			line += fmt.Sprintf(" %10.2fms", entry.Best.Seconds()*1000)
+		} else {
+			line += fmt.Sprintf(" %12s", "-")
+		}
+		if entry.Failures > 0 {
+			line += fmt.Sprintf("  (%d/%d runs failed)", entry.Failures, gogcSweepRepeats)
+		}
+
+		if len(ranked) > 0 && entry.GOGC == ranked[0].GOGC {
+			green.Println(line + "  ⭐")
+		} else if !entry.Passed {
+			red.Println(line)
+		} else {
+			fmt.Println(line)
+		}
+	}
+
+	if len(ranked) > 0 {
+		cyan.Printf("💡 Fastest setting: -solution-gogc=%s\n", ranked[0].GOGC)
+	}
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// defaultTemplate is used by `new` when -template is not given
+const defaultTemplate = "fastio"
+
+// builtinTemplates are available without any setup. A user template with the
+// same name takes precedence.
+var builtinTemplates = map[string]string{
+	"fastio": `// {{.ProblemID}}{{if .Name}} - {{.Name}}{{end}}
+// {{.URL}}
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+var (
+	reader = bufio.NewReaderSize(os.Stdin, 1<<20)
+	writer = bufio.NewWriterSize(os.Stdout, 1<<20)
+)
+
+func main() {
+	defer writer.Flush()
+
+	var n int
+	fmt.Fscan(reader, &n)
+	fmt.Fprintln(writer, n)
+}
+`,
+	"segtree": `// {{.ProblemID}}{{if .Name}} - {{.Name}}{{end}}
+// {{.URL}}
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+var (
+	reader = bufio.NewReaderSize(os.Stdin, 1<<20)
+	writer = bufio.NewWriterSize(os.Stdout, 1<<20)
+)
+
+// SegmentTree answers range sum queries with point updates
+type SegmentTree struct {
+	n    int
+	tree []int64
+}
+
+func NewSegmentTree(values []int64) *SegmentTree {
+	n := len(values)
+	t := &SegmentTree{n: n, tree: make([]int64, 2*n)}
+	copy(t.tree[n:], values)
+	for i := n - 1; i > 0; i-- {
+		t.tree[i] = t.tree[2*i] + t.tree[2*i+1]
+	}
+	return t
+}
+
+// Set assigns values[i] = v
+func (t *SegmentTree) Set(i int, v int64) {
+	i += t.n
+	t.tree[i] = v
+	for i > 1 {
+		i /= 2
+		t.tree[i] = t.tree[2*i] + t.tree[2*i+1]
+	}
+}
+
+// Query returns the sum of values[l:r]
+func (t *SegmentTree) Query(l, r int) int64 {
+	var sum int64
+	for l, r = l+t.n, r+t.n; l < r; l, r = l/2, r/2 {
+		if l&1 == 1 {
+			sum += t.tree[l]
+			l++
+		}
+		if r&1 == 1 {
+			r--
+			sum += t.tree[r]
+		}
+	}
+	return sum
+}
+
+func main() {
+	defer writer.Flush()
+
+	var n, q int
+	fmt.Fscan(reader, &n, &q)
+	values := make([]int64, n)
+	for i := range values {
+		fmt.Fscan(reader, &values[i])
+	}
+	tree := NewSegmentTree(values)
+
+	for ; q > 0; q-- {
+		var l, r int
+		fmt.Fscan(reader, &l, &r)
+		fmt.Fprintln(writer, tree.Query(l-1, r))
+	}
+}
+`,
+	"dsu": `// {{.ProblemID}}{{if .Name}} - {{.Name}}{{end}}
+// {{.URL}}
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+var (
+	reader = bufio.NewReaderSize(os.Stdin, 1<<20)
+	writer = bufio.NewWriterSize(os.Stdout, 1<<20)
+)
+
+// DSU is a disjoint set union with path halving and union by size
+type DSU struct {
+	parent []int
+	size   []int
+}
+
+func NewDSU(n int) *DSU {
+	d := &DSU{parent: make([]int, n), size: make([]int, n)}
+	for i := range d.parent {
+		d.parent[i] = i
+		d.size[i] = 1
+	}
+	return d
+}
+
+func (d *DSU) Find(x int) int {
+	for d.parent[x] != x {
+		d.parent[x] = d.parent[d.parent[x]]
+		x = d.parent[x]
+	}
+	return x
+}
+
+// Union merges the sets of a and b and reports whether they were separate
+func (d *DSU) Union(a, b int) bool {
+	a, b = d.Find(a), d.Find(b)
+	if a == b {
+		return false
+	}
+	if d.size[a] < d.size[b] {
+		a, b = b, a
+	}
+	d.parent[b] = a
+	d.size[a] += d.size[b]
+	return true
+}
+
+func main() {
+	defer writer.Flush()
+
+	var n, m int
+	fmt.Fscan(reader, &n, &m)
+	dsu := NewDSU(n + 1)
+	for ; m > 0; m-- {
+		var a, b int
+		fmt.Fscan(reader, &a, &b)
+		dsu.Union(a, b)
+	}
+}
+`,
+}
+
+// TemplateData is what templates can refer to, e.g. {{.ProblemID}} or {{.Name}}
+type TemplateData struct {
+	ProblemID string
+	Name      string // empty unless the problem list has been cached by `suggest`
+	URL       string
+	Date      string
+}
+
+// handleTemplate implements `template list`, `template add <name> <file>` and
+// `template use <name>` (scaffold -problem from the template)
+func handleTemplate(config *Config, args []string) error {
+	if len(args) == 0 {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: template list | add <name> <file> | use <name>"))
+	}
+
+	switch args[0] {
+	case "list":
+		return listTemplates(config)
+	case "add":
+		if len(args) != 3 {
+			return withExitCode(ExitUsageError, fmt.Errorf("usage: template add <name> <file>"))
+		}
+		return addTemplate(config, args[1], args[2])
+	case "use":
+		if len(args) != 2 {
+			return withExitCode(ExitUsageError, fmt.Errorf("usage: template use <name> -problem=<id> [-file=path]"))
+		}
+		return scaffoldSolution(config, args[1])
+	}
+
+	return withExitCode(ExitUsageError, fmt.Errorf("unknown template subcommand: %s", args[0]))
+}
+
+// handleNew scaffolds a solution for -problem from -template (fastio by default)
+func handleNew(config *Config, templateName string) error {
+	if templateName == "" {
+		templateName = defaultTemplate
+	}
+	return scaffoldSolution(config, templateName)
+}
+
+func listTemplates(config *Config) error {
+	names := make(map[string]string)
+	for name := range builtinTemplates {
+		names[name] = "built-in"
+	}
+
+	files, err := filepath.Glob(filepath.Join(config.GetTemplatesDir(), "*.go.tmpl"))
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".go.tmpl")
+		if _, builtin := names[name]; builtin {
+			names[name] = file + " (overrides built-in)"
+		} else {
+			names[name] = file
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		marker := "  "
+		if name == defaultTemplate {
+			marker = "* "
+		}
+		fmt.Printf("%s%-12s %s\n", marker, name, names[name])
+	}
+	fmt.Println("\n  * used by `new` unless -template is given")
+	return nil
+}
+
+func addTemplate(config *Config, name, file string) error {
+	if !validTemplateName(name) {
+		return withExitCode(ExitUsageError, fmt.Errorf("invalid template name %q", name))
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return withExitCode(ExitUsageError, fmt.Errorf("failed to read template: %w", err))
+	}
+	if _, err := template.New(name).Parse(string(data)); err != nil {
+		return withExitCode(ExitUsageError, fmt.Errorf("invalid template: %w", err))
+	}
+
+	if err := os.MkdirAll(config.GetTemplatesDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+
+	path := filepath.Join(config.GetTemplatesDir(), name+".go.tmpl")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save template: %w", err)
+	}
+
+	green.Printf("✅ Saved template %s to %s\n", name, path)
+	return nil
+}
+
+// validTemplateName rejects names that would point outside the templates directory
+func validTemplateName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, `/\.`)
+}
+
+// loadTemplate returns the user template called name, or the built-in one
+func loadTemplate(config *Config, name string) (string, error) {
+	if !validTemplateName(name) {
+		return "", withExitCode(ExitUsageError, fmt.Errorf("invalid template name %q", name))
+	}
+
+	data, err := os.ReadFile(filepath.Join(config.GetTemplatesDir(), name+".go.tmpl"))
+	if err == nil {
+		return string(data), nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+
+	if source, ok := builtinTemplates[name]; ok {
+		return source, nil
+	}
+	return "", withExitCode(ExitUsageError, fmt.Errorf("unknown template %q (see `template list`)", name))
+}
+
+// scaffoldSolution writes -file (or <problem>.go) from the named template,
+// never overwriting an existing file
+func scaffoldSolution(config *Config, name string) error {
+	if config.ProblemID == "" {
+		return withExitCode(ExitUsageError, fmt.Errorf("the -problem flag is required"))
+	}
+
+	path := config.FilePath
+	if path == "" {
+		path = config.ProblemID + ".go"
+	}
+	if _, err := os.Stat(path); err == nil {
+		return withExitCode(ExitUsageError, fmt.Errorf("%s already exists", path))
+	}
+
+	source, err := loadTemplate(config, name)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New(name).Parse(source)
+	if err != nil {
+		return withExitCode(ExitUsageError, fmt.Errorf("invalid template %s: %w", name, err))
+	}
+
+	data := TemplateData{
+		ProblemID: config.ProblemID,
+		Name:      cachedProblemName(config, config.ProblemID),
+		URL:       fmt.Sprintf("%s/problemset/task/%s", config.GetBaseURL(), config.ProblemID),
+		Date:      time.Now().Format("2006-01-02"),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	green.Printf("✅ Created %s from template %s\n", path, name)
+	fmt.Printf("   %s -file=%s -problem=%s\n", AppName, path, config.ProblemID)
+	return nil
+}
+
+// cachedProblemName looks the problem up in the cached problem list without
+// touching the network
+func cachedProblemName(config *Config, problemID string) string {
+	data, err := os.ReadFile(config.GetProblemsetPath())
+	if err != nil {
+		return ""
+	}
+
+	var index ProblemsetIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return ""
+	}
+
+	problem, _ := index.Find(problemID)
+	return problem.Name
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// dedupWriter writes files, hard linking a file to an earlier one with the
+// same content instead of storing it again. Generated suites often repeat
+// inputs, and the cache never modifies its files in place.
+type dedupWriter struct {
+	written map[[sha256.Size]byte]string
+	linked  int
+}
+
+func newDedupWriter() *dedupWriter {
+	return &dedupWriter{written: make(map[[sha256.Size]byte]string)}
+}
+
+func (w *dedupWriter) write(path, content string) error {
+	sum := sha256.Sum256([]byte(content))
+	if original, ok := w.written[sum]; ok {
+		os.Remove(path)
+		if err := os.Link(original, path); err == nil {
+			w.linked++
+			return nil
+		}
+		// Fall back to a copy where hard links are not supported
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+	w.written[sum] = path
+	return nil
+}
+
+// handleTests implements `tests list`, an overview of the cached tests of
+// -problem that points out tests repeating an earlier input, and
+// `tests import-samples`
+func handleTests(config *Config, args []string, sampleURL string) error {
+	if len(args) == 1 && args[0] == "import-samples" {
+		return handleImportSamples(config, sampleURL)
+	}
+	if len(args) > 1 || (len(args) == 1 && args[0] != "list") {
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: tests list -problem=<id> | tests import-samples -url=<problem-url>"))
+	}
+	if config.ProblemID == "" {
+		return withExitCode(ExitUsageError, fmt.Errorf("the -problem flag is required"))
+	}
+
+	testCases, err := newTestSource(config, NewCSESAuth(config)).FetchTestCases(config.ProblemID)
+	if err != nil {
+		return withExitCode(ExitFetchError, err)
+	}
+	sort.Slice(testCases, func(i, j int) bool { return testCases[i].Number < testCases[j].Number })
+
+	type firstSeen struct {
+		number       int
+		expectedHash [sha256.Size]byte
+	}
+	seen := make(map[[sha256.Size]byte]firstSeen)
+	duplicates := 0
+
+	fmt.Printf("%-6s %10s %10s  %s\n", "TEST", "INPUT", "OUTPUT", "NOTE")
+	for _, testCase := range testCases {
+		inputHash, expectedHash, inputSize, expectedSize, err := hashTestCase(testCase)
+		if err != nil {
+			return err
+		}
+
+		note := ""
+		if first, ok := seen[inputHash]; ok {
+			duplicates++
+			note = fmt.Sprintf("duplicate of test %d", first.number)
+			if first.expectedHash != expectedHash {
+				note += " (but a different expected output)"
+			}
+		} else {
+			seen[inputHash] = firstSeen{number: testCase.Number, expectedHash: expectedHash}
+		}
+
+		line := strings.TrimRight(fmt.Sprintf("%-6d %10s %10s  %s", testCase.Number, formatBytes(int64(inputSize)), formatBytes(int64(expectedSize)), note), " ")
+		if note != "" {
+			yellow.Println(line)
+		} else {
+			fmt.Println(line)
+		}
+	}
+
+	fmt.Println()
+	if duplicates > 0 {
+		yellow.Printf("🔁 %d of %d tests repeat an earlier input\n", duplicates, len(testCases))
+	} else {
+		green.Printf("✅ %d tests, all inputs distinct\n", len(testCases))
+	}
+	return nil
+}
+
+// hashTestCase returns the content hashes and sizes of a test's input and expected output
+func hashTestCase(testCase TestCase) (inputHash, expectedHash [sha256.Size]byte, inputSize, expectedSize int, err error) {
+	testCase, err = testCase.Load()
+	if err != nil {
+		return inputHash, expectedHash, 0, 0, fmt.Errorf("failed to read test %d: %w", testCase.Number, err)
+	}
+	return sha256.Sum256([]byte(testCase.Input)), sha256.Sum256([]byte(testCase.Expected)), len(testCase.Input), len(testCase.Expected), nil
+}
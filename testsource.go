@@ -0,0 +1,263 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TestSource provides the tests of a problem. The CSES download endpoint
+// (TestCaseFetcher) is the default; a manifest can point a problem elsewhere.
+type TestSource interface {
+	// Name describes where the tests come from, for the run output
+	Name() string
+	FetchTestCases(problemID string) ([]TestCase, error)
+}
+
+// TestSourceSpec is the `tests` setting of a manifest. Exactly one of Dir,
+// URL and Git is set; {id} in Dir, URL and Path is replaced by the problem ID.
+//
+//	tests:
+//	  dir: tests/{id}                  # N.in and N.out (or N.ans), or input/N.txt and output/N.txt
+//	tests:
+//	  url: https://example.com/{id}.zip
+//	tests:
+//	  git: https://github.com/user/cses-tests.git
+//	  ref: main                        # optional branch or tag
+//	  path: data/{id}                  # optional, default {id}
+type TestSourceSpec struct {
+	Dir  string `yaml:"dir"`
+	URL  string `yaml:"url"`
+	Git  string `yaml:"git"`
+	Ref  string `yaml:"ref"`
+	Path string `yaml:"path"`
+}
+
+// validate checks that the spec names exactly one source
+func (s *TestSourceSpec) validate() error {
+	set := 0
+	for _, value := range []string{s.Dir, s.URL, s.Git} {
+		if value != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("tests needs exactly one of dir, url and git")
+	}
+	if (s.Ref != "" || s.Path != "") && s.Git == "" {
+		return fmt.Errorf("tests ref and path only apply to git")
+	}
+	return nil
+}
+
+// newTestSource returns the source of the tests configured for the problem:
+// the manifest's `tests` setting, or CSES
+func newTestSource(config *Config, auth *CSESAuth) TestSource {
+	fetcher := NewTestCaseFetcher(config, auth)
+	switch spec := config.Tests; {
+	case spec == nil:
+		return fetcher
+	case spec.Dir != "":
+		return &dirTestSource{fetcher: fetcher, pattern: spec.Dir}
+	case spec.URL != "":
+		return &urlTestSource{fetcher: fetcher, pattern: spec.URL}
+	default:
+		return &gitTestSource{fetcher: fetcher, spec: *spec}
+	}
+}
+
+// expandProblemID replaces {id} in pattern with the problem ID
+func expandProblemID(pattern, problemID string) string {
+	return strings.ReplaceAll(pattern, "{id}", problemID)
+}
+
+// dirTestSource reads tests from a local directory
+type dirTestSource struct {
+	fetcher *TestCaseFetcher
+	pattern string
+}
+
+func (s *dirTestSource) Name() string {
+	return s.pattern
+}
+
+func (s *dirTestSource) FetchTestCases(problemID string) ([]TestCase, error) {
+	dir := expandProblemID(s.pattern, problemID)
+	if !strings.Contains(s.pattern, "{id}") {
+		dir = filepath.Join(dir, problemID)
+	}
+	return s.fetcher.loadTestDir(dir)
+}
+
+// urlTestSource downloads a zip archive of tests, like the CSES one, from a
+// URL template. Archives are cached per URL; -refresh-tests downloads again.
+type urlTestSource struct {
+	fetcher *TestCaseFetcher
+	pattern string
+}
+
+func (s *urlTestSource) Name() string {
+	return s.pattern
+}
+
+func (s *urlTestSource) FetchTestCases(problemID string) ([]TestCase, error) {
+	url := expandProblemID(s.pattern, problemID)
+	cacheDir := sourceCacheDir(s.fetcher.config, "url", url)
+
+	if cached, err := s.fetcher.loadCachedTestCases(cacheDir); err == nil && len(cached) > 0 && !s.fetcher.config.RefreshTests {
+		return cached, nil
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: status %d", url, resp.StatusCode)
+	}
+	zipData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+
+	testCases, err := s.fetcher.extractTestCasesFromZip(zipData)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+
+	// Replace rather than overwrite: cached files may be hard links
+	os.RemoveAll(cacheDir)
+	if err := s.fetcher.cacheTestCases(cacheDir, testCases); err != nil {
+		yellow.Printf("⚠️  Failed to cache test cases: %v\n", err)
+		return testCases, nil
+	}
+	if cached, err := s.fetcher.loadCachedTestCases(cacheDir); err == nil && len(cached) == len(testCases) {
+		return cached, nil
+	}
+	return testCases, nil
+}
+
+// gitTestSource reads tests from a Git repository of test data, cloned once
+// into the cache and pulled again with -refresh-tests
+type gitTestSource struct {
+	fetcher *TestCaseFetcher
+	spec    TestSourceSpec
+}
+
+func (s *gitTestSource) Name() string {
+	if s.spec.Ref != "" {
+		return s.spec.Git + "@" + s.spec.Ref
+	}
+	return s.spec.Git
+}
+
+func (s *gitTestSource) FetchTestCases(problemID string) ([]TestCase, error) {
+	clone := sourceCacheDir(s.fetcher.config, "git", s.Name())
+
+	if _, err := os.Stat(filepath.Join(clone, ".git")); os.IsNotExist(err) {
+		yellow.Printf("📥 Cloning %s...\n", s.Name())
+		args := []string{"clone", "--depth", "1"}
+		if s.spec.Ref != "" {
+			args = append(args, "--branch", s.spec.Ref)
+		}
+		if err := runGit(append(args, s.spec.Git, clone)...); err != nil {
+			os.RemoveAll(clone)
+			return nil, err
+		}
+	} else if s.fetcher.config.RefreshTests {
+		if err := runGit("-C", clone, "pull", "--ff-only"); err != nil {
+			yellow.Printf("⚠️  Using the tests already cloned: %v\n", err)
+		}
+	}
+
+	path := s.spec.Path
+	if path == "" {
+		path = "{id}"
+	}
+	return s.fetcher.loadTestDir(filepath.Join(clone, filepath.FromSlash(expandProblemID(path, problemID))))
+}
+
+func runGit(args ...string) error {
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s failed: %w\n%s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// sourceCacheDir is where the tests of a non-CSES source are cached, apart
+// from the CSES tests so the two never mix
+func sourceCacheDir(config *Config, kind, location string) string {
+	sum := sha256.Sum256([]byte(location))
+	return filepath.Join(config.CacheDir, "sources", kind+"-"+hex.EncodeToString(sum[:6]))
+}
+
+// loadTestDir reads the tests of a directory in any layout parseTestFile
+// knows, such as N.in with N.out or N.ans, or input/N.txt with output/N.txt
+func (f *TestCaseFetcher) loadTestDir(dir string) ([]TestCase, error) {
+	inputs := make(map[int]string)
+	outputs := make(map[int]string)
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		isInput, number := f.parseTestFile(filepath.ToSlash(rel))
+		if number <= 0 {
+			return nil
+		}
+		target := outputs
+		if isInput {
+			target = inputs
+		}
+		if _, seen := target[number]; !seen {
+			target[number] = path
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tests: %w", err)
+	}
+
+	warnIncompleteTests(inputs, outputs, dir)
+	var testCases []TestCase
+	for number, inputPath := range inputs {
+		outputPath, ok := outputs[number]
+		if !ok {
+			continue
+		}
+		inputInfo, err := os.Stat(inputPath)
+		if err != nil {
+			continue
+		}
+		outputInfo, err := os.Stat(outputPath)
+		if err != nil {
+			continue
+		}
+		testCases = append(testCases, TestCase{
+			Number:       number,
+			InputPath:    inputPath,
+			ExpectedPath: outputPath,
+			Size:         inputInfo.Size() + outputInfo.Size(),
+			lazy:         true,
+		})
+	}
+
+	if len(testCases) == 0 {
+		return nil, fmt.Errorf("no tests (N.in with N.out or N.ans, or input/N.txt with output/N.txt) in %s", dir)
+	}
+	sortTestCases(testCases)
+	return testCases, nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrUnavailable is matched by every error caused by CSES being down for
+// maintenance or overloaded, as opposed to a network or login problem
+var ErrUnavailable = errors.New("CSES is unavailable")
+
+// How often and how long a request is retried while CSES is unavailable;
+// a longer Retry-After is reported rather than waited for
+const (
+	unavailableRetries  = 2
+	unavailableBackoff  = 2 * time.Second
+	unavailableMaxDelay = 10 * time.Second
+)
+
+// maintenancePattern recognizes the maintenance page CSES serves instead of
+// the requested one
+var maintenancePattern = regexp.MustCompile(`(?i)(down for maintenance|under maintenance|maintenance break)`)
+
+// UnavailableError reports a 502/503/504 response or a maintenance page
+type UnavailableError struct {
+	Status      int
+	Maintenance bool
+	RetryAfter  time.Duration // 0 when CSES did not say
+}
+
+func (e *UnavailableError) Error() string {
+	message := e.summary()
+	if e.RetryAfter > 0 {
+		message += fmt.Sprintf(", retry in %s", e.RetryAfter)
+	}
+	return message
+}
+
+// summary is the error without the Retry-After advice
+func (e *UnavailableError) summary() string {
+	if e.Maintenance {
+		return "CSES is unavailable (down for maintenance)"
+	}
+	return fmt.Sprintf("CSES is unavailable (HTTP %d)", e.Status)
+}
+
+func (e *UnavailableError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+// unavailableTransport turns downtime responses into an UnavailableError, so
+// every caller can tell them from other failures, and retries idempotent
+// requests a couple of times first. Once retrying did not help, later
+// requests fail at once instead of waiting again.
+type unavailableTransport struct {
+	next http.RoundTripper
+	down atomic.Bool
+}
+
+func (t *unavailableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		unavailable, err := checkUnavailable(resp)
+		if err != nil || unavailable == nil {
+			return resp, err
+		}
+
+		delay := unavailable.RetryAfter
+		if delay == 0 {
+			delay = unavailableBackoff
+		}
+		if req.Method != http.MethodGet || attempt == unavailableRetries || delay > unavailableMaxDelay || t.down.Load() {
+			t.down.Store(true)
+			return nil, unavailable
+		}
+		yellow.Printf("⏳ %s, retrying in %s...\n", unavailable.summary(), delay)
+		time.Sleep(delay)
+	}
+}
+
+// checkUnavailable inspects a response for downtime. HTML pages are read to
+// look for the maintenance notice and handed back intact; other bodies, such
+// as test archives, are left alone.
+func checkUnavailable(resp *http.Response) (*UnavailableError, error) {
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		resp.Body.Close()
+		return &UnavailableError{Status: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}, nil
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if maintenancePattern.Match(body) {
+		return &UnavailableError{Status: resp.StatusCode, Maintenance: true, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}, nil
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return nil, nil
+}
+
+// unavailableCause returns the UnavailableError behind err, without the
+// request and wrapping messages around it
+func unavailableCause(err error) error {
+	var unavailable *UnavailableError
+	if errors.As(err, &unavailable) {
+		return unavailable
+	}
+	return err
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(when).Round(time.Second); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
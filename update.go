@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// releaseRepository is where releases of the runner are published
+const releaseRepository = "anurag5sh/cses-go-runner"
+
+// checksumsAsset lists the SHA-256 of every release binary, in sha256sum format
+const checksumsAsset = "checksums.txt"
+
+// githubRelease is the part of GET /repos/{repo}/releases/latest we use
+type githubRelease struct {
+	TagName string        `json:"tag_name"`
+	HTMLURL string        `json:"html_url"`
+	Assets  []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// handleUpdate replaces the running binary with the one for this platform
+// from the latest GitHub release, after checking it against the release's
+// checksums. With check, it only reports whether there is a newer release.
+func handleUpdate(check bool) error {
+	release, err := latestRelease()
+	if err != nil {
+		return err
+	}
+
+	latest := strings.TrimPrefix(release.TagName, "v")
+	if compareVersions(latest, AppVersion) <= 0 {
+		green.Printf("✅ %s v%s is up to date\n", AppName, AppVersion)
+		return nil
+	}
+	cyan.Printf("⬆️  %s v%s is available (installed: v%s): %s\n", AppName, latest, AppVersion, release.HTMLURL)
+	if check {
+		return nil
+	}
+
+	name := releaseAssetName()
+	binary, ok := release.asset(name)
+	if !ok {
+		return fmt.Errorf("release %s has no binary for %s/%s (%s)", release.TagName, runtime.GOOS, runtime.GOARCH, name)
+	}
+	checksums, ok := release.asset(checksumsAsset)
+	if !ok {
+		return fmt.Errorf("release %s has no %s to verify the download against", release.TagName, checksumsAsset)
+	}
+
+	sums, err := download(checksums.URL)
+	if err != nil {
+		return err
+	}
+	want, ok := lookupChecksum(sums, name)
+	if !ok {
+		return fmt.Errorf("%s of release %s has no entry for %s", checksumsAsset, release.TagName, name)
+	}
+
+	yellow.Printf("📥 Downloading %s...\n", name)
+	data, err := download(binary.URL)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, want, got)
+	}
+
+	path, err := replaceExecutable(data)
+	if err != nil {
+		return err
+	}
+	green.Printf("🎉 Updated %s to v%s\n", path, latest)
+	return nil
+}
+
+func latestRelease() (*githubRelease, error) {
+	data, err := download(fmt.Sprintf("%s/repos/%s/releases/latest", githubAPI(), releaseRepository))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the latest release: %w", err)
+	}
+
+	var release githubRelease
+	if err := json.Unmarshal(data, &release); err != nil || release.TagName == "" {
+		return nil, fmt.Errorf("unexpected release response")
+	}
+	return &release, nil
+}
+
+func (r *githubRelease) asset(name string) (githubAsset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return githubAsset{}, false
+}
+
+// releaseAssetName is the name of the release binary for this platform,
+// e.g. cses-go-runner_linux_amd64 or cses-go-runner_windows_amd64.exe
+func releaseAssetName() string {
+	name := fmt.Sprintf("%s_%s_%s", AppName, runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func download(url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", AppName+"/"+AppVersion)
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" && strings.HasPrefix(url, githubAPI()) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: status %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// lookupChecksum finds the hash of name in sha256sum output ("<hash>  <name>")
+func lookupChecksum(sums []byte, name string) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
+
+// replaceExecutable writes the new binary next to the running one and renames
+// it into place, so the old binary is replaced in one step or not at all
+func replaceExecutable(data []byte) (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	if path, err = filepath.EvalSymlinks(path); err != nil {
+		return "", fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+AppName+"-update-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to write to %s: %w", filepath.Dir(path), err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return "", fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := os.Chmod(temp.Name(), info.Mode().Perm()|0111); err != nil {
+		return "", err
+	}
+
+	if runtime.GOOS == "windows" {
+		// A running executable cannot be replaced on Windows, but it can be renamed
+		old := path + ".old"
+		os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return "", fmt.Errorf("failed to move the old binary aside: %w", err)
+		}
+		if err := os.Rename(temp.Name(), path); err != nil {
+			os.Rename(old, path)
+			return "", fmt.Errorf("failed to install the new binary: %w", err)
+		}
+		return path, nil
+	}
+
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to install the new binary: %w", err)
+	}
+	return path, nil
+}
+
+// compareVersions compares dotted versions such as 1.10.0 and 1.9.2
+// numerically, returning -1, 0 or 1
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(strings.SplitN(as[i], "-", 2)[0])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(strings.SplitN(bs[i], "-", 2)[0])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0", "1.99.99", 1},
+		{"1.2", "1.2.0", 0},
+		{"1.2", "1.2.1", -1},
+		{"1.3.0-rc1", "1.3.0", 0},
+		{"0.9.0", "1.0.0-beta", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLookupChecksum(t *testing.T) {
+	sums := []byte("ABCDEF01  cses-go-runner_linux_amd64\n" +
+		"23456789 *cses-go-runner_windows_amd64.exe\n" +
+		"malformed\n" +
+		"facefeed  cses-go-runner_linux_amd64.tar.gz\n")
+
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"cses-go-runner_linux_amd64", "abcdef01", true},
+		{"cses-go-runner_windows_amd64.exe", "23456789", true},
+		{"cses-go-runner_linux_amd64.tar.gz", "facefeed", true},
+		{"cses-go-runner_darwin_arm64", "", false},
+		{"malformed", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := lookupChecksum(sums, tt.name)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("lookupChecksum(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
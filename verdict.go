@@ -0,0 +1,35 @@
+package main
+
+// Verdict is the judge-style outcome of a single test
+type Verdict string
+
+const (
+	VerdictAccepted     Verdict = "AC"
+	VerdictWrongAnswer  Verdict = "WA"
+	VerdictTimeLimit    Verdict = "TLE"
+	VerdictRuntimeError Verdict = "RE"
+	VerdictMemoryLimit  Verdict = "MLE"
+	VerdictSkipped      Verdict = "SKIP"
+	VerdictSanitizer    Verdict = "SAN" // a -checks sanitizer reported a memory error
+)
+
+// Description returns the long form of a verdict
+func (v Verdict) Description() string {
+	switch v {
+	case VerdictAccepted:
+		return "Accepted"
+	case VerdictWrongAnswer:
+		return "Wrong answer"
+	case VerdictTimeLimit:
+		return "Time limit exceeded"
+	case VerdictRuntimeError:
+		return "Runtime error"
+	case VerdictMemoryLimit:
+		return "Memory limit exceeded"
+	case VerdictSkipped:
+		return "Skipped"
+	case VerdictSanitizer:
+		return "Sanitizer error"
+	}
+	return string(v)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed web/*.html
+var webTemplates embed.FS
+
+// DashboardServer hosts the local web UI on top of the run history
+type DashboardServer struct {
+	config    *Config
+	history   *HistoryStore
+	templates *template.Template
+}
+
+type problemStatus struct {
+	ProblemID string
+	Latest    *RunRecord
+	Runs      int
+	EverAllOK bool
+}
+
+type timingBar struct {
+	Test    TestRecord
+	Percent float64
+}
+
+// parseWebTemplates parses the embedded HTML templates shared by the dashboard and reports
+func parseWebTemplates() (*template.Template, error) {
+	funcs := template.FuncMap{
+		"formatTime": func(t time.Time) string { return t.Local().Format("2006-01-02 15:04:05") },
+		"ms":         func(v float64) string { return fmt.Sprintf("%.2fms", v) },
+		"bytes":      formatBytes,
+		"diff":       lineDiff,
+	}
+
+	templates, err := template.New("").Funcs(funcs).ParseFS(webTemplates, "web/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// NewDashboardServer parses the embedded templates and creates the server
+func NewDashboardServer(config *Config) (*DashboardServer, error) {
+	templates, err := parseWebTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DashboardServer{
+		config:    config,
+		history:   NewHistoryStore(config),
+		templates: templates,
+	}, nil
+}
+
+// ListenAndServe serves the dashboard until the process is interrupted
+func (d *DashboardServer) ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", d.handleIndex)
+	mux.HandleFunc("/run/", d.handleRun)
+
+	green.Printf("🌐 Dashboard running at http://%s\n", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+func (d *DashboardServer) handleIndex(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/" {
+		http.NotFound(w, req)
+		return
+	}
+
+	records, err := d.history.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	statuses := make(map[string]*problemStatus)
+	for _, record := range records {
+		status, exists := statuses[record.ProblemID]
+		if !exists {
+			// Records are newest first, so the first one seen is the latest
+			status = &problemStatus{ProblemID: record.ProblemID, Latest: record}
+			statuses[record.ProblemID] = status
+		}
+		status.Runs++
+		if record.AllPassed() {
+			status.EverAllOK = true
+		}
+	}
+
+	var problems []*problemStatus
+	for _, status := range statuses {
+		problems = append(problems, status)
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].Latest.StartedAt.After(problems[j].Latest.StartedAt)
+	})
+
+	d.render(w, "index.html", map[string]interface{}{
+		"Problems": problems,
+		"Runs":     records,
+	})
+}
+
+func (d *DashboardServer) handleRun(w http.ResponseWriter, req *http.Request) {
+	id := strings.TrimPrefix(req.URL.Path, "/run/")
+	record, err := d.history.Load(id)
+	if err != nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	var slowest float64
+	for _, test := range record.Tests {
+		if test.Duration > slowest {
+			slowest = test.Duration
+		}
+	}
+
+	var bars []timingBar
+	var failed []TestRecord
+	for _, test := range record.Tests {
+		percent := 0.0
+		if slowest > 0 {
+			percent = test.Duration / slowest * 100
+		}
+		bars = append(bars, timingBar{Test: test, Percent: percent})
+
+		if !test.Passed {
+			failed = append(failed, test)
+		}
+	}
+
+	d.render(w, "run.html", map[string]interface{}{
+		"Run":    record,
+		"Bars":   bars,
+		"Failed": failed,
+	})
+}
+
+func (d *DashboardServer) render(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := d.templates.ExecuteTemplate(w, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// homeDir returns the current user's home directory, or "" if it is unknown
+func homeDir() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		return home
+	}
+	if currentUser, err := user.Current(); err == nil {
+		return currentUser.HomeDir
+	}
+	return ""
+}
+
+// xdgDir returns <$env>/cses-go-runner, falling back to <home>/<fallback>/cses-go-runner
+// when the variable is unset or not absolute, as the XDG base directory spec requires
+func xdgDir(env, fallback string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, AppName)
+	}
+	return filepath.Join(homeDir(), fallback, AppName)
+}
+
+// defaultCacheDir holds what can be re-created: tests, binaries, sessions, history
+func defaultCacheDir() string {
+	return xdgDir("XDG_CACHE_HOME", ".cache")
+}
+
+// defaultConfigDir holds what the user wrote: templates and lifecycle hooks
+func defaultConfigDir() string {
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// legacyCacheDir is where everything, configuration included, used to be kept
+func legacyCacheDir() string {
+	return filepath.Join(homeDir(), ".cache", AppName)
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		return homeDir() + path[1:]
+	}
+	return path
+}
+
+// resolveDirs fills in the default cache and config directories and creates them.
+// On first use of the defaults, the legacy cache is moved to the XDG cache
+// directory and the configuration kept in it is moved to the config directory.
+func resolveDirs(config *Config) {
+	defaultCache, defaultConfig := config.CacheDir == "", config.ConfigDir == ""
+	if defaultCache {
+		config.CacheDir = defaultCacheDir()
+	}
+	if defaultConfig {
+		config.ConfigDir = defaultConfigDir()
+	}
+	config.CacheDir = filepath.Clean(expandHome(config.CacheDir))
+	config.ConfigDir = filepath.Clean(expandHome(config.ConfigDir))
+
+	if defaultCache {
+		migrateLegacyCache(config.CacheDir)
+	}
+	if defaultCache && defaultConfig {
+		migrateLegacyConfig(config)
+	}
+
+	os.MkdirAll(config.CacheDir, 0755)
+	os.MkdirAll(config.ConfigDir, 0755)
+}
+
+// migrateLegacyCache moves ~/.cache/cses-go-runner to cacheDir when
+// XDG_CACHE_HOME points elsewhere and cacheDir does not exist yet
+func migrateLegacyCache(cacheDir string) {
+	legacy := legacyCacheDir()
+	if legacy == cacheDir {
+		return
+	}
+	if _, err := os.Stat(legacy); err != nil {
+		return
+	}
+	if _, err := os.Stat(cacheDir); err == nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheDir), 0755); err != nil {
+		yellow.Printf("⚠️  Failed to move %s to %s: %v\n", legacy, cacheDir, err)
+		return
+	}
+	if err := os.Rename(legacy, cacheDir); err != nil {
+		yellow.Printf("⚠️  Failed to move %s to %s: %v\n", legacy, cacheDir, err)
+		return
+	}
+	cyan.Printf("📦 Moved the cache from %s to %s\n", legacy, cacheDir)
+}
+
+// migrateLegacyConfig moves templates and hooks.yaml out of the cache into
+// the config directory, leaving anything already in the config directory alone
+func migrateLegacyConfig(config *Config) {
+	moves := map[string]string{
+		filepath.Join(config.CacheDir, "templates"):  config.GetTemplatesDir(),
+		filepath.Join(config.CacheDir, "hooks.yaml"): filepath.Join(config.ConfigDir, "hooks.yaml"),
+	}
+
+	for from, to := range moves {
+		if _, err := os.Stat(from); err != nil {
+			continue
+		}
+		if _, err := os.Stat(to); err == nil {
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+			yellow.Printf("⚠️  Failed to move %s to %s: %v\n", from, to, err)
+			continue
+		}
+		if err := os.Rename(from, to); err != nil {
+			yellow.Printf("⚠️  Failed to move %s to %s: %v\n", from, to, err)
+			continue
+		}
+		cyan.Printf("📦 Moved %s to %s\n", from, to)
+	}
+}
+
+// handleClean implements `clean [cache|config]`; without an argument it
+// removes the cache, which only holds data that can be fetched or built again
+func handleClean(config *Config, args []string) error {
+	target := "cache"
+	if len(args) > 0 {
+		target = args[0]
+	}
+
+	var dir string
+	switch target {
+	case "cache":
+		dir = config.CacheDir
+	case "config":
+		dir = config.ConfigDir
+	default:
+		return withExitCode(ExitUsageError, fmt.Errorf("usage: clean [cache|config]"))
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clean %s: %w", target, err)
+	}
+	green.Printf("🧹 Removed the %s directory %s\n", target, dir)
+	return nil
+}